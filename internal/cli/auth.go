@@ -1,16 +1,32 @@
 package cli
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
 	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/llm"
+)
+
+var (
+	flagWithToken         bool
+	flagClientID          string
+	flagScopes            []string
+	flagAppID             string
+	flagAppInstallationID int64
+	flagAppPrivateKey     string
+	flagLLMProvider       string
+	flagDelete            bool
 )
 
 var authCmd = &cobra.Command{
@@ -20,8 +36,25 @@ var authCmd = &cobra.Command{
 
 var authLoginCmd = &cobra.Command{
 	Use:   "login",
-	Short: "Store GitHub PAT for the current project",
-	RunE:  runAuthLogin,
+	Short: "Sign in to GitHub for the current project",
+	Long: `Sign in to GitHub for the current project.
+
+By default grit uses the OAuth device flow: it shows a one-time code, opens
+the verification page in a browser and waits for you to authorize it. The
+resulting token is stored in the system keyring.
+
+With --with-token, a personal access token is read from standard input
+instead, for scripts:
+
+  echo "$TOKEN" | grit auth login --with-token
+
+With --app-id, configure the project to authenticate as a GitHub App
+installation instead: the app ID is saved to .grit/config.yaml and the
+private key given with --private-key is stored in the system keyring.
+
+Where no system keyring is available, secrets are kept in an encrypted file
+in the user config directory instead.`,
+	RunE: runAuthLogin,
 }
 
 var authStatusCmd = &cobra.Command{
@@ -30,62 +63,346 @@ var authStatusCmd = &cobra.Command{
 	RunE:  runAuthStatus,
 }
 
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored GitHub token for the current project",
+	Long: `Remove the GitHub token stored for the current project from the system
+keyring and the encrypted secret file. When the project authenticates as a
+GitHub App, the app's stored private key is removed as well.
+
+Tokens grit reads from other tools, such as GH_TOKEN or the gh CLI, are not
+touched.`,
+	Args: cobra.NoArgs,
+	RunE: runAuthLogout,
+}
+
+var authLLMCmd = &cobra.Command{
+	Use:   "llm",
+	Short: "Store or delete the API key for the LLM provider",
+	Long: `Store the API key for the configured LLM provider, or another provider
+given with --provider. With --delete, remove the stored key instead.`,
+	Args: cobra.NoArgs,
+	RunE: runAuthLLM,
+}
+
 func init() {
 	rootCmd.AddCommand(authCmd)
 	authCmd.AddCommand(authLoginCmd)
 	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authLLMCmd)
+
+	authLLMCmd.Flags().StringVar(&flagLLMProvider, "provider", "", "LLM provider (default: the configured provider)")
+	authLLMCmd.Flags().BoolVar(&flagDelete, "delete", false, "Delete the stored API key")
+
+	config.PassphrasePrompt = promptPassphrase
+
+	authLoginCmd.Flags().BoolVar(&flagWithToken, "with-token", false, "Read a personal access token from standard input")
+	authLoginCmd.Flags().StringVar(&flagClientID, "client-id", "", "OAuth app client ID for the device flow")
+	authLoginCmd.Flags().StringSliceVar(&flagScopes, "scopes", []string{"repo"}, "OAuth scopes to request")
+	authLoginCmd.Flags().StringVar(&flagAppID, "app-id", "", "Authenticate as the GitHub App with this ID")
+	authLoginCmd.Flags().Int64Var(&flagAppInstallationID, "installation-id", 0, "GitHub App installation ID (default: the app's installation on the repository)")
+	authLoginCmd.Flags().StringVar(&flagAppPrivateKey, "private-key", "", "Path to the GitHub App's PEM private key")
 }
 
 func runAuthLogin(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadFromWorkingDir()
+	if flagAppID != "" {
+		return runAuthLoginApp()
+	}
+
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
 	projectKey := config.ProjectKey(cfg)
 
-	fmt.Printf("Enter GitHub PAT for %s: ", projectKey)
-
-	tokenBytes, err := term.ReadPassword(int(syscall.Stdin))
+	var token string
+	clientID := oauthClientID(cfg)
+	switch {
+	case flagWithToken:
+		token, err = readToken(cfg, projectKey)
+	case clientID == "":
+		fmt.Fprintf(os.Stderr, "No OAuth app is configured for %s, so signing in with the browser is not available.\n", cfg.Project.HostName())
+		fmt.Fprintln(os.Stderr, "Set oauth_client_id in .grit/config.yaml or GRIT_OAUTH_CLIENT_ID to use it; using a personal access token instead (--with-token).")
+		token, err = readToken(cfg, projectKey)
+	default:
+		token, err = deviceLogin(cmd.Context(), cfg, clientID)
+	}
 	if err != nil {
-		reader := bufio.NewReader(os.Stdin)
-		token, err := reader.ReadString('\n')
+		return err
+	}
+
+	if err := (&config.SecretTokenStore{}).Set(projectKey, token); err != nil {
+		return err
+	}
+
+	fmt.Printf("Token stored for %s in the %s\n", projectKey, secretBackendName())
+	return nil
+}
+
+// readToken reads a personal access token from standard input, prompting
+// for it without echo when standard input is a terminal.
+func readToken(cfg *config.Config, projectKey string) (string, error) {
+	var token string
+	if term.IsTerminal(int(syscall.Stdin)) {
+		fmt.Printf("Create a token at %s/settings/tokens\n", cfg.Project.WebURL())
+		fmt.Printf("Enter GitHub PAT for %s: ", projectKey)
+
+		tokenBytes, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("reading token: %w", err)
+		}
+		token = string(tokenBytes)
+	} else {
+		data, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			return fmt.Errorf("reading token: %w", err)
+			return "", fmt.Errorf("reading token: %w", err)
 		}
-		tokenBytes = []byte(strings.TrimSpace(token))
+		token = string(data)
 	}
-	fmt.Println()
 
-	token := strings.TrimSpace(string(tokenBytes))
+	token = strings.TrimSpace(token)
 	if token == "" {
-		return fmt.Errorf("token cannot be empty")
+		return "", fmt.Errorf("token cannot be empty")
+	}
+	return token, nil
+}
+
+// deviceLogin signs in with the OAuth device flow, using the OAuth app
+// clientID, and returns the token.
+func deviceLogin(ctx context.Context, cfg *config.Config, clientID string) (string, error) {
+	flow := github.NewDeviceFlow(cfg.Project.WebURL(), clientID, flagScopes)
+	code, err := flow.RequestCode(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("First copy your one-time code: %s\n", code.UserCode)
+	fmt.Printf("Then open %s in your browser and enter it.\n", code.VerificationURI)
+	openInBrowser(code.VerificationURI)
+	fmt.Println("Waiting for authorization...")
+
+	token, err := flow.PollToken(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	if token.Scope != "" {
+		fmt.Printf("Authorized with scopes: %s\n", strings.ReplaceAll(token.Scope, ",", ", "))
 	}
+	return token.AccessToken, nil
+}
+
+// oauthClientID returns the OAuth app for the project's host: --client-id,
+// GRIT_OAUTH_CLIENT_ID, the project's oauth_client_id, and on github.com
+// the app built into grit.
+func oauthClientID(cfg *config.Config) string {
+	switch {
+	case flagClientID != "":
+		return flagClientID
+	case os.Getenv("GRIT_OAUTH_CLIENT_ID") != "":
+		return os.Getenv("GRIT_OAUTH_CLIENT_ID")
+	case cfg.Project.OAuthClientID != "":
+		return cfg.Project.OAuthClientID
+	case !cfg.Project.IsEnterprise():
+		return OAuthClientID
+	}
+	return ""
+}
 
-	store := config.NewCompositeTokenStore()
-	if err := store.Set(projectKey, token); err != nil {
+// runAuthLoginApp saves GitHub App settings to the project configuration and
+// stores the app's private key in the keyring.
+func runAuthLoginApp() error {
+	if flagAppPrivateKey == "" {
+		return fmt.Errorf("--private-key is required with --app-id")
+	}
+	if flagRepo != "" {
+		return fmt.Errorf("--repo cannot be used with --app-id; GitHub App settings are saved to the current project")
+	}
+
+	key, err := os.ReadFile(flagAppPrivateKey)
+	if err != nil {
+		return fmt.Errorf("reading private key: %w", err)
+	}
+	if _, err := github.ParseAppPrivateKey(key); err != nil {
 		return err
 	}
 
-	fmt.Printf("Token stored for %s\n", projectKey)
+	root, err := config.FindRoot()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(root)
+	if err != nil {
+		return err
+	}
+
+	app := &config.AppConfig{ID: flagAppID, InstallationID: flagAppInstallationID}
+	if err := (&config.SecretTokenStore{}).Set(config.AppKey(app), string(key)); err != nil {
+		return err
+	}
+
+	cfg.App = app
+	if err := config.Save(root, cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("GitHub App %s configured for %s; private key stored in the %s\n", app.ID, config.ProjectKey(cfg), secretBackendName())
 	return nil
 }
 
-func runAuthStatus(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadFromWorkingDir()
+func runAuthLogout(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
 	projectKey := config.ProjectKey(cfg)
-	store := config.NewCompositeTokenStore()
+	removed, err := config.DeleteToken(projectKey)
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		fmt.Printf("No token stored for %s\n", projectKey)
+	} else {
+		fmt.Printf("Removed token for %s from %s\n", projectKey, strings.Join(removed, " and "))
+	}
 
-	token, err := store.Get(projectKey)
+	app, err := config.AppAuth(cfg)
+	if err != nil {
+		return err
+	}
+	if app != nil {
+		removed, err := config.DeleteAppPrivateKey(app)
+		if err != nil {
+			return err
+		}
+		if len(removed) > 0 {
+			fmt.Printf("Removed private key for GitHub App %s from %s\n", app.ID, strings.Join(removed, " and "))
+		}
+		return nil
+	}
+
+	if _, source, err := lookupToken(cfg); err == nil {
+		fmt.Printf("A token is still available from %s; see auth.token_sources in the configuration docs\n", source)
+	}
+	return nil
+}
+
+func runAuthLLM(cmd *cobra.Command, args []string) error {
+	provider := flagLLMProvider
+	if provider == "" {
+		cfg, err := loadConfig()
+		if errors.Is(err, config.ErrNoProject) {
+			cfg, err = config.LoadUser()
+		}
+		if err != nil {
+			return err
+		}
+		provider = cfg.LLM.Provider
+	}
+
+	info := llm.ProviderByName(provider)
+	switch {
+	case info == nil:
+		return fmt.Errorf("unknown LLM provider %q", provider)
+	case info.Name == "none":
+		return fmt.Errorf("no LLM provider is configured; pass --provider")
+	case !info.RequiresKey && !flagDelete:
+		return fmt.Errorf("%s does not need an API key", info.Name)
+	}
+
+	if flagDelete {
+		removed, err := config.DeleteLLMKey(info.Name)
+		if err != nil {
+			return err
+		}
+		if len(removed) == 0 {
+			fmt.Printf("No API key stored for %s\n", info.Name)
+		} else {
+			fmt.Printf("Removed API key for %s from %s\n", info.Name, strings.Join(removed, " and "))
+		}
+		return nil
+	}
+
+	key, err := promptAPIKey(info.Name)
+	if err != nil {
+		return err
+	}
+	if err := config.SetLLMKey(info.Name, key); err != nil {
+		return err
+	}
+	fmt.Printf("API key stored for %s in the %s\n", info.Name, secretBackendName())
+	return nil
+}
+
+// promptPassphrase asks for the passphrase of the encrypted secret file,
+// confirming it when the file is being created.
+func promptPassphrase(create bool) (string, error) {
+	if !term.IsTerminal(int(syscall.Stdin)) {
+		return "", fmt.Errorf("no system keyring is available and the secret file needs a passphrase; set GRIT_SECRETS_PASSPHRASE or GRIT_SECRETS_KEY_FILE")
+	}
+
+	if create {
+		fmt.Fprintln(os.Stderr, "No system keyring is available; secrets will be stored in an encrypted file.")
+	}
+	passphrase, err := readPassphrase("Secret file passphrase: ")
+	if err != nil || !create {
+		return passphrase, err
+	}
+
+	confirm, err := readPassphrase("Confirm passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != passphrase {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+func readPassphrase(label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	data, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase: %w", err)
+	}
+	return string(data), nil
+}
+
+// secretBackendName describes where secrets are saved.
+func secretBackendName() string {
+	if config.SecretBackend() == config.BackendFile {
+		return "encrypted secret file"
+	}
+	return "system keyring"
+}
+
+func runAuthStatus(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	app, err := config.AppAuth(cfg)
+	if err != nil {
+		return err
+	}
+
+	var token, source string
+	var ts *github.AppTokenSource
+	if app != nil {
+		_, ts, err = githubCredentials(cfg)
+		source = "GitHub App"
+	} else {
+		token, source, err = lookupToken(cfg)
+	}
 	if err != nil {
 		fmt.Printf("GitHub: not authenticated (%v)\n", err)
 	} else {
-		maskedToken := token[:4] + "..." + token[len(token)-4:]
-		fmt.Printf("GitHub: authenticated (%s)\n", maskedToken)
+		printGitHubStatus(cmd.Context(), cfg, app, token, source, ts)
 	}
 
 	if cfg.LLM.Provider == "none" {
@@ -99,3 +416,129 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// printGitHubStatus checks the token against the API and reports who it
+// authenticates as, what it may do on the project's repository, and any
+// missing permissions.
+func printGitHubStatus(ctx context.Context, cfg *config.Config, app *config.AppConfig, token, source string, ts *github.AppTokenSource) {
+	client := newGitHubClient(cfg, token, ts)
+	repo := cfg.Project.FullName()
+
+	var info *github.TokenInfo
+	if ts != nil {
+		var err error
+		if token, err = ts.Token(ctx); err != nil {
+			fmt.Printf("GitHub: GitHub App %s cannot authenticate (%v)\n", app.ID, err)
+			return
+		}
+		fmt.Printf("GitHub: authenticated as GitHub App %s (installation %d)\n", app.ID, ts.InstallationID())
+	} else {
+		var err error
+		info, err = client.GetTokenInfo(ctx)
+		if err != nil {
+			var unauthorized *github.UnauthorizedError
+			if errors.As(err, &unauthorized) {
+				fmt.Printf("GitHub: token rejected (%s): it is invalid, expired or revoked\n", maskToken(token))
+				fmt.Println("  Run 'grit auth login' to sign in again")
+				return
+			}
+			fmt.Printf("GitHub: could not verify token (%s): %v\n", maskToken(token), err)
+			return
+		}
+		fmt.Printf("GitHub: authenticated as %s\n", info.Login)
+	}
+
+	kind := github.TokenType(token)
+	fmt.Printf("  Token:   %s (%s) from %s\n", kind, maskToken(token), source)
+
+	var warnings []string
+	if info != nil && info.Scopes != nil {
+		scopes := "none"
+		if len(info.Scopes) > 0 {
+			scopes = strings.Join(info.Scopes, ", ")
+		}
+		fmt.Printf("  Scopes:  %s\n", scopes)
+		if len(cfg.Projects) > 0 && !info.HasScope("project") {
+			warnings = append(warnings, "the token lacks the project scope, so project board updates will fail; run 'grit auth login --scopes repo,project'")
+		}
+	}
+
+	switch {
+	case ts != nil:
+		fmt.Printf("  Expires: %s (refreshed automatically)\n", ts.Expiry().Local().Format("2006-01-02 15:04"))
+	case info.Expiry.IsZero():
+		fmt.Println("  Expires: never")
+	default:
+		fmt.Printf("  Expires: %s\n", info.Expiry.Local().Format("2006-01-02 15:04"))
+		if left := time.Until(info.Expiry); left < 7*24*time.Hour {
+			warnings = append(warnings, fmt.Sprintf("the token expires in %d day(s); create a new one before then", int(left.Hours()/24)))
+		}
+	}
+
+	warnings = append(warnings, checkRepoAccess(ctx, client, info, kind, repo)...)
+	for _, w := range warnings {
+		fmt.Printf("  Warning: %s\n", w)
+	}
+}
+
+// checkRepoAccess reports whether the token can write issues on repo and
+// returns warnings for anything that stops it.
+func checkRepoAccess(ctx context.Context, client github.AuthClient, info *github.TokenInfo, kind, repo string) []string {
+	r, err := client.GetRepository(ctx)
+	if err != nil {
+		var notFound *github.NotFoundError
+		if errors.As(err, &notFound) {
+			fmt.Printf("  Access:  cannot see %s\n", repo)
+			return []string{fmt.Sprintf("%s does not exist or the token has no access to it", repo)}
+		}
+		fmt.Printf("  Access:  unknown (%v)\n", err)
+		return nil
+	}
+
+	var warnings []string
+	if info != nil && info.Scopes != nil {
+		switch {
+		case r.Private && !info.HasScope("repo"):
+			warnings = append(warnings, fmt.Sprintf("the token lacks the repo scope needed to write issues on the private repository %s", repo))
+		case !r.Private && !info.HasScope("public_repo"):
+			warnings = append(warnings, "the token lacks the repo or public_repo scope needed to write issues")
+		}
+	}
+	if !r.HasIssues {
+		fmt.Printf("  Access:  issues are disabled on %s\n", repo)
+		return append(warnings, fmt.Sprintf("issues are disabled on %s", repo))
+	}
+	if r.Archived {
+		fmt.Printf("  Access:  read-only (%s is archived)\n", repo)
+		return append(warnings, fmt.Sprintf("%s is archived, so its issues cannot be changed", repo))
+	}
+
+	// The API reports the user's role on the repository, not what a
+	// fine-grained token or an app was granted, so those are only hinted
+	// at.
+	switch p := r.Permissions; {
+	case p == nil:
+		fmt.Printf("  Access:  set by the app's Issues permission on %s\n", repo)
+	case p.CanTriage():
+		fmt.Printf("  Access:  can write issues on %s\n", repo)
+	default:
+		fmt.Printf("  Access:  read-only on %s (can open and comment on issues)\n", repo)
+		warnings = append(warnings, fmt.Sprintf("you have read access to %s; editing labels, assignees and other people's issues needs triage access", repo))
+	}
+	switch kind {
+	case github.TokenFineGrained:
+		fmt.Printf("           the token also needs the Issues: Read and write permission on %s\n", repo)
+	case github.TokenApp:
+		fmt.Println("           the app needs the Issues: Read and write permission")
+	}
+	return warnings
+}
+
+// maskToken shows the start and end of a token, hiding short tokens
+// entirely.
+func maskToken(token string) string {
+	if len(token) < 12 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:4] + "..." + token[len(token)-4:]
+}
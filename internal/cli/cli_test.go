@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/github/githubtest"
+)
+
+// newProject serves n issues in acme/widgets and changes into a grit
+// project for it, with a token in the environment and the user's
+// directories kept out of the way.
+func newProject(t *testing.T, n int) *githubtest.Server {
+	t.Helper()
+	srv := githubtest.NewServer()
+	t.Cleanup(srv.Close)
+	repo := srv.Repo("acme", "widgets")
+	for i := 1; i <= n; i++ {
+		repo.AddIssue(github.Issue{Title: fmt.Sprintf("issue %d", i)})
+	}
+
+	dir := t.TempDir()
+	config := fmt.Sprintf("version: 1\nproject:\n  host: %s\n  owner: acme\n  repo: widgets\nllm:\n  provider: none\n", srv.URL)
+	if err := os.MkdirAll(filepath.Join(dir, ".grit"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".grit", "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	t.Setenv("GRIT_PAT", "token")
+	t.Setenv(envOffline, "")
+	return srv
+}
+
+// run runs grit with args, with nothing on stdin, and returns what it
+// printed to stdout.
+func run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	// Flags keep their values between runs, so the command's flags are set
+	// back to their defaults first.
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fs := range []*pflag.FlagSet{cmd.LocalFlags(), cmd.InheritedFlags()} {
+		fs.VisitAll(func(f *pflag.Flag) {
+			if s, ok := f.Value.(pflag.SliceValue); ok {
+				_ = s.Replace(nil)
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	}
+	activeGitHubClient = nil
+
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdin, w
+	defer func() { os.Stdin, os.Stdout = oldStdin, oldStdout }()
+
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+
+	rootCmd.SetArgs(args)
+	rootCmd.SetErr(io.Discard)
+	err = rootCmd.Execute()
+	w.Close()
+	return <-out, err
+}
+
+func TestIssueList(t *testing.T) {
+	newProject(t, 12)
+
+	out, err := run(t, "issue", "list", "--limit", "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "#12 ") || !strings.Contains(out, "#8 ") || strings.Contains(out, "#7 ") {
+		t.Errorf("first page:\n%s\nwant #12 to #8", out)
+	}
+	if !strings.Contains(out, "Page 1 · [n]ext · [q]uit") {
+		t.Errorf("first page:\n%s\nwant a prompt for the next page", out)
+	}
+
+	out, err = run(t, "issue", "list", "--limit", "5", "--page", "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "[n]ext") {
+		t.Errorf("last page:\n%s\nwant no next page", out)
+	}
+}
+
+func TestIssueListAll(t *testing.T) {
+	// More issues than fit on one page of 100, so --all has to follow the
+	// Link headers.
+	srv := newProject(t, 250)
+
+	out, err := run(t, "issue", "list", "--all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listed int
+	for line := range strings.Lines(out) {
+		if strings.HasPrefix(line, "#") {
+			listed++
+		}
+	}
+	if listed != 250 {
+		t.Errorf("listed %d issues, want 250", listed)
+	}
+
+	var pages int
+	for _, r := range srv.Requests() {
+		if r.Path == "/repos/acme/widgets/issues" {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Errorf("fetched %d pages, want 3", pages)
+	}
+}
+
+func TestIssueViewNotFound(t *testing.T) {
+	newProject(t, 1)
+
+	_, err := run(t, "issue", "view", "9")
+	var notFound *github.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("err = %v, want a NotFoundError", err)
+	}
+}
+
+func TestIssueViewRetriesRateLimit(t *testing.T) {
+	srv := newProject(t, 1)
+	srv.Fail(githubtest.SecondaryRateLimit("GET", "/repos/*/*/issues/1", 0, 1))
+
+	out, err := run(t, "issue", "view", "1")
+	if err != nil {
+		t.Fatalf("err = %v, want the request retried", err)
+	}
+	if !strings.Contains(out, "issue 1") {
+		t.Errorf("output:\n%s\nwant the issue", out)
+	}
+}
+
+func TestOfflineCloseAndSync(t *testing.T) {
+	srv := newProject(t, 2)
+	repo := srv.Repo("acme", "widgets")
+
+	if _, err := run(t, "sync"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, "--offline", "issue", "close", "1", "fixed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Closed issue #1") {
+		t.Errorf("close output:\n%s", out)
+	}
+	if issue, _ := repo.Issue(1); issue.State != "open" {
+		t.Fatalf("state on GitHub = %s, want the close queued", issue.State)
+	}
+
+	out, err = run(t, "sync", "--pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Waiting to sync:") || strings.Count(out, "#1") != 2 {
+		t.Errorf("pending:\n%s\nwant the comment and the close on #1", out)
+	}
+
+	out, err = run(t, "sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Sent to GitHub:") || !strings.Contains(out, "Synced acme/widgets") {
+		t.Errorf("sync output:\n%s", out)
+	}
+	if issue, _ := repo.Issue(1); issue.State != "closed" {
+		t.Errorf("state on GitHub = %s, want closed", issue.State)
+	}
+	if comments := repo.Comments(1); len(comments) != 1 || comments[0].Body != "fixed" {
+		t.Errorf("comments on GitHub = %+v, want the closing comment", comments)
+	}
+}
+
+func TestIssueReferencesAcrossRepositories(t *testing.T) {
+	srv := newProject(t, 2)
+	repo := srv.Repo("acme", "widgets")
+	srv.Repo("acme", "api").AddIssue(github.Issue{Title: "canonical"})
+
+	if _, err := run(t, "issue", "link", "2", "acme/api#1", "--comment"); err != nil {
+		t.Fatal(err)
+	}
+	if comments := repo.Comments(2); len(comments) != 1 || comments[0].Body != "Related to acme/api#1" {
+		t.Errorf("comments on #2 = %+v, want a link to acme/api#1", comments)
+	}
+
+	out, err := run(t, "issue", "close", "1", "--duplicate-of", "acme/api#1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "as a duplicate of acme/api#1") {
+		t.Errorf("close output:\n%s", out)
+	}
+	if issue, _ := repo.Issue(1); issue.State != "closed" {
+		t.Errorf("#1 is %s, want closed", issue.State)
+	}
+	if comments := repo.Comments(1); len(comments) != 1 || comments[0].Body != "Duplicate of acme/api#1" {
+		t.Errorf("comments on #1 = %+v, want a duplicate reference to acme/api#1", comments)
+	}
+
+	if _, err := run(t, "issue", "close", "2", "--duplicate-of", "acme/api#7"); err == nil {
+		t.Error("closing as a duplicate of a missing issue: err = nil")
+	}
+}
+
+func TestOfflineDraft(t *testing.T) {
+	srv := newProject(t, 2)
+	if _, err := run(t, "sync"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, "--offline", "issue", "create", "--title", "offline", "--raw", "--yes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Created draft-1") {
+		t.Fatalf("create output:\n%s", out)
+	}
+	if out, err := run(t, "--offline", "issue", "view", "draft-1"); err != nil || !strings.Contains(out, "draft-1 offline") {
+		t.Errorf("view draft-1: %v\n%s", err, out)
+	}
+
+	srv.Repo("acme", "widgets").AddIssue(github.Issue{Title: "elsewhere"})
+	out, err = run(t, "sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `create draft-1 "offline" as #4`) {
+		t.Errorf("sync output:\n%s", out)
+	}
+}
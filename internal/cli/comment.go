@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/service"
+)
+
+var commentEditCmd = &cobra.Command{
+	Use:   "edit <comment-id> <body>",
+	Short: "Replace the text of a comment",
+	Long:  "Replace the text of an existing comment. Comment IDs are shown by 'grit issue view --comments'.",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runCommentEdit,
+}
+
+var commentDeleteCmd = &cobra.Command{
+	Use:   "delete <comment-id>",
+	Short: "Delete a comment",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCommentDelete,
+}
+
+func init() {
+	issueCommentCmd.AddCommand(commentEditCmd)
+	issueCommentCmd.AddCommand(commentDeleteCmd)
+
+	commentDeleteCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Skip confirmation")
+}
+
+func parseCommentID(arg string) (int64, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid comment id: %s", arg)
+	}
+	return id, nil
+}
+
+func runCommentEdit(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	id, err := parseCommentID(args[0])
+	if err != nil {
+		return err
+	}
+
+	body := strings.Join(args[1:], " ")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	comment, err := svc.UpdateComment(ctx, id, body)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated comment: %s\n", comment.HTMLURL)
+	return nil
+}
+
+func runCommentDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	id, err := parseCommentID(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	if !flagYes {
+		if !confirmAction(fmt.Sprintf("Delete comment %d?", id)) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	if err := svc.DeleteComment(ctx, id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted comment %d\n", id)
+	return nil
+}
+
+func printComments(comments []github.IssueComment) {
+	fmt.Printf("Comments (%d)\n", len(comments))
+	fmt.Println(strings.Repeat("─", 60))
+	for i, c := range comments {
+		if i > 0 {
+			fmt.Println()
+		}
+		header := fmt.Sprintf("@%s · %s · id %d", c.User.Login, c.CreatedAt.Format("2006-01-02 15:04"), c.ID)
+		if c.UpdatedAt.After(c.CreatedAt) {
+			header += " · edited"
+		}
+		fmt.Println(header)
+		fmt.Println(c.Body)
+	}
+	fmt.Println(strings.Repeat("─", 60))
+}
@@ -5,8 +5,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
-
-	"github.com/dulait/grit/internal/config"
 )
 
 var configCmd = &cobra.Command{
@@ -26,7 +24,7 @@ func init() {
 }
 
 func runConfigShow(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadFromWorkingDir()
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
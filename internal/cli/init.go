@@ -13,17 +13,44 @@ import (
 
 	"github.com/dulait/grit/internal/config"
 	"github.com/dulait/grit/internal/llm"
+	"github.com/dulait/grit/internal/service"
+)
+
+var (
+	flagInitHost         string
+	flagInitOwner        string
+	flagInitRepo         string
+	flagInitRemote       string
+	flagInitProvider     string
+	flagInitModel        string
+	flagInitBaseURL      string
+	flagInitImportLabels bool
 )
 
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize grit in the current directory",
-	Long:  "Creates a .grit directory with configuration for connecting to a GitHub repository.",
-	RunE:  runInit,
+	Long: `Creates a .grit directory with configuration for connecting to a GitHub repository.
+
+Inside a git clone, the host, owner and repository are read from the git
+remotes and offered as defaults. Passing --owner, --repo or --provider runs
+init without prompting, for use in scripts and CI; values that are not given
+come from the git remote and the provider's defaults.`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().StringVar(&flagInitHost, "host", "", "GitHub host, for GitHub Enterprise Server")
+	initCmd.Flags().StringVar(&flagInitOwner, "owner", "", "Repository owner (user or org)")
+	initCmd.Flags().StringVar(&flagInitRepo, "repo", "", "Repository name")
+	initCmd.Flags().StringVar(&flagInitRemote, "remote", "", "Git remote to read the repository from")
+	initCmd.Flags().StringVar(&flagInitProvider, "provider", "", "LLM provider: none, groq, ollama, anthropic")
+	initCmd.Flags().StringVar(&flagInitModel, "model", "", "LLM model (default: the provider's default)")
+	initCmd.Flags().StringVar(&flagInitBaseURL, "base-url", "", "Ollama base URL")
+	initCmd.Flags().BoolVar(&flagInitImportLabels, "import-labels", false, "Import the repository's labels without prompting")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -36,21 +63,163 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("grit already initialized in this directory")
 	}
 
-	reader := bufio.NewReader(os.Stdin)
-
-	owner, err := prompt(reader, "GitHub owner (user or org)")
+	remotes, err := config.GitRemotes(cwd)
 	if err != nil {
+		fmt.Printf("Warning: could not read git remotes: %v\n", err)
+	}
+
+	var cfg *config.Config
+	if initNonInteractive(cmd) {
+		cfg, err = initFromFlags(remotes)
+		if err != nil {
+			return err
+		}
+		if flagInitImportLabels {
+			importInitLabels(cmd, cfg)
+		}
+	} else {
+		reader := bufio.NewReader(os.Stdin)
+		cfg, err = initInteractive(reader, remotes)
+		if err != nil {
+			return err
+		}
+		offerLabelImport(cmd, reader, cfg)
+	}
+
+	if err := config.Save(cwd, cfg); err != nil {
 		return err
 	}
 
-	repo, err := prompt(reader, "Repository name")
+	if err := config.WriteGitignore(cwd); err != nil {
+		return fmt.Errorf("creating .gitignore: %w", err)
+	}
+
+	if cfg.LLM.Provider == "none" {
+		fmt.Printf("Initialized grit for %s (no LLM)\n", cfg.Project.FullName())
+	} else {
+		fmt.Printf("Initialized grit for %s using %s (%s)\n", cfg.Project.FullName(), cfg.LLM.Provider, cfg.LLM.Model)
+	}
+	return nil
+}
+
+// initNonInteractive reports whether init was given enough flags to run
+// without prompting.
+func initNonInteractive(cmd *cobra.Command) bool {
+	for _, name := range []string{"owner", "repo", "provider"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// initFromFlags builds the configuration from flags, filling in the
+// repository from the git remote and the model from the provider.
+func initFromFlags(remotes []config.Remote) (*config.Config, error) {
+	project := config.ProjectConfig{
+		Host:  flagInitHost,
+		Owner: flagInitOwner,
+		Repo:  flagInitRepo,
+	}
+
+	if project.Owner == "" || project.Repo == "" {
+		remote, err := selectRemote(remotes, flagInitRemote)
+		if err != nil {
+			return nil, err
+		}
+		if remote == nil {
+			return nil, fmt.Errorf("--owner and --repo are required when no GitHub remote is found")
+		}
+		if project.Owner == "" {
+			project.Owner = remote.Owner
+		}
+		if project.Repo == "" {
+			project.Repo = remote.Repo
+		}
+		if project.Host == "" {
+			project.Host = remote.Host
+		}
+	}
+	if project.Host == config.DefaultHost {
+		project.Host = ""
+	}
+
+	name := flagInitProvider
+	if name == "" {
+		name = "none"
+	}
+	provider := llm.ProviderByName(name)
+	if provider == nil {
+		return nil, fmt.Errorf("unknown LLM provider %q", name)
+	}
+
+	llmCfg := config.LLMConfig{Provider: provider.Name}
+	if provider.Name != "none" {
+		llmCfg.Model = flagInitModel
+		if llmCfg.Model == "" {
+			llmCfg.Model = provider.DefaultModel
+		}
+		if provider.DefaultURL != "" {
+			llmCfg.BaseURL = flagInitBaseURL
+			if llmCfg.BaseURL == "" {
+				llmCfg.BaseURL = provider.DefaultURL
+			}
+		}
+		if provider.RequiresKey && os.Getenv("GRIT_LLM_KEY") == "" {
+			fmt.Printf("No API key stored for %s; set GRIT_LLM_KEY before creating issues.\n", provider.Name)
+		}
+	}
+
+	return &config.Config{Version: 1, Project: project, LLM: llmCfg}, nil
+}
+
+// initInteractive runs the setup wizard, offering the repository of a git
+// remote as the default.
+func initInteractive(reader *bufio.Reader, remotes []config.Remote) (*config.Config, error) {
+	remote, err := selectRemote(remotes, flagInitRemote)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if remote == nil && len(remotes) > 1 {
+		remote, err = promptRemoteMenu(reader, remotes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defaultHost, defaultOwner, defaultRepo := config.DefaultHost, flagInitOwner, flagInitRepo
+	if remote != nil {
+		fmt.Printf("Using git remote %s (%s)\n", remote.Name, remote.URL)
+		if remote.Host != "" {
+			defaultHost = remote.Host
+		}
+		defaultOwner, defaultRepo = remote.Owner, remote.Repo
+	}
+	if flagInitHost != "" {
+		defaultHost = flagInitHost
+	}
+
+	host, err := promptWithDefault(reader, "GitHub host", defaultHost)
+	if err != nil {
+		return nil, err
+	}
+	if host == config.DefaultHost {
+		host = ""
+	}
+
+	owner, err := promptMaybeDefault(reader, "GitHub owner (user or org)", defaultOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := promptMaybeDefault(reader, "Repository name", defaultRepo)
+	if err != nil {
+		return nil, err
 	}
 
 	provider, err := promptProviderMenu(reader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	llmCfg := config.LLMConfig{
@@ -63,17 +232,17 @@ func runInit(cmd *cobra.Command, args []string) error {
 		if provider.RequiresKey {
 			key, err := promptAPIKey(provider.Name)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			if err := config.SetLLMKey(provider.Name, key); err != nil {
-				return fmt.Errorf("storing API key: %w", err)
+				return nil, fmt.Errorf("storing API key: %w", err)
 			}
 		}
 
 		if provider.DefaultURL != "" {
 			baseURL, err := promptWithDefault(reader, "Ollama base URL", provider.DefaultURL)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			llmCfg.BaseURL = baseURL
 
@@ -86,34 +255,105 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 		model, err := promptWithDefault(reader, "Model", provider.DefaultModel)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		llmCfg.Model = model
 	}
 
-	cfg := &config.Config{
+	return &config.Config{
 		Version: 1,
 		Project: config.ProjectConfig{
+			Host:  host,
 			Owner: owner,
 			Repo:  repo,
 		},
 		LLM: llmCfg,
+	}, nil
+}
+
+// selectRemote picks the remote named by --remote, or the only remote when
+// there is just one. It returns nil when a choice is still needed.
+func selectRemote(remotes []config.Remote, name string) (*config.Remote, error) {
+	if name != "" {
+		for i := range remotes {
+			if remotes[i].Name == name {
+				return &remotes[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no GitHub remote named %q", name)
+	}
+	if len(remotes) == 1 {
+		return &remotes[0], nil
 	}
+	return nil, nil
+}
 
-	if err := config.Save(cwd, cfg); err != nil {
-		return err
+func promptRemoteMenu(reader *bufio.Reader, remotes []config.Remote) (*config.Remote, error) {
+	def := 1
+	fmt.Println("Select git remote:")
+	for i, r := range remotes {
+		fmt.Printf("  %d. %-10s %s\n", i+1, r.Name, r.FullName())
+		if r.Name == "origin" {
+			def = i + 1
+		}
 	}
+	fmt.Printf("  %d. %-10s %s\n", len(remotes)+1, "none", "enter the repository manually")
 
-	if err := config.WriteGitignore(cwd); err != nil {
-		return fmt.Errorf("creating .gitignore: %w", err)
+	for {
+		input, err := promptWithDefault(reader, "Choice", strconv.Itoa(def))
+		if err != nil {
+			return nil, err
+		}
+
+		choice, err := strconv.Atoi(input)
+		if err != nil || choice < 1 || choice > len(remotes)+1 {
+			fmt.Printf("Please enter a number between 1 and %d.\n", len(remotes)+1)
+			continue
+		}
+		if choice == len(remotes)+1 {
+			return nil, nil
+		}
+		return &remotes[choice-1], nil
 	}
+}
 
-	if provider.Name == "none" {
-		fmt.Printf("Initialized grit for %s/%s (no LLM)\n", owner, repo)
-	} else {
-		fmt.Printf("Initialized grit for %s/%s using %s (%s)\n", owner, repo, llmCfg.Provider, llmCfg.Model)
+// offerLabelImport copies the repository's labels into cfg when a token for
+// the repository is already available. Failures are reported but never stop
+// initialization.
+func offerLabelImport(cmd *cobra.Command, reader *bufio.Reader, cfg *config.Config) {
+	if _, err := buildGitHubClient(cfg); err != nil {
+		fmt.Println("Run 'grit auth login' and then 'grit label import' to import the repository's labels.")
+		return
 	}
-	return nil
+
+	answer, err := promptWithDefault(reader, "Import existing labels from the repository? (y/n)", "y")
+	if err != nil {
+		return
+	}
+	if a := strings.ToLower(answer); a != "y" && a != "yes" {
+		return
+	}
+
+	importInitLabels(cmd, cfg)
+}
+
+// importInitLabels copies the repository's labels into cfg, reporting
+// failures as warnings.
+func importInitLabels(cmd *cobra.Command, cfg *config.Config) {
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		fmt.Printf("Warning: could not import labels: %v\n", err)
+		return
+	}
+
+	labels, err := service.NewLabelService(ghClient, cfg).Import(cmd.Context())
+	if err != nil {
+		fmt.Printf("Warning: could not import labels: %v\n", err)
+		return
+	}
+
+	cfg.Project.Labels = labels
+	fmt.Printf("Imported %d label(s)\n", len(labels))
 }
 
 func promptProviderMenu(reader *bufio.Reader) (*llm.ProviderInfo, error) {
@@ -172,6 +412,14 @@ func prompt(reader *bufio.Reader, label string) (string, error) {
 	return strings.TrimSpace(input), nil
 }
 
+// promptMaybeDefault prompts with a default when there is one.
+func promptMaybeDefault(reader *bufio.Reader, label, defaultVal string) (string, error) {
+	if defaultVal == "" {
+		return prompt(reader, label)
+	}
+	return promptWithDefault(reader, label, defaultVal)
+}
+
 func promptWithDefault(reader *bufio.Reader, label, defaultVal string) (string, error) {
 	fmt.Printf("%s [%s]: ", label, defaultVal)
 	input, err := reader.ReadString('\n')
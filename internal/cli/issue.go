@@ -2,11 +2,13 @@ package cli
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"iter"
 	"os"
 	"os/exec"
 	"runtime"
-	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,6 +16,7 @@ import (
 	"github.com/dulait/grit/internal/config"
 	"github.com/dulait/grit/internal/github"
 	"github.com/dulait/grit/internal/llm"
+	"github.com/dulait/grit/internal/offline"
 	"github.com/dulait/grit/internal/service"
 )
 
@@ -31,12 +34,21 @@ var (
 	flagRaw         bool
 	flagEnhance     bool
 	linkType        string
+	unlinkType      string
 	flagState       string
 	flagAssignee    string
 	flagLabel       string
 	flagLimit       int
 	flagPage        int
 	flagWeb         bool
+	flagAll         bool
+	flagComment     bool
+	flagComments    bool
+	flagMilestone   string
+	flagReason      string
+	flagDuplicateOf string
+	flagCopyLabels  bool
+	flagIncludePRs  bool
 )
 
 var issueCreateCmd = &cobra.Command{
@@ -48,7 +60,11 @@ var issueCreateCmd = &cobra.Command{
 2. AI-assisted (prompt):      grit issue create "describe the problem"
 3. Explicit (flags):          grit issue create -t "Title" -d "Description"
 
-Flags override AI generation. Missing fields are generated by the LLM.`,
+Flags override AI generation. Missing fields are generated by the LLM.
+
+Use --template to start from one of the repository's issue templates. Its
+labels and assignees are applied, the LLM fills in its sections, and issue
+forms prompt for each of their fields.`,
 	RunE: runIssueCreate,
 }
 
@@ -59,19 +75,31 @@ var issueListCmd = &cobra.Command{
 }
 
 var issueCloseCmd = &cobra.Command{
-	Use:   "close <number> [reason]",
+	Use:   "close <number> [comment]",
 	Short: "Close an issue",
-	Long:  "Close an issue, optionally with a comment explaining why.",
+	Long: `Close an issue, optionally with a comment explaining why.
+
+Use --reason to record why the issue was closed, or --duplicate-of to mark
+it as a duplicate of another issue.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runIssueClose,
+}
+
+var issueReopenCmd = &cobra.Command{
+	Use:   "reopen <number> [comment]",
+	Short: "Reopen a closed issue",
 	Args:  cobra.MinimumNArgs(1),
-	RunE:  runIssueClose,
+	RunE:  runIssueReopen,
 }
 
 var issueCommentCmd = &cobra.Command{
 	Use:   "comment <number> <prompt>",
-	Short: "Add a comment to an issue",
-	Long:  "Generate and add a comment using natural language.",
-	Args:  cobra.MinimumNArgs(2),
-	RunE:  runIssueComment,
+	Short: "Add, edit, or delete issue comments",
+	Long: `Generate and add a comment using natural language.
+
+Use the edit and delete subcommands to change comments that already exist.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runIssueComment,
 }
 
 var issueAssignCmd = &cobra.Command{
@@ -84,9 +112,38 @@ var issueAssignCmd = &cobra.Command{
 var issueLinkCmd = &cobra.Command{
 	Use:   "link <number> <target-number>",
 	Short: "Link two issues",
-	Long:  "Create a reference between two issues. Use --type to specify relationship.",
+	Long: `Create a relationship between two issues. Use --type to specify the relationship.
+
+blocks and blocked-by use GitHub issue dependencies, parent and child use
+sub-issues, and duplicates closes the issue as a duplicate of the target.
+Use --comment to record the relationship as a comment instead, for servers
+without these APIs.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runIssueLink,
+}
+
+var issueUnlinkCmd = &cobra.Command{
+	Use:   "unlink <number> <target-number>",
+	Short: "Remove a relationship between two issues",
+	Long:  "Remove a blocks, blocked-by, parent or child relationship created with 'grit issue link'.",
 	Args:  cobra.ExactArgs(2),
-	RunE:  runIssueLink,
+	RunE:  runIssueUnlink,
+}
+
+var issueLinksCmd = &cobra.Command{
+	Use:   "links <number>",
+	Short: "Show an issue's relationships",
+	Long:  "Show the parent, sub-issues, and blocking relationships of an issue.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runIssueLinks,
+}
+
+var issueHistoryCmd = &cobra.Command{
+	Use:   "history <number>",
+	Short: "Show an issue's timeline",
+	Long:  "Show a chronological log of label, assignment, state and title changes, comments and cross-references.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runIssueHistory,
 }
 
 var issueViewCmd = &cobra.Command{
@@ -114,10 +171,13 @@ var issueSearchCmd = &cobra.Command{
 
 var issueSubCmd = &cobra.Command{
 	Use:   "sub <parent-number> [prompt]",
-	Short: "Create a sub-issue",
-	Long:  "Create an issue linked as a child of an existing issue.",
-	Args:  cobra.MinimumNArgs(1),
-	RunE:  runIssueSub,
+	Short: "Create and manage sub-issues",
+	Long: `Create an issue as a sub-issue of an existing issue.
+
+Use the add, remove, list and move subcommands to manage the sub-issues of
+existing issues.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runIssueSub,
 }
 
 func init() {
@@ -126,9 +186,13 @@ func init() {
 	issueCmd.AddCommand(issueListCmd)
 	issueCmd.AddCommand(issueViewCmd)
 	issueCmd.AddCommand(issueCloseCmd)
+	issueCmd.AddCommand(issueReopenCmd)
 	issueCmd.AddCommand(issueCommentCmd)
 	issueCmd.AddCommand(issueAssignCmd)
 	issueCmd.AddCommand(issueLinkCmd)
+	issueCmd.AddCommand(issueUnlinkCmd)
+	issueCmd.AddCommand(issueLinksCmd)
+	issueCmd.AddCommand(issueHistoryCmd)
 	issueCmd.AddCommand(issueEditCmd)
 	issueCmd.AddCommand(issueSubCmd)
 	issueCmd.AddCommand(issueSearchCmd)
@@ -139,56 +203,97 @@ func init() {
 	issueCreateCmd.Flags().StringVarP(&flagAssignees, "assignees", "a", "", "Comma-separated assignees")
 	issueCreateCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Skip confirmation prompt")
 	issueCreateCmd.Flags().BoolVar(&flagRaw, "raw", false, "Use input verbatim without LLM enhancement")
+	issueCreateCmd.Flags().StringVarP(&flagMilestone, "milestone", "m", "", "Milestone number or title")
+	addProjectFlags(issueCreateCmd)
 
 	issueSubCmd.Flags().StringVarP(&flagTitle, "title", "t", "", "Issue title")
 	issueSubCmd.Flags().StringVarP(&flagDescription, "description", "d", "", "Issue description")
 	issueSubCmd.Flags().StringVarP(&flagLabels, "labels", "l", "", "Comma-separated labels")
 	issueSubCmd.Flags().StringVarP(&flagAssignees, "assignees", "a", "", "Comma-separated assignees")
 	issueSubCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Skip confirmation prompt")
+	issueSubCmd.Flags().StringVarP(&flagMilestone, "milestone", "m", "", "Milestone number or title")
 
 	issueLinkCmd.Flags().StringVar(&linkType, "type", "related", "Link type: related, blocks, blocked-by, duplicates, parent, child")
+	issueLinkCmd.Flags().BoolVar(&flagComment, "comment", false, "Record the link as a comment instead of a native relationship")
+
+	issueUnlinkCmd.Flags().StringVar(&unlinkType, "type", "blocks", "Link type: blocks, blocked-by, parent, child")
+
+	issueCloseCmd.Flags().StringVarP(&flagReason, "reason", "r", "", "Close reason: completed, not_planned, duplicate")
+	issueCloseCmd.Flags().StringVar(&flagDuplicateOf, "duplicate-of", "", "Close as a duplicate of this issue, as N or owner/repo#N")
+	issueCloseCmd.Flags().BoolVar(&flagCopyLabels, "copy-labels", false, "Copy this issue's labels to the canonical issue")
+	issueCloseCmd.MarkFlagsMutuallyExclusive("reason", "duplicate-of")
 
 	issueViewCmd.Flags().BoolVarP(&flagWeb, "web", "w", false, "Open in browser")
+	issueViewCmd.Flags().BoolVarP(&flagComments, "comments", "c", false, "Show the comment thread")
 
 	issueEditCmd.Flags().StringVarP(&flagTitle, "title", "t", "", "New title")
 	issueEditCmd.Flags().StringVarP(&flagDescription, "description", "d", "", "New description")
 	issueEditCmd.Flags().StringVarP(&flagLabels, "labels", "l", "", "Comma-separated labels (replaces all)")
 	issueEditCmd.Flags().StringVarP(&flagAssignees, "assignees", "a", "", "Comma-separated assignees (replaces all)")
 	issueEditCmd.Flags().StringVarP(&flagState, "state", "s", "", "New state: open or closed")
+	issueEditCmd.Flags().StringVarP(&flagMilestone, "milestone", "m", "", "Milestone number or title, or \"none\" to remove it")
+	addProjectFlags(issueEditCmd)
 	issueEditCmd.Flags().BoolVar(&flagEnhance, "enhance", false, "Enhance changes with LLM")
 	issueEditCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Skip confirmation")
 
 	issueListCmd.Flags().StringVarP(&flagState, "state", "s", "open", "Filter by state: open, closed, all")
 	issueListCmd.Flags().StringVarP(&flagAssignee, "assignee", "a", "", "Filter by assignee, or \"none\" for unassigned")
 	issueListCmd.Flags().StringVarP(&flagLabel, "label", "l", "", "Filter by label")
-	issueListCmd.Flags().IntVarP(&flagLimit, "limit", "n", 30, "Results per page")
+	issueListCmd.Flags().StringVarP(&flagMilestone, "milestone", "m", "", "Filter by milestone number or title, \"*\" for any, or \"none\"")
+	issueListCmd.Flags().IntVarP(&flagLimit, "limit", "n", 30, "Results per page, or maximum results with --all")
 	issueListCmd.Flags().IntVarP(&flagPage, "page", "p", 1, "Page number")
+	issueListCmd.Flags().BoolVar(&flagAll, "all", false, "Stream every matching issue without pagination prompts")
+	issueListCmd.Flags().BoolVar(&flagIncludePRs, "include-prs", false, "Include pull requests")
+	issueListCmd.Flags().BoolVar(&flagAllRepos, "all-repos", false, "List issues from every known repository")
 
 	issueSearchCmd.Flags().StringVarP(&flagState, "state", "s", "", "Filter by state: open, closed")
 	issueSearchCmd.Flags().StringVarP(&flagLabel, "label", "l", "", "Filter by label")
-	issueSearchCmd.Flags().IntVarP(&flagLimit, "limit", "n", 30, "Results per page")
+	issueSearchCmd.Flags().StringVarP(&flagMilestone, "milestone", "m", "", "Filter by milestone number or title, or \"none\"")
+	issueSearchCmd.Flags().IntVarP(&flagLimit, "limit", "n", 30, "Results per page, or maximum results with --all")
 	issueSearchCmd.Flags().IntVarP(&flagPage, "page", "p", 1, "Page number")
+	issueSearchCmd.Flags().BoolVar(&flagAll, "all", false, "Stream every matching issue without pagination prompts")
+	issueSearchCmd.Flags().BoolVar(&flagIncludePRs, "include-prs", false, "Include pull requests")
+	issueSearchCmd.Flags().BoolVar(&flagAllRepos, "all-repos", false, "Search every known repository")
 }
 
 func runIssueCreate(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	cfg, err := config.LoadFromWorkingDir()
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
-	input, err := gatherIssueInput(cfg, args)
+	ghClient, err := buildGitHubClient(cfg)
 	if err != nil {
 		return err
 	}
 
-	enhance := !flagRaw
+	tmpl, err := findTemplate(ctx, ghClient)
+	if err != nil {
+		return err
+	}
 
-	ghClient, err := buildGitHubClient(cfg)
+	var input service.IssueInput
+	if tmpl != nil && tmpl.Form {
+		input, err = gatherFormInput(cfg, args, tmpl)
+	} else {
+		input, err = gatherIssueInput(cfg, args)
+	}
 	if err != nil {
 		return err
 	}
+	if input.Assignees, err = checkAssignees(ctx, ghClient, cfg, input.Assignees); err != nil {
+		return err
+	}
+	input = service.ApplyTemplate(input, tmpl)
+
+	board, err := parseProjectFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	enhance := !flagRaw
 
 	var llmClient llm.Client
 	if enhance {
@@ -207,6 +312,10 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	printGeneratedIssue(generated, input.Assignees)
+	if board != nil {
+		board.print()
+		fmt.Println()
+	}
 
 	if !flagYes {
 		if !confirmAction("Create this issue?") {
@@ -215,12 +324,23 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	issue, err := svc.CreateIssue(ctx, generated, input.Assignees)
+	opts := service.CreateOptions{Assignees: input.Assignees, Milestone: flagMilestone}
+	issue, err := svc.CreateIssue(ctx, generated, opts)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Created issue #%d: %s\n", issue.Number, issue.HTMLURL)
+	if offline.IsDraft(issue.Number) {
+		fmt.Printf("Created %s, numbered by GitHub when synced\n", offline.FormatNumber(issue.Number))
+	} else {
+		fmt.Printf("Created issue #%d: %s\n", issue.Number, issue.HTMLURL)
+	}
+
+	if board != nil {
+		if err := applyProjectFlags(ctx, ghClient, cfg, issue.Number, board); err != nil {
+			return fmt.Errorf("created issue %s but %w", offline.FormatNumber(issue.Number), err)
+		}
+	}
 	return nil
 }
 
@@ -243,7 +363,7 @@ func gatherIssueInput(cfg *config.Config, args []string) (service.IssueInput, er
 	}
 
 	if len(input.Labels) > 0 {
-		input.Labels = validateLabels(input.Labels, cfg.Project.Labels)
+		input.Labels = validateLabels(input.Labels, cfg.Project.LabelNames())
 	}
 
 	return input, nil
@@ -268,14 +388,14 @@ func runInteractiveMode(cfg *config.Config) (service.IssueInput, error) {
 	}
 	input.Description = description
 
-	if len(cfg.Project.Labels) > 0 {
-		fmt.Printf("Available labels: %s\n", strings.Join(cfg.Project.Labels, ", "))
+	if len(cfg.Project.LabelNames()) > 0 {
+		fmt.Printf("Available labels: %s\n", strings.Join(cfg.Project.LabelNames(), ", "))
 		labelsStr, err := promptOptional(reader, "Labels (comma-separated)")
 		if err != nil {
 			return input, err
 		}
 		if labelsStr != "" {
-			input.Labels = validateLabels(parseCSV(labelsStr), cfg.Project.Labels)
+			input.Labels = validateLabels(parseCSV(labelsStr), cfg.Project.LabelNames())
 		}
 	} else {
 		fmt.Println("No labels configured. Skipping label selection.")
@@ -306,9 +426,9 @@ func runInteractiveMode(cfg *config.Config) (service.IssueInput, error) {
 func runIssueClose(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	number, err := strconv.Atoi(args[0])
+	cfg, number, err := loadIssueRef(args[0])
 	if err != nil {
-		return fmt.Errorf("invalid issue number: %s", args[0])
+		return err
 	}
 
 	var comment string
@@ -316,11 +436,52 @@ func runIssueClose(cmd *cobra.Command, args []string) error {
 		comment = strings.Join(args[1:], " ")
 	}
 
-	cfg, err := config.LoadFromWorkingDir()
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	opts := service.CloseOptions{
+		Comment:    comment,
+		Reason:     flagReason,
+		CopyLabels: flagCopyLabels,
+	}
+	if flagDuplicateOf != "" {
+		if opts.DuplicateOf, err = issueRefIn(cfg, flagDuplicateOf); err != nil {
+			return err
+		}
+	}
+	issue, err := svc.CloseIssue(ctx, number, opts)
+	if err != nil {
+		if issue != nil {
+			return fmt.Errorf("closed issue %s but %w", offline.FormatNumber(issue.Number), err)
+		}
+		return err
+	}
+
+	if flagDuplicateOf != "" {
+		fmt.Printf("Closed issue %s as a duplicate of %s: %s\n", offline.FormatNumber(issue.Number), opts.DuplicateOf, issue.HTMLURL)
+	} else {
+		fmt.Printf("Closed issue %s: %s\n", offline.FormatNumber(issue.Number), issue.HTMLURL)
+	}
+	return nil
+}
+
+func runIssueReopen(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, number, err := loadIssueRef(args[0])
 	if err != nil {
 		return err
 	}
 
+	var comment string
+	if len(args) > 1 {
+		comment = strings.Join(args[1:], " ")
+	}
+
 	ghClient, err := buildGitHubClient(cfg)
 	if err != nil {
 		return err
@@ -328,30 +489,25 @@ func runIssueClose(cmd *cobra.Command, args []string) error {
 
 	svc := service.NewIssueService(ghClient, nil, cfg)
 
-	issue, err := svc.CloseIssue(ctx, number, comment)
+	issue, err := svc.ReopenIssue(ctx, number, comment)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Closed issue #%d: %s\n", issue.Number, issue.HTMLURL)
+	fmt.Printf("Reopened issue %s: %s\n", offline.FormatNumber(issue.Number), issue.HTMLURL)
 	return nil
 }
 
 func runIssueComment(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	number, err := strconv.Atoi(args[0])
+	cfg, number, err := loadIssueRef(args[0])
 	if err != nil {
-		return fmt.Errorf("invalid issue number: %s", args[0])
+		return err
 	}
 
 	userPrompt := strings.Join(args[1:], " ")
 
-	cfg, err := config.LoadFromWorkingDir()
-	if err != nil {
-		return err
-	}
-
 	ghClient, err := buildGitHubClient(cfg)
 	if err != nil {
 		return err
@@ -378,19 +534,17 @@ func runIssueComment(cmd *cobra.Command, args []string) error {
 func runIssueAssign(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	number, err := strconv.Atoi(args[0])
+	cfg, number, err := loadIssueRef(args[0])
 	if err != nil {
-		return fmt.Errorf("invalid issue number: %s", args[0])
+		return err
 	}
 
-	assignees := args[1:]
-
-	cfg, err := config.LoadFromWorkingDir()
+	ghClient, err := buildGitHubClient(cfg)
 	if err != nil {
 		return err
 	}
 
-	ghClient, err := buildGitHubClient(cfg)
+	assignees, err := checkAssignees(ctx, ghClient, cfg, args[1:])
 	if err != nil {
 		return err
 	}
@@ -402,24 +556,52 @@ func runIssueAssign(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Assigned %v to issue #%d: %s\n", assignees, issue.Number, issue.HTMLURL)
+	fmt.Printf("Assigned %v to issue %s: %s\n", assignees, offline.FormatNumber(issue.Number), issue.HTMLURL)
 	return nil
 }
 
 func runIssueLink(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	number, err := strconv.Atoi(args[0])
+	cfg, number, err := loadIssueRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	target, err := issueRefIn(cfg, args[1])
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	if flagComment {
+		err = svc.LinkIssueByComment(ctx, number, target, linkType)
+	} else {
+		err = svc.LinkIssue(ctx, number, target, linkType)
+	}
 	if err != nil {
-		return fmt.Errorf("invalid issue number: %s", args[0])
+		return err
 	}
 
-	targetNumber, err := strconv.Atoi(args[1])
+	fmt.Printf("Linked issue #%d to %s (%s)\n", number, target, linkType)
+	return nil
+}
+
+func runIssueUnlink(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, number, err := loadIssueRef(args[0])
 	if err != nil {
-		return fmt.Errorf("invalid target issue number: %s", args[1])
+		return err
 	}
 
-	cfg, err := config.LoadFromWorkingDir()
+	target, err := issueRefIn(cfg, args[1])
 	if err != nil {
 		return err
 	}
@@ -431,23 +613,104 @@ func runIssueLink(cmd *cobra.Command, args []string) error {
 
 	svc := service.NewIssueService(ghClient, nil, cfg)
 
-	if err := svc.LinkIssue(ctx, number, targetNumber, linkType); err != nil {
+	if err := svc.UnlinkIssue(ctx, number, target, unlinkType); err != nil {
 		return err
 	}
 
-	fmt.Printf("Linked issue #%d to #%d (%s)\n", number, targetNumber, linkType)
+	fmt.Printf("Unlinked issue #%d from %s (%s)\n", number, target, unlinkType)
 	return nil
 }
 
-func runIssueSub(cmd *cobra.Command, args []string) error {
+func runIssueLinks(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, number, err := loadIssueRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	rel, err := svc.Relationships(ctx, number)
+	if err != nil {
+		return err
+	}
+
+	empty := true
+	if rel.Parent != nil {
+		fmt.Println("Parent:")
+		printIssueRow(*rel.Parent)
+		empty = false
+	}
+	for _, group := range []struct {
+		title  string
+		issues []github.Issue
+	}{
+		{"Sub-issues:", rel.SubIssues},
+		{"Blocked by:", rel.BlockedBy},
+		{"Blocking:", rel.Blocking},
+	} {
+		if len(group.issues) == 0 {
+			continue
+		}
+		if !empty {
+			fmt.Println()
+		}
+		fmt.Println(group.title)
+		printIssueList(group.issues)
+		empty = false
+	}
+
+	if empty {
+		fmt.Printf("#%d has no relationships.\n", number)
+	}
+	return nil
+}
+
+func runIssueHistory(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	parentNumber, err := strconv.Atoi(args[0])
+	cfg, number, err := loadIssueRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
 	if err != nil {
-		return fmt.Errorf("invalid parent issue number: %s", args[0])
+		return err
 	}
 
-	cfg, err := config.LoadFromWorkingDir()
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	entries, err := svc.History(ctx, number)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("#%d has no history yet.\n", number)
+		return nil
+	}
+
+	for _, e := range entries {
+		actor := "-"
+		if e.Actor != "" {
+			actor = "@" + e.Actor
+		}
+		fmt.Printf("%s  %-20s %s\n", e.Time.Format("2006-01-02 15:04"), actor, e.Text)
+	}
+	return nil
+}
+
+func runIssueSub(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, parentNumber, err := loadIssueRef(args[0])
 	if err != nil {
 		return err
 	}
@@ -477,6 +740,9 @@ func runIssueSub(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if input.Assignees, err = checkAssignees(ctx, ghClient, cfg, input.Assignees); err != nil {
+		return err
+	}
 
 	var llmClient llm.Client
 	if enhance {
@@ -504,8 +770,12 @@ func runIssueSub(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	issue, err := svc.CreateSubIssue(ctx, parentNumber, generated, input.Assignees)
+	opts := service.CreateOptions{Assignees: input.Assignees, Milestone: flagMilestone}
+	issue, err := svc.CreateSubIssue(ctx, parentNumber, generated, opts)
 	if err != nil {
+		if issue != nil {
+			return fmt.Errorf("created issue #%d but could not attach it to #%d: %w", issue.Number, parentNumber, err)
+		}
 		return err
 	}
 
@@ -516,7 +786,11 @@ func runIssueSub(cmd *cobra.Command, args []string) error {
 func runIssueList(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	cfg, err := config.LoadFromWorkingDir()
+	if flagAllRepos {
+		return runCrossRepoList(cmd)
+	}
+
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
@@ -527,36 +801,63 @@ func runIssueList(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	milestone, err := listMilestoneFilter(ctx, svc, flagMilestone)
+	if err != nil {
+		return err
+	}
+
+	if flagAll {
+		limit := allLimit(cmd)
+		req := github.ListIssuesRequest{
+			State:     flagState,
+			Assignee:  flagAssignee,
+			Labels:    flagLabel,
+			Milestone: milestone,
+			PerPage:   min(limit, 100),
+			Page:      flagPage,
+
+			IncludePullRequests: flagIncludePRs,
+		}
+		return streamIssues(svc.IterateIssues(ctx, req), limit)
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 	page := flagPage
 
 	for {
 		req := github.ListIssuesRequest{
-			State:    flagState,
-			Assignee: flagAssignee,
-			Labels:   flagLabel,
-			PerPage:  flagLimit,
-			Page:     page,
+			State:     flagState,
+			Assignee:  flagAssignee,
+			Labels:    flagLabel,
+			Milestone: milestone,
+			PerPage:   flagLimit,
+			Page:      page,
+
+			IncludePullRequests: flagIncludePRs,
 		}
 
-		issues, err := svc.ListIssues(ctx, req)
+		result, err := svc.ListIssues(ctx, req)
 		if err != nil {
 			return err
 		}
 
-		if len(issues) == 0 && page == 1 {
+		if len(result.Issues) == 0 && !result.HasNext && page == 1 {
 			fmt.Println("No issues found.")
 			return nil
 		}
 
-		if len(issues) == 0 {
+		if len(result.Issues) == 0 && !result.HasNext {
 			fmt.Println("No more issues.")
 			return nil
 		}
 
-		printIssueList(issues)
+		if len(result.Issues) == 0 {
+			fmt.Println("No issues on this page, only pull requests.")
+		}
+		printIssueList(result.Issues)
 
-		hasNext := len(issues) == flagLimit
+		hasNext := result.HasNext
 		hasPrev := page > 1
 
 		if !hasNext && !hasPrev {
@@ -576,8 +877,13 @@ func runIssueList(cmd *cobra.Command, args []string) error {
 
 func runIssueSearch(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
+	query := strings.Join(args, " ")
+
+	if flagAllRepos {
+		return runCrossRepoSearch(cmd, query)
+	}
 
-	cfg, err := config.LoadFromWorkingDir()
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
@@ -588,18 +894,41 @@ func runIssueSearch(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	milestone, err := searchMilestoneFilter(ctx, svc, flagMilestone)
+	if err != nil {
+		return err
+	}
+
+	req := github.SearchIssuesRequest{
+		Query:     query,
+		State:     flagState,
+		Labels:    flagLabel,
+		Milestone: milestone,
+
+		IncludePullRequests: flagIncludePRs,
+	}
+	return searchIssues(cmd, svc, req)
+}
+
+// searchIssues runs a search, streaming every result with --all and
+// prompting for pagination otherwise.
+func searchIssues(cmd *cobra.Command, svc *service.IssueService, req github.SearchIssuesRequest) error {
+	ctx := cmd.Context()
+
+	if flagAll {
+		limit := allLimit(cmd)
+		req.PerPage = min(limit, 100)
+		req.Page = flagPage
+		return streamIssues(svc.IterateSearch(ctx, req), limit)
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 	page := flagPage
-	query := strings.Join(args, " ")
 
 	for {
-		req := github.SearchIssuesRequest{
-			Query:   query,
-			State:   flagState,
-			Labels:  flagLabel,
-			PerPage: flagLimit,
-			Page:    page,
-		}
+		req.PerPage = flagLimit
+		req.Page = page
 
 		resp, err := svc.SearchIssues(ctx, req)
 		if err != nil {
@@ -622,7 +951,7 @@ func runIssueSearch(cmd *cobra.Command, args []string) error {
 
 		printIssueList(resp.Items)
 
-		hasNext := len(resp.Items) == flagLimit
+		hasNext := resp.HasNext
 		hasPrev := page > 1
 
 		if !hasNext && !hasPrev {
@@ -640,15 +969,38 @@ func runIssueSearch(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func runIssueView(cmd *cobra.Command, args []string) error {
-	ctx := cmd.Context()
+// allLimit returns the maximum number of results for --all mode. The limit
+// only applies when --limit was given explicitly; zero means unlimited.
+func allLimit(cmd *cobra.Command) int {
+	if cmd.Flags().Changed("limit") {
+		return flagLimit
+	}
+	return 0
+}
 
-	number, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid issue number: %s", args[0])
+func streamIssues(issues iter.Seq2[github.Issue, error], limit int) error {
+	count := 0
+	for issue, err := range issues {
+		if err != nil {
+			return err
+		}
+		printIssueRow(issue)
+		count++
+		if limit > 0 && count >= limit {
+			break
+		}
+	}
+
+	if count == 0 {
+		fmt.Println("No issues found.")
 	}
+	return nil
+}
 
-	cfg, err := config.LoadFromWorkingDir()
+func runIssueView(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, number, err := loadIssueRef(args[0])
 	if err != nil {
 		return err
 	}
@@ -670,18 +1022,31 @@ func runIssueView(cmd *cobra.Command, args []string) error {
 	}
 
 	printIssueDetail(issue)
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+	if !issue.IsPullRequest() {
+		prs, err := svc.LinkedPullRequests(ctx, number)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if len(prs) > 0 {
+			printLinkedPullRequests(prs, cfg.Project.Owner+"/"+cfg.Project.Repo)
+		}
+	}
+
+	if flagComments {
+		comments, err := svc.ListComments(ctx, number)
+		if err != nil {
+			return err
+		}
+		printComments(comments)
+	}
 	return nil
 }
 
 func runIssueEdit(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	number, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid issue number: %s", args[0])
-	}
-
-	cfg, err := config.LoadFromWorkingDir()
+	cfg, number, err := loadIssueRef(args[0])
 	if err != nil {
 		return err
 	}
@@ -697,11 +1062,24 @@ func runIssueEdit(cmd *cobra.Command, args []string) error {
 	}
 
 	input := buildEditInput(cmd)
-	if input == nil {
+	board, err := parseProjectFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if input == nil && board == nil {
 		printIssueDetail(issue)
-		fmt.Println("\nUse flags to specify changes: -t title, -d description, -l labels, -a assignees, -s state")
+		fmt.Println("\nUse flags to specify changes: -t title, -d description, -l labels, -a assignees, -s state, -m milestone, --project, --status, --field")
 		return nil
 	}
+	hasEdits := input != nil
+	if !hasEdits {
+		input = &service.EditIssueInput{}
+	}
+	if input.SetAssignees {
+		if input.Assignees, err = checkAssignees(ctx, ghClient, cfg, input.Assignees); err != nil {
+			return err
+		}
+	}
 
 	enhance := flagEnhance && (input.Title != nil || input.Body != nil)
 
@@ -735,7 +1113,7 @@ func runIssueEdit(cmd *cobra.Command, args []string) error {
 		input.Body = &generated.Body
 	}
 
-	printEditPreview(issue, input)
+	printEditPreview(issue, input, board)
 
 	if !flagYes {
 		if !confirmAction("Apply these changes?") {
@@ -744,13 +1122,20 @@ func runIssueEdit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	svc := service.NewIssueService(ghClient, nil, cfg)
-	updated, err := svc.EditIssue(ctx, number, *input)
-	if err != nil {
-		return err
+	if hasEdits {
+		svc := service.NewIssueService(ghClient, nil, cfg)
+		if _, err := svc.EditIssue(ctx, number, *input); err != nil {
+			return err
+		}
+	}
+
+	if board != nil {
+		if err := applyProjectFlags(ctx, ghClient, cfg, number, board); err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("Updated issue #%d: %s\n", updated.Number, updated.HTMLURL)
+	fmt.Printf("Updated issue %s: %s\n", offline.FormatNumber(issue.Number), issue.HTMLURL)
 	return nil
 }
 
@@ -780,6 +1165,10 @@ func buildEditInput(cmd *cobra.Command) *service.EditIssueInput {
 		input.SetAssignees = true
 		changed = true
 	}
+	if cmd.Flags().Changed("milestone") {
+		input.Milestone = &flagMilestone
+		changed = true
+	}
 
 	if !changed {
 		return nil
@@ -787,10 +1176,10 @@ func buildEditInput(cmd *cobra.Command) *service.EditIssueInput {
 	return input
 }
 
-func printEditPreview(current *github.Issue, input *service.EditIssueInput) {
+func printEditPreview(current *github.Issue, input *service.EditIssueInput, board *projectFlags) {
 	fmt.Println()
 	fmt.Println(strings.Repeat("─", 60))
-	fmt.Printf("Editing issue #%d\n", current.Number)
+	fmt.Printf("Editing issue %s\n", offline.FormatNumber(current.Number))
 	fmt.Println(strings.Repeat("─", 60))
 
 	if input.Title != nil {
@@ -809,6 +1198,12 @@ func printEditPreview(current *github.Issue, input *service.EditIssueInput) {
 	if input.SetAssignees {
 		fmt.Printf("Assignees:  %s → %s\n", formatAssignees(current.Assignees), strings.Join(input.Assignees, ", "))
 	}
+	if input.Milestone != nil {
+		fmt.Printf("Milestone:  %s → %s\n", formatMilestone(current.Milestone), *input.Milestone)
+	}
+	if board != nil {
+		board.print()
+	}
 
 	fmt.Println(strings.Repeat("─", 60))
 }
@@ -816,7 +1211,7 @@ func printEditPreview(current *github.Issue, input *service.EditIssueInput) {
 func printIssueDetail(issue *github.Issue) {
 	fmt.Println()
 	fmt.Println(strings.Repeat("─", 60))
-	fmt.Printf("#%d %s\n", issue.Number, issue.Title)
+	fmt.Printf("%s %s\n", offline.FormatNumber(issue.Number), issue.Title)
 	fmt.Println(strings.Repeat("─", 60))
 	fmt.Printf("State:      %s\n", issue.State)
 	if len(issue.Labels) > 0 {
@@ -825,6 +1220,9 @@ func printIssueDetail(issue *github.Issue) {
 	if len(issue.Assignees) > 0 {
 		fmt.Printf("Assignees:  %s\n", formatAssignees(issue.Assignees))
 	}
+	if issue.Milestone != nil {
+		fmt.Printf("Milestone:  %s\n", formatMilestone(issue.Milestone))
+	}
 	fmt.Printf("URL:        %s\n", issue.HTMLURL)
 	fmt.Printf("Created:    %s\n", issue.CreatedAt.Format("2006-01-02 15:04"))
 	fmt.Printf("Updated:    %s\n", issue.UpdatedAt.Format("2006-01-02 15:04"))
@@ -837,6 +1235,24 @@ func printIssueDetail(issue *github.Issue) {
 	fmt.Println(strings.Repeat("─", 60))
 }
 
+// printLinkedPullRequests lists pull requests that reference an issue.
+// Pull requests in other repositories than repo are shown as owner/repo#N.
+func printLinkedPullRequests(prs []github.LinkedPullRequest, repo string) {
+	fmt.Println("Pull requests:")
+	for _, pr := range prs {
+		ref := fmt.Sprintf("#%d", pr.Number)
+		if !strings.EqualFold(pr.Repository, repo) {
+			ref = pr.Repository + ref
+		}
+		closes := ""
+		if pr.Closes {
+			closes = "closes"
+		}
+		fmt.Printf("  %-12s %-7s %-7s %s\n", ref, strings.ToLower(pr.State), closes, truncate(pr.Title, 50))
+	}
+	fmt.Println(strings.Repeat("─", 60))
+}
+
 func openInBrowser(url string) {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
@@ -852,19 +1268,35 @@ func openInBrowser(url string) {
 
 func printIssueList(issues []github.Issue) {
 	for _, issue := range issues {
-		labels := formatLabels(issue.Labels)
-		assignees := formatAssignees(issue.Assignees)
+		printIssueRow(issue)
+	}
+}
 
-		line := fmt.Sprintf("#%-5d %-50s %-8s", issue.Number, truncate(issue.Title, 50), issue.State)
-		if labels != "" {
-			line += fmt.Sprintf(" %-12s", labels)
-		}
-		if assignees != "" {
-			line += fmt.Sprintf(" %s", assignees)
+func printIssueRow(issue github.Issue) {
+	labels := formatLabels(issue.Labels)
+	assignees := formatAssignees(issue.Assignees)
+
+	title, state := issue.Title, issue.State
+	if issue.IsPullRequest() {
+		title = "[PR] " + title
+		if issue.PullRequest.MergedAt != nil {
+			state = "merged"
 		}
-		fmt.Println(line)
-		fmt.Printf("       %s\n", issue.HTMLURL)
 	}
+
+	line := fmt.Sprintf("%-6s %-50s %-8s", offline.FormatNumber(issue.Number), truncate(title, 50), state)
+	if flagAllRepos {
+		ref := fmt.Sprintf("%s#%d", issue.RepoFullName(), issue.Number)
+		line = fmt.Sprintf("%-30s %-40s %-8s", truncate(ref, 30), truncate(title, 40), state)
+	}
+	if labels != "" {
+		line += fmt.Sprintf(" %-12s", labels)
+	}
+	if assignees != "" {
+		line += fmt.Sprintf(" %s", assignees)
+	}
+	fmt.Println(line)
+	fmt.Printf("       %s\n", issue.HTMLURL)
 }
 
 func promptPagination(reader *bufio.Reader, currentPage int, hasNext, hasPrev bool) int {
@@ -913,6 +1345,16 @@ func formatAssignees(users []github.User) string {
 	return strings.Join(names, ",")
 }
 
+func formatMilestone(m *github.Milestone) string {
+	if m == nil {
+		return "none"
+	}
+	if m.DueOn != nil {
+		return fmt.Sprintf("%s (due %s)", m.Title, m.DueOn.Format("2006-01-02"))
+	}
+	return m.Title
+}
+
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -921,12 +1363,76 @@ func truncate(s string, maxLen int) string {
 }
 
 func buildGitHubClient(cfg *config.Config) (github.Client, error) {
-	store := config.NewCompositeTokenStore()
-	token, err := store.Get(config.ProjectKey(cfg))
+	token, ts, err := githubCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return withOfflineStore(cfg, newGitHubClient(cfg, token, ts))
+}
+
+// githubCredentials returns the project's token, or a token source when it
+// authenticates as a GitHub App.
+func githubCredentials(cfg *config.Config) (string, *github.AppTokenSource, error) {
+	app, err := config.AppAuth(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("not authenticated: %w\nrun 'grit auth login' first", err)
+		return "", nil, err
 	}
-	return github.NewHTTPClient(cfg.Project.Owner, cfg.Project.Repo, token), nil
+	if app != nil {
+		ts, err := buildAppTokenSource(cfg, app)
+		return "", ts, err
+	}
+
+	token, _, err := lookupToken(cfg)
+	return token, nil, err
+}
+
+// lookupToken finds the project's token in the configured token sources
+// and returns it with the name of its source.
+func lookupToken(cfg *config.Config) (token, source string, err error) {
+	store, err := config.NewTokenStore(cfg)
+	if err != nil {
+		return "", "", err
+	}
+	for _, key := range config.TokenKeys(cfg) {
+		if token, source, err = store.Lookup(key); err == nil {
+			return token, source, nil
+		}
+	}
+	return "", "", fmt.Errorf("not authenticated: %w\nrun 'grit auth login' first", err)
+}
+
+func newGitHubClient(cfg *config.Config, token string, ts *github.AppTokenSource) *github.HTTPClient {
+	opts := []github.Option{
+		github.WithBaseURL(cfg.Project.APIURL()),
+		github.WithGraphQLURL(cfg.Project.GraphQLURL()),
+	}
+	if ts != nil {
+		opts = append(opts, github.WithTokenSource(ts))
+	}
+	if !flagNoCache {
+		// Without a cache directory, requests are simply not cached.
+		if dir, err := cfg.Cache.HTTPDir(); err == nil {
+			opts = append(opts, github.WithCache(github.NewResponseCache(dir, cfg.Cache.MaxSize())))
+		}
+	}
+
+	client := github.NewHTTPClient(cfg.Project.Owner, cfg.Project.Repo, token, opts...)
+	activeGitHubClient = client
+	return client
+}
+
+func buildAppTokenSource(cfg *config.Config, app *config.AppConfig) (*github.AppTokenSource, error) {
+	key, err := config.AppPrivateKey(app)
+	if err != nil {
+		return nil, err
+	}
+	return github.NewAppTokenSource(cfg.Project.APIURL(), github.AppCredentials{
+		AppID:          app.ID,
+		InstallationID: app.InstallationID,
+		PrivateKey:     key,
+		Owner:          cfg.Project.Owner,
+		Repo:           cfg.Project.Repo,
+	})
 }
 
 func buildLLMClient(cfg *config.Config) (llm.Client, error) {
@@ -958,6 +1464,9 @@ func printGeneratedIssue(issue *llm.GeneratedIssue, assignees []string) {
 	if len(assignees) > 0 {
 		fmt.Printf("Assignees: %s\n", strings.Join(assignees, ", "))
 	}
+	if flagMilestone != "" {
+		fmt.Printf("Milestone: %s\n", flagMilestone)
+	}
 	fmt.Println()
 }
 
@@ -993,6 +1502,27 @@ func parseCSV(s string) []string {
 	return result
 }
 
+// checkAssignees checks logins against the repository's assignable users
+// and returns them as GitHub spells them. Unknown logins are an error with
+// suggestions. When the users cannot be listed, a warning is printed and the
+// logins are used as given.
+func checkAssignees(ctx context.Context, gh github.Client, cfg *config.Config, logins []string) ([]string, error) {
+	if len(logins) == 0 {
+		return logins, nil
+	}
+
+	resolved, err := service.NewAssigneeService(gh, cfg).Validate(ctx, logins)
+	var unknown *service.UnknownAssigneesError
+	if errors.As(err, &unknown) {
+		return nil, err
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not check assignees: %v\n", err)
+		return logins, nil
+	}
+	return resolved, nil
+}
+
 func validateLabels(requested, allowed []string) []string {
 	if len(allowed) == 0 {
 		return requested
@@ -0,0 +1,319 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/service"
+)
+
+var (
+	flagColor   string
+	flagNewName string
+	flagDryRun  bool
+	flagPrune   bool
+)
+
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Manage repository labels",
+}
+
+var labelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the repository's labels",
+	Args:  cobra.NoArgs,
+	RunE:  runLabelList,
+}
+
+var labelCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a label",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runLabelCreate,
+}
+
+var labelEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Change a label's name, color or description",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runLabelEdit,
+}
+
+var labelDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a label",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runLabelDelete,
+}
+
+var labelSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Reconcile repository labels with the config",
+	Long: `Create and update repository labels so they match project.labels in
+.grit/config.yaml. Colors and descriptions left out of the config are not
+changed. Labels that exist only in the repository are kept unless --prune
+is given.`,
+	Args: cobra.NoArgs,
+	RunE: runLabelSync,
+}
+
+var labelImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Replace the configured labels with the repository's labels",
+	Args:  cobra.NoArgs,
+	RunE:  runLabelImport,
+}
+
+func init() {
+	rootCmd.AddCommand(labelCmd)
+	labelCmd.AddCommand(labelListCmd)
+	labelCmd.AddCommand(labelCreateCmd)
+	labelCmd.AddCommand(labelEditCmd)
+	labelCmd.AddCommand(labelDeleteCmd)
+	labelCmd.AddCommand(labelSyncCmd)
+	labelCmd.AddCommand(labelImportCmd)
+
+	labelCreateCmd.Flags().StringVarP(&flagColor, "color", "c", "", "Hex color, e.g. d73a4a")
+	labelCreateCmd.Flags().StringVarP(&flagDescription, "description", "d", "", "Label description")
+
+	labelEditCmd.Flags().StringVarP(&flagNewName, "name", "n", "", "New label name")
+	labelEditCmd.Flags().StringVarP(&flagColor, "color", "c", "", "New hex color")
+	labelEditCmd.Flags().StringVarP(&flagDescription, "description", "d", "", "New description")
+
+	labelDeleteCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Skip confirmation")
+
+	labelSyncCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Show the changes without applying them")
+	labelSyncCmd.Flags().BoolVar(&flagPrune, "prune", false, "Delete labels that are not in the config")
+	labelSyncCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Skip confirmation")
+
+	labelImportCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Skip confirmation")
+}
+
+func newLabelService() (*service.LabelService, *config.Config, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.NewLabelService(ghClient, cfg), cfg, nil
+}
+
+func runLabelList(cmd *cobra.Command, args []string) error {
+	svc, _, err := newLabelService()
+	if err != nil {
+		return err
+	}
+
+	labels, err := svc.List(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if len(labels) == 0 {
+		fmt.Println("No labels found.")
+		return nil
+	}
+
+	for _, l := range labels {
+		fmt.Printf("%-24s #%-7s %s\n", truncate(l.Name, 24), l.Color, l.Description)
+	}
+	return nil
+}
+
+func runLabelCreate(cmd *cobra.Command, args []string) error {
+	svc, _, err := newLabelService()
+	if err != nil {
+		return err
+	}
+
+	label, err := svc.Create(cmd.Context(), config.LabelConfig{
+		Name:        args[0],
+		Color:       flagColor,
+		Description: flagDescription,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created label %s (#%s)\n", label.Name, label.Color)
+	return nil
+}
+
+func runLabelEdit(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	if !flags.Changed("name") && !flags.Changed("color") && !flags.Changed("description") {
+		return fmt.Errorf("nothing to change; use --name, --color or --description")
+	}
+
+	svc, _, err := newLabelService()
+	if err != nil {
+		return err
+	}
+
+	req := github.LabelRequest{Name: flagNewName, Color: flagColor}
+	if flags.Changed("description") {
+		req.Description = &flagDescription
+	}
+
+	label, err := svc.Update(cmd.Context(), args[0], req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated label %s (#%s)\n", label.Name, label.Color)
+	return nil
+}
+
+func runLabelDelete(cmd *cobra.Command, args []string) error {
+	svc, _, err := newLabelService()
+	if err != nil {
+		return err
+	}
+
+	if !flagYes {
+		if !confirmAction(fmt.Sprintf("Delete label %q from every issue?", args[0])) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	if err := svc.Delete(cmd.Context(), args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted label %s\n", args[0])
+	return nil
+}
+
+func runLabelSync(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	svc, cfg, err := newLabelService()
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Project.Labels) == 0 {
+		return fmt.Errorf("no labels configured; add them under project.labels in .grit/config.yaml or run 'grit label import'")
+	}
+
+	changes, err := svc.PlanSync(ctx, flagPrune)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("Labels are already in sync.")
+		return nil
+	}
+
+	printLabelChanges(changes)
+
+	if flagDryRun {
+		return nil
+	}
+
+	if !flagYes {
+		fmt.Println()
+		if !confirmAction(fmt.Sprintf("Apply %d change(s)?", len(changes))) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	if err := svc.ApplySync(ctx, changes); err != nil {
+		return err
+	}
+
+	fmt.Printf("Applied %d change(s)\n", len(changes))
+	return nil
+}
+
+func runLabelImport(cmd *cobra.Command, args []string) error {
+	if flagRepo != "" {
+		return fmt.Errorf("label import writes .grit/config.yaml and cannot be used with --repo")
+	}
+
+	svc, cfg, err := newLabelService()
+	if err != nil {
+		return err
+	}
+
+	labels, err := svc.Import(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Project.Labels) > 0 && !flagYes {
+		prompt := fmt.Sprintf("Replace %d configured label(s) with %d from the repository?", len(cfg.Project.Labels), len(labels))
+		if !confirmAction(prompt) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	root, err := config.FindRoot()
+	if err != nil {
+		return err
+	}
+
+	// Reload the file so repositories merged in from the user
+	// configuration are not written to the project.
+	cfg, err = config.Load(root)
+	if err != nil {
+		return err
+	}
+	cfg.Project.Labels = labels
+	if err := config.Save(root, cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d label(s) into %s\n", len(labels), config.Path(root))
+	return nil
+}
+
+func printLabelChanges(changes []service.LabelChange) {
+	for _, c := range changes {
+		switch c.Action {
+		case service.LabelCreate:
+			fmt.Printf("+ %s%s\n", c.Desired.Name, describeLabel(c.Desired.Color, c.Desired.Description))
+		case service.LabelUpdate:
+			fmt.Printf("~ %s\n", c.Current.Name)
+			if c.Current.Name != c.Desired.Name {
+				fmt.Printf("    name:        %s → %s\n", c.Current.Name, c.Desired.Name)
+			}
+			if color := config.NormalizeColor(c.Desired.Color); color != "" && !strings.EqualFold(color, c.Current.Color) {
+				fmt.Printf("    color:       #%s → #%s\n", c.Current.Color, color)
+			}
+			if c.Desired.Description != "" && c.Desired.Description != c.Current.Description {
+				fmt.Printf("    description: %q → %q\n", c.Current.Description, c.Desired.Description)
+			}
+		case service.LabelDelete:
+			fmt.Printf("- %s\n", c.Current.Name)
+		}
+	}
+}
+
+func describeLabel(color, description string) string {
+	var parts []string
+	if color = config.NormalizeColor(color); color != "" {
+		parts = append(parts, "#"+color)
+	}
+	if description != "" {
+		parts = append(parts, fmt.Sprintf("%q", description))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
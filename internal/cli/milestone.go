@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dulait/grit/internal/service"
+)
+
+var (
+	flagMilestoneState string
+	flagDue            string
+)
+
+var milestoneCmd = &cobra.Command{
+	Use:   "milestone",
+	Short: "Manage milestones",
+}
+
+var milestoneListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List milestones",
+	Args:  cobra.NoArgs,
+	RunE:  runMilestoneList,
+}
+
+var milestoneCreateCmd = &cobra.Command{
+	Use:   "create <title>",
+	Short: "Create a milestone",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runMilestoneCreate,
+}
+
+var milestoneCloseCmd = &cobra.Command{
+	Use:   "close <number-or-title>",
+	Short: "Close a milestone",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runMilestoneClose,
+}
+
+func init() {
+	rootCmd.AddCommand(milestoneCmd)
+	milestoneCmd.AddCommand(milestoneListCmd)
+	milestoneCmd.AddCommand(milestoneCreateCmd)
+	milestoneCmd.AddCommand(milestoneCloseCmd)
+
+	milestoneListCmd.Flags().StringVarP(&flagMilestoneState, "state", "s", "open", "Filter by state: open, closed, all")
+
+	milestoneCreateCmd.Flags().StringVarP(&flagDescription, "description", "d", "", "Milestone description")
+	milestoneCreateCmd.Flags().StringVar(&flagDue, "due", "", "Due date (YYYY-MM-DD)")
+}
+
+func newMilestoneService() (*service.MilestoneService, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewMilestoneService(ghClient), nil
+}
+
+func runMilestoneList(cmd *cobra.Command, args []string) error {
+	svc, err := newMilestoneService()
+	if err != nil {
+		return err
+	}
+
+	milestones, err := svc.List(cmd.Context(), flagMilestoneState)
+	if err != nil {
+		return err
+	}
+
+	if len(milestones) == 0 {
+		fmt.Println("No milestones found.")
+		return nil
+	}
+
+	for _, m := range milestones {
+		due := "-"
+		if m.DueOn != nil {
+			due = m.DueOn.Format("2006-01-02")
+		}
+		total := m.OpenIssues + m.ClosedIssues
+		fmt.Printf("%-4d %-32s %-8s due %-10s  %d/%d closed\n",
+			m.Number, truncate(m.Title, 32), m.State, due, m.ClosedIssues, total)
+	}
+	return nil
+}
+
+func runMilestoneCreate(cmd *cobra.Command, args []string) error {
+	var due time.Time
+	if flagDue != "" {
+		var err error
+		due, err = time.Parse("2006-01-02", flagDue)
+		if err != nil {
+			return fmt.Errorf("invalid due date %q: use YYYY-MM-DD", flagDue)
+		}
+	}
+
+	svc, err := newMilestoneService()
+	if err != nil {
+		return err
+	}
+
+	m, err := svc.Create(cmd.Context(), strings.Join(args, " "), flagDescription, due)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created milestone %d: %s\n", m.Number, m.HTMLURL)
+	return nil
+}
+
+func runMilestoneClose(cmd *cobra.Command, args []string) error {
+	svc, err := newMilestoneService()
+	if err != nil {
+		return err
+	}
+
+	m, err := svc.Close(cmd.Context(), strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Closed milestone %d: %s\n", m.Number, m.Title)
+	return nil
+}
+
+// listMilestoneFilter converts a --milestone value into the milestone
+// number expected by the list issues API.
+func listMilestoneFilter(ctx context.Context, svc *service.IssueService, ref string) (string, error) {
+	switch strings.ToLower(ref) {
+	case "", "*", "none":
+		return strings.ToLower(ref), nil
+	}
+	m, err := svc.ResolveMilestone(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(m.Number), nil
+}
+
+// searchMilestoneFilter converts a --milestone value into the milestone
+// title expected by the search qualifier.
+func searchMilestoneFilter(ctx context.Context, svc *service.IssueService, ref string) (string, error) {
+	switch strings.ToLower(ref) {
+	case "", "none":
+		return strings.ToLower(ref), nil
+	}
+	m, err := svc.ResolveMilestone(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return m.Title, nil
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/service"
+)
+
+var (
+	flagProject string
+	flagStatus  string
+	flagFields  []string
+)
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Work with GitHub Projects boards",
+	Long: `Work with the GitHub Projects (v2) boards configured under projects in
+.grit/config.yaml. The token needs the project scope.`,
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the configured projects",
+	Args:  cobra.NoArgs,
+	RunE:  runProjectList,
+}
+
+var projectFieldsCmd = &cobra.Command{
+	Use:   "fields [project]",
+	Short: "List a project's fields and their options",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runProjectFields,
+}
+
+var projectItemCmd = &cobra.Command{
+	Use:   "item <number>",
+	Short: "Show an issue's projects and field values",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProjectItem,
+}
+
+func init() {
+	rootCmd.AddCommand(projectCmd)
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectFieldsCmd)
+	projectCmd.AddCommand(projectItemCmd)
+}
+
+// addProjectFlags registers the flags that add an issue to a project.
+func addProjectFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagProject, "project", "", "Add to this configured project (default project if empty)")
+	cmd.Flags().StringVar(&flagStatus, "status", "", "Set the project's status field")
+	cmd.Flags().StringArrayVar(&flagFields, "field", nil, "Set a project field as name=value (repeatable)")
+}
+
+// projectFlags holds the project changes requested on the command line.
+type projectFlags struct {
+	name   string
+	fields []service.FieldAssignment
+}
+
+// parseProjectFlags returns nil when no project flag was given.
+func parseProjectFlags(cmd *cobra.Command) (*projectFlags, error) {
+	flags := cmd.Flags()
+	if !flags.Changed("project") && !flags.Changed("status") && !flags.Changed("field") {
+		return nil, nil
+	}
+
+	p := &projectFlags{name: flagProject}
+	if flags.Changed("status") {
+		p.fields = append(p.fields, service.FieldAssignment{Field: "status", Value: flagStatus})
+	}
+	for _, f := range flagFields {
+		name, value, ok := strings.Cut(f, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --field %q: use name=value", f)
+		}
+		p.fields = append(p.fields, service.FieldAssignment{
+			Field: strings.TrimSpace(name),
+			Value: strings.TrimSpace(value),
+		})
+	}
+	return p, nil
+}
+
+func (p *projectFlags) print() {
+	name := p.name
+	if name == "" {
+		name = "(default)"
+	}
+	fmt.Printf("Project:    %s\n", name)
+	for _, f := range p.fields {
+		fmt.Printf("  %-9s %s\n", f.Field+":", f.Value)
+	}
+}
+
+func applyProjectFlags(ctx context.Context, ghClient github.Client, cfg *config.Config, number int, p *projectFlags) error {
+	svc := service.NewProjectService(ghClient, cfg)
+	project, err := svc.AddIssue(ctx, number, p.name, p.fields)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added #%d to project %s\n", number, project.Title)
+	return nil
+}
+
+func newProjectService() (*service.ProjectService, *config.Config, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.NewProjectService(ghClient, cfg), cfg, nil
+}
+
+func runProjectList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Projects) == 0 {
+		fmt.Println("No projects configured. Add one under projects in .grit/config.yaml.")
+		return nil
+	}
+
+	for _, b := range cfg.Projects {
+		ref := b.ID
+		if ref == "" {
+			ref = fmt.Sprintf("%s/%d", b.Owner, b.Number)
+		}
+		line := fmt.Sprintf("%-16s %s", b.Name, ref)
+		if b.Default || len(cfg.Projects) == 1 {
+			line += "  (default)"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runProjectFields(cmd *cobra.Command, args []string) error {
+	svc, _, err := newProjectService()
+	if err != nil {
+		return err
+	}
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	project, board, err := svc.Board(cmd.Context(), name)
+	if err != nil {
+		return err
+	}
+
+	aliases := make(map[string][]string)
+	for alias, field := range board.Fields {
+		aliases[strings.ToLower(field)] = append(aliases[strings.ToLower(field)], alias)
+	}
+
+	fmt.Printf("%s (%s)\n\n", project.Title, project.URL)
+	for _, f := range project.Fields {
+		line := fmt.Sprintf("%-20s %s", f.Name, strings.ToLower(f.DataType))
+		if a := aliases[strings.ToLower(f.Name)]; len(a) > 0 {
+			sort.Strings(a)
+			line += fmt.Sprintf("  alias: %s", strings.Join(a, ", "))
+		}
+		fmt.Println(line)
+
+		for _, o := range f.Options {
+			fmt.Printf("    - %s\n", o.Name)
+		}
+		for _, it := range f.Iterations {
+			fmt.Printf("    - %s (from %s, %d days)\n", it.Title, it.StartDate, it.Duration)
+		}
+	}
+	return nil
+}
+
+func runProjectItem(cmd *cobra.Command, args []string) error {
+	cfg, number, err := loadIssueRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+	svc := service.NewProjectService(ghClient, cfg)
+
+	items, err := svc.Items(cmd.Context(), number)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		fmt.Printf("#%d is not in any project.\n", number)
+		return nil
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s (%s)\n", item.Project.Title, item.Project.URL)
+		for _, v := range item.Values {
+			fmt.Printf("  %-20s %s\n", v.Field, v.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/offline"
+	"github.com/dulait/grit/internal/service"
+)
+
+var (
+	flagRepo     string
+	flagAllRepos bool
+	flagGlobal   bool
+)
+
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Manage the list of known repositories",
+	Long: `Manage the repositories used by cross-repository listing and search
+(grit issue list --all-repos, grit issue search --all-repos).
+
+Inside a grit project the list is kept in .grit/config.yaml; use --global,
+or run outside a project, to keep it in the user configuration instead.`,
+}
+
+var repoAddCmd = &cobra.Command{
+	Use:   "add <owner/name>...",
+	Short: "Register repositories",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runRepoAdd,
+}
+
+var repoListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List known repositories",
+	Args:  cobra.NoArgs,
+	RunE:  runRepoList,
+}
+
+var repoRemoveCmd = &cobra.Command{
+	Use:   "remove <owner/name>...",
+	Short: "Unregister repositories",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runRepoRemove,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&flagRepo, "repo", "R", "", "Target repository as owner/name instead of the current project's")
+
+	rootCmd.AddCommand(repoCmd)
+	repoCmd.AddCommand(repoAddCmd)
+	repoCmd.AddCommand(repoListCmd)
+	repoCmd.AddCommand(repoRemoveCmd)
+
+	repoAddCmd.Flags().BoolVar(&flagGlobal, "global", false, "Use the user configuration even inside a project")
+	repoRemoveCmd.Flags().BoolVar(&flagGlobal, "global", false, "Use the user configuration even inside a project")
+}
+
+func runRepoAdd(cmd *cobra.Command, args []string) error {
+	cfg, save, where, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range args {
+		added, err := cfg.AddRepository(repo)
+		if err != nil {
+			return err
+		}
+		if !added {
+			fmt.Printf("%s is already registered\n", repo)
+		}
+	}
+
+	if err := save(); err != nil {
+		return err
+	}
+	fmt.Printf("Repositories saved to %s\n", where)
+	return nil
+}
+
+func runRepoList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil && !errors.Is(err, config.ErrNoProject) {
+		return err
+	}
+	if cfg == nil {
+		if cfg, err = config.LoadUser(); err != nil {
+			return err
+		}
+	}
+
+	repos := cfg.RepoList()
+	if len(repos) == 0 {
+		fmt.Println("No repositories registered. Add some with 'grit repo add owner/name'.")
+		return nil
+	}
+	for _, repo := range repos {
+		if strings.EqualFold(repo, cfg.Project.FullName()) {
+			fmt.Printf("%s (current)\n", repo)
+		} else {
+			fmt.Println(repo)
+		}
+	}
+	return nil
+}
+
+func runRepoRemove(cmd *cobra.Command, args []string) error {
+	cfg, save, where, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range args {
+		if !cfg.RemoveRepository(repo) {
+			fmt.Printf("%s is not registered in %s\n", repo, where)
+		}
+	}
+
+	if err := save(); err != nil {
+		return err
+	}
+	fmt.Printf("Repositories saved to %s\n", where)
+	return nil
+}
+
+// loadRegistry loads the configuration that holds the repository registry:
+// the project configuration, or the user configuration with --global or
+// outside a project. It returns a function that saves it and the path it is
+// saved to.
+func loadRegistry() (*config.Config, func() error, string, error) {
+	if !flagGlobal {
+		root, err := config.FindRoot()
+		if err == nil {
+			cfg, err := config.Load(root)
+			if err != nil {
+				return nil, nil, "", err
+			}
+			return cfg, func() error { return config.Save(root, cfg) }, config.Path(root), nil
+		}
+		if !errors.Is(err, config.ErrNoProject) {
+			return nil, nil, "", err
+		}
+	}
+
+	cfg, err := config.LoadUser()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	path, err := config.UserPath()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return cfg, func() error { return config.SaveUser(cfg) }, path, nil
+}
+
+// loadConfig loads the project configuration, switched to the repository
+// given with --repo. With --repo it also works outside a project, using the
+// user configuration. Repositories registered in the user configuration are
+// added to the project's registry, and its token sources are used when the
+// project sets none.
+func loadConfig() (*config.Config, error) {
+	return loadRepoConfig(flagRepo)
+}
+
+func loadRepoConfig(repo string) (*config.Config, error) {
+	cfg, err := config.LoadFromWorkingDir()
+	switch {
+	case err == nil:
+		if user, err := config.LoadUser(); err == nil {
+			cfg.MergeRepositories(user.Repositories)
+			if len(cfg.Auth.TokenSources) == 0 {
+				cfg.Auth.TokenSources = user.Auth.TokenSources
+			}
+			cfg.Cache.Dir = cmp.Or(cfg.Cache.Dir, user.Cache.Dir)
+			cfg.Cache.MaxSizeMB = cmp.Or(cfg.Cache.MaxSizeMB, user.Cache.MaxSizeMB)
+		}
+	case errors.Is(err, config.ErrNoProject) && repo != "":
+		cfg, err = config.LoadUser()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if repo == "" {
+		return cfg, nil
+	}
+	return cfg.ForRepo(repo)
+}
+
+// loadIssueRef parses an issue reference and loads the configuration for
+// the repository it is in.
+func loadIssueRef(ref string) (*config.Config, int, error) {
+	repo, number, err := parseIssueRef(ref)
+	if err != nil {
+		return nil, 0, err
+	}
+	if repo == "" {
+		repo = flagRepo
+	}
+	cfg, err := loadRepoConfig(repo)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cfg, number, nil
+}
+
+// issueRefIn parses a second issue reference, such as a link target. A
+// reference without a repository, or to cfg's own, is to an issue in cfg's
+// repository and comes back without one.
+func issueRefIn(cfg *config.Config, ref string) (github.IssueRef, error) {
+	repo, number, err := parseIssueRef(ref)
+	if err != nil {
+		return github.IssueRef{}, err
+	}
+	if strings.EqualFold(repo, cfg.Project.FullName()) {
+		repo = ""
+	}
+	return github.IssueRef{Repo: repo, Number: number}, nil
+}
+
+// parseIssueRef parses "123", "#123" or "owner/repo#123", or the name of
+// an issue created offline, such as "draft-1". The repository is empty
+// unless given.
+func parseIssueRef(ref string) (repo string, number int, err error) {
+	if number, ok := offline.ParseDraft(ref); ok {
+		return "", number, nil
+	}
+	repo, num, found := strings.Cut(ref, "#")
+	if !found {
+		repo, num = "", ref
+	}
+	number, err = strconv.Atoi(num)
+	if err != nil || number <= 0 {
+		return "", 0, fmt.Errorf("invalid issue number: %s", ref)
+	}
+	if repo != "" {
+		owner, name, err := config.ParseRepo(repo)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid issue reference %s: %w", ref, err)
+		}
+		repo = owner + "/" + name
+	}
+	return repo, number, nil
+}
+
+// loadCrossRepo loads the configuration and repository list used by
+// --all-repos. Outside a project the client targets the first registered
+// repository.
+func loadCrossRepo() (*config.Config, []string, error) {
+	cfg, err := loadConfig()
+	if errors.Is(err, config.ErrNoProject) {
+		cfg, err = config.LoadUser()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repos := cfg.RepoList()
+	if len(repos) == 0 {
+		return nil, nil, errors.New("no repositories registered; add some with 'grit repo add owner/name'")
+	}
+	if cfg.Project.Repo == "" {
+		if cfg, err = cfg.ForRepo(repos[0]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return cfg, repos, nil
+}
+
+func runCrossRepoList(cmd *cobra.Command) error {
+	cfg, repos, err := loadCrossRepo()
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	if flagAssignee == "*" {
+		return errors.New("--assignee '*' is not supported with --all-repos")
+	}
+	milestone, err := crossRepoMilestone(flagMilestone)
+	if err != nil {
+		return err
+	}
+
+	req := github.SearchIssuesRequest{
+		Repos:     repos,
+		State:     flagState,
+		Assignee:  flagAssignee,
+		Labels:    flagLabel,
+		Milestone: milestone,
+		Sort:      "created",
+
+		IncludePullRequests: flagIncludePRs,
+	}
+	return searchIssues(cmd, service.NewIssueService(ghClient, nil, cfg), req)
+}
+
+func runCrossRepoSearch(cmd *cobra.Command, query string) error {
+	cfg, repos, err := loadCrossRepo()
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	milestone, err := crossRepoMilestone(flagMilestone)
+	if err != nil {
+		return err
+	}
+
+	req := github.SearchIssuesRequest{
+		Query:     query,
+		Repos:     repos,
+		State:     flagState,
+		Labels:    flagLabel,
+		Milestone: milestone,
+
+		IncludePullRequests: flagIncludePRs,
+	}
+	return searchIssues(cmd, service.NewIssueService(ghClient, nil, cfg), req)
+}
+
+// crossRepoMilestone checks a --milestone value used with --all-repos.
+// Milestone numbers differ between repositories, so only titles and "none"
+// are accepted.
+func crossRepoMilestone(ref string) (string, error) {
+	if ref == "*" {
+		return "", errors.New("--milestone '*' is not supported with --all-repos")
+	}
+	if _, err := strconv.Atoi(ref); err == nil {
+		return "", fmt.Errorf("--milestone %s: use a milestone title with --all-repos; numbers differ between repositories", ref)
+	}
+	if strings.EqualFold(ref, "none") {
+		return "none", nil
+	}
+	return ref, nil
+}
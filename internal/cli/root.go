@@ -1,11 +1,15 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
 	"github.com/dulait/grit/internal/tui"
 )
 
@@ -16,15 +20,38 @@ var (
 	BuildDate = "unknown"
 )
 
+// OAuthClientID is the OAuth app used by 'grit auth login' on github.com,
+// set at build time via ldflags.
+var OAuthClientID = ""
+
 var rootCmd = &cobra.Command{
 	Use:   "grit",
 	Short: "A CLI tool for managing GitHub issues",
 	Long:  "grit allows you to create, close, comment on, and manage GitHub issues from the command line.",
 	RunE:  runTUI,
+
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		warnRateLimit(cmd, args)
+		noteOffline(cmd)
+	},
+}
+
+// activeGitHubClient is the client built for the running command, if any.
+var activeGitHubClient github.Client
+
+func warnRateLimit(cmd *cobra.Command, args []string) {
+	if activeGitHubClient == nil {
+		return
+	}
+	rl := activeGitHubClient.RateLimit()
+	if rl.Low() {
+		fmt.Fprintf(os.Stderr, "Warning: GitHub rate limit low (%d/%d remaining, resets at %s)\n",
+			rl.Remaining, rl.Limit, rl.Reset.Local().Format("15:04"))
+	}
 }
 
 func runTUI(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadFromWorkingDir()
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
@@ -35,16 +62,23 @@ func runTUI(cmd *cobra.Command, args []string) error {
 	}
 
 	llmClient, _ := buildLLMClient(cfg)
+	root, _ := config.FindRoot()
 
 	deps := tui.Dependencies{
 		Config:       cfg,
 		GitHubClient: ghClient,
 		LLMClient:    llmClient,
+		Root:         root,
 	}
 	return tui.Run(deps)
 }
 
+// flagNoCache sends every request in full instead of revalidating cached
+// responses.
+var flagNoCache bool
+
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagNoCache, "no-cache", false, "Fetch every response from GitHub instead of revalidating cached copies")
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -60,5 +94,19 @@ var versionCmd = &cobra.Command{
 
 // Execute runs the root command.
 func Execute() error {
-	return rootCmd.Execute()
+	return explainError(rootCmd.Execute())
+}
+
+// explainError adds a remediation hint to GitHub errors the user can fix.
+func explainError(err error) error {
+	var unauthorized *github.UnauthorizedError
+	var forbidden *github.ForbiddenError
+	switch {
+	case errors.As(err, &unauthorized):
+		return fmt.Errorf("%w\nrun 'grit auth login' to store a valid token", err)
+	case errors.As(err, &forbidden) && len(forbidden.MissingScopes()) > 0:
+		return fmt.Errorf("%w\ncreate a token with the %s scope and run 'grit auth login'",
+			err, strings.Join(forbidden.MissingScopes(), " or "))
+	}
+	return err
 }
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/service"
+)
+
+var (
+	flagAfter  string
+	flagBefore string
+)
+
+var subAddCmd = &cobra.Command{
+	Use:   "add <parent-number> <child-number>",
+	Short: "Attach an existing issue as a sub-issue",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runSubAdd,
+}
+
+var subRemoveCmd = &cobra.Command{
+	Use:   "remove <parent-number> <child-number>",
+	Short: "Detach a sub-issue from its parent",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runSubRemove,
+}
+
+var subListCmd = &cobra.Command{
+	Use:   "list <parent-number>",
+	Short: "List the sub-issues of an issue",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSubList,
+}
+
+var subMoveCmd = &cobra.Command{
+	Use:   "move <parent-number> <child-number>",
+	Short: "Reprioritize a sub-issue",
+	Long:  "Move a sub-issue directly after or before one of its siblings.",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runSubMove,
+}
+
+func init() {
+	issueSubCmd.AddCommand(subAddCmd)
+	issueSubCmd.AddCommand(subRemoveCmd)
+	issueSubCmd.AddCommand(subListCmd)
+	issueSubCmd.AddCommand(subMoveCmd)
+
+	subMoveCmd.Flags().StringVar(&flagAfter, "after", "", "Place after this sibling issue")
+	subMoveCmd.Flags().StringVar(&flagBefore, "before", "", "Place before this sibling issue")
+	subMoveCmd.MarkFlagsMutuallyExclusive("after", "before")
+	subMoveCmd.MarkFlagsOneRequired("after", "before")
+}
+
+// loadParentChild parses the parent and child issue references and loads
+// the configuration for the parent's repository. The child may be in
+// another repository.
+func loadParentChild(args []string) (*config.Config, int, github.IssueRef, error) {
+	cfg, parent, err := loadIssueRef(args[0])
+	if err != nil {
+		return nil, 0, github.IssueRef{}, err
+	}
+	child, err := issueRefIn(cfg, args[1])
+	if err != nil {
+		return nil, 0, github.IssueRef{}, err
+	}
+	return cfg, parent, child, nil
+}
+
+func runSubAdd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, parent, child, err := loadParentChild(args)
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	native, err := svc.AttachSubIssue(ctx, github.IssueRef{Number: parent}, child)
+	if err != nil {
+		return err
+	}
+
+	if native {
+		fmt.Printf("Added %s as a sub-issue of #%d\n", child, parent)
+	} else {
+		fmt.Printf("Sub-issues API unavailable; referenced #%d from the body of %s\n", parent, child)
+	}
+	return nil
+}
+
+func runSubRemove(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, parent, child, err := loadParentChild(args)
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	native, err := svc.DetachSubIssue(ctx, github.IssueRef{Number: parent}, child)
+	if err != nil {
+		return err
+	}
+
+	if native {
+		fmt.Printf("Removed %s from the sub-issues of #%d\n", child, parent)
+	} else {
+		fmt.Printf("Sub-issues API unavailable; removed the #%d reference from %s\n", parent, child)
+	}
+	return nil
+}
+
+func runSubList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, parent, err := loadIssueRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	issues, err := svc.ListSubIssues(ctx, parent)
+	if err != nil {
+		return err
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("#%d has no sub-issues.\n", parent)
+		return nil
+	}
+
+	closed := 0
+	for _, issue := range issues {
+		if issue.State == "closed" {
+			closed++
+		}
+	}
+	fmt.Printf("Sub-issues of #%d (%d/%d completed)\n\n", parent, closed, len(issues))
+	printIssueList(issues)
+	return nil
+}
+
+func runSubMove(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, parent, child, err := loadParentChild(args)
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	svc := service.NewIssueService(ghClient, nil, cfg)
+
+	var pos github.SubIssuePosition
+	if flagAfter != "" {
+		pos.After, err = issueRefIn(cfg, flagAfter)
+	} else {
+		pos.Before, err = issueRefIn(cfg, flagBefore)
+	}
+	if err != nil {
+		return err
+	}
+	if err := svc.ReorderSubIssue(ctx, parent, child, pos); err != nil {
+		return err
+	}
+
+	if flagAfter != "" {
+		fmt.Printf("Moved %s after %s in #%d\n", child, pos.After, parent)
+	} else {
+		fmt.Printf("Moved %s before %s in #%d\n", child, pos.Before, parent)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/offline"
+)
+
+// envOffline forces offline mode when set to a true value.
+const envOffline = "GRIT_OFFLINE"
+
+var (
+	flagOffline bool
+	flagForce   bool
+	flagDiscard bool
+	flagPending bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Send changes made offline and refresh the offline copy of issues",
+	Long: `Send the changes queued while offline to GitHub, in the order they were
+made, then refresh the copy of the repository's issues kept in .grit/offline
+for use when GitHub cannot be reached.
+
+A queued change is refused as a conflict when its issue was updated on
+GitHub after the change was made. Conflicting changes, and those GitHub
+rejects, stay queued along with later changes to the same issue until they
+are sent with --force or dropped with --discard.`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagOffline, "offline", false, "Work from the offline copy of issues without contacting GitHub (or set "+envOffline+")")
+
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().BoolVar(&flagForce, "force", false, "Send conflicting changes anyway, overwriting the updates on GitHub")
+	syncCmd.Flags().BoolVar(&flagDiscard, "discard", false, "Drop conflicting and rejected changes instead of keeping them queued")
+	syncCmd.Flags().BoolVar(&flagPending, "pending", false, "List the queued changes without syncing")
+	syncCmd.MarkFlagsMutuallyExclusive("force", "discard")
+}
+
+// offlineForced reports whether offline mode was asked for with --offline
+// or GRIT_OFFLINE.
+func offlineForced() bool {
+	if flagOffline {
+		return true
+	}
+	forced, _ := strconv.ParseBool(os.Getenv(envOffline))
+	return forced
+}
+
+// withOfflineStore wraps client so that issues are read from, and changes
+// queued in, the project's offline store when GitHub cannot be reached.
+// Outside a project there is no store, and client is returned as is.
+func withOfflineStore(cfg *config.Config, client github.Client) (github.Client, error) {
+	root, err := config.FindRoot()
+	if err != nil {
+		if offlineForced() {
+			return nil, errors.New("offline mode needs a grit project to keep issues in; run 'grit init' first")
+		}
+		return client, nil
+	}
+
+	store, err := offline.Open(cfg.Project.OfflineDir(root))
+	if err != nil {
+		return nil, err
+	}
+	oc := offline.NewClient(client, store, cfg.Project.FullName(), offlineForced())
+	activeGitHubClient = oc
+	return oc, nil
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+	oc, ok := ghClient.(*offline.Client)
+	if !ok {
+		return errors.New("grit sync needs a grit project; run 'grit init' first")
+	}
+
+	if flagPending {
+		printPending(oc.Store())
+		return nil
+	}
+
+	res, err := oc.Sync(cmd.Context(), offline.SyncOptions{Force: flagForce, Discard: flagDiscard})
+	if res != nil {
+		printSyncResult(res)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Synced %s (%d issues updated)\n", cfg.Project.FullName(), res.Fetched)
+	return nil
+}
+
+func printSyncResult(res *offline.SyncResult) {
+	if len(res.Sent) > 0 {
+		fmt.Println("Sent to GitHub:")
+		for _, ch := range res.Sent {
+			if number, ok := res.Created[ch.Number]; ok && ch.Kind == offline.KindCreate {
+				fmt.Printf("  %s as #%d\n", ch, number)
+			} else {
+				fmt.Printf("  %s\n", ch)
+			}
+		}
+	}
+	if len(res.Conflicts) > 0 {
+		fmt.Println("Conflicts, kept queued:")
+		for _, ch := range res.Conflicts {
+			fmt.Printf("  %s: %s\n", ch, ch.Conflict)
+		}
+		fmt.Println("Review the issues on GitHub, then run 'grit sync --force' to apply the changes or 'grit sync --discard' to drop them.")
+	}
+	if len(res.Failed) > 0 {
+		fmt.Println("Rejected by GitHub, kept queued:")
+		for _, ch := range res.Failed {
+			fmt.Printf("  %s: %s\n", ch, ch.Error)
+		}
+		fmt.Println("Run 'grit sync --discard' to drop them.")
+	}
+	if len(res.Discarded) > 0 {
+		fmt.Println("Discarded:")
+		for _, ch := range res.Discarded {
+			fmt.Printf("  %s\n", ch)
+		}
+	}
+}
+
+func printPending(store *offline.Store) {
+	changes := store.Pending()
+	if len(changes) == 0 {
+		fmt.Println("No changes waiting to sync.")
+	} else {
+		fmt.Println("Waiting to sync:")
+		for _, ch := range changes {
+			line := fmt.Sprintf("  %3d  %s  %s", ch.ID, ch.QueuedAt.Local().Format("2006-01-02 15:04"), ch)
+			switch {
+			case ch.Conflict != "":
+				line += " (conflict: " + ch.Conflict + ")"
+			case ch.Error != "":
+				line += " (rejected: " + ch.Error + ")"
+			}
+			fmt.Println(line)
+		}
+	}
+	printSyncedAt(store.SyncedAt())
+}
+
+func printSyncedAt(at time.Time) {
+	if at.IsZero() {
+		fmt.Println("The offline copy has never been synced; run 'grit sync' while online.")
+		return
+	}
+	fmt.Printf("Last synced %s.\n", at.Local().Format("2006-01-02 15:04"))
+}
+
+// noteOffline tells the user when the command worked from the offline
+// store, or left changes waiting to be synced.
+func noteOffline(cmd *cobra.Command) {
+	oc, ok := activeGitHubClient.(*offline.Client)
+	if !ok || cmd == syncCmd {
+		return
+	}
+	waiting := len(oc.Store().Pending())
+	switch {
+	case oc.Queued() > 0:
+		fmt.Fprintf(os.Stderr, "Offline: queued for GitHub (%d waiting); run 'grit sync' when back online\n", waiting)
+	case oc.ServedFromStore():
+		synced := "never synced"
+		if at := oc.Store().SyncedAt(); !at.IsZero() {
+			synced = "last synced " + at.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(os.Stderr, "Offline: showing the offline copy of issues (%s)\n", synced)
+	case waiting > 0:
+		fmt.Fprintf(os.Stderr, "Changes made offline are waiting (%d); run 'grit sync' to send them to GitHub\n", waiting)
+	}
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/service"
+)
+
+var flagTemplate string
+
+var issueTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List the repository's issue templates",
+	Long: `List the issue templates and issue forms in .github/ISSUE_TEMPLATE.
+
+Templates are read from the local checkout when it has them, and from the
+repository on GitHub otherwise. Pass a template's name to 'grit issue create --template'.`,
+	Args: cobra.NoArgs,
+	RunE: runIssueTemplates,
+}
+
+func init() {
+	issueCmd.AddCommand(issueTemplatesCmd)
+
+	issueCreateCmd.Flags().StringVar(&flagTemplate, "template", "", "Issue template or form to use, by file name or display name")
+}
+
+func runIssueTemplates(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	ghClient, err := buildGitHubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	templates, err := newTemplateService(ghClient).List(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		fmt.Printf("No issue templates found in %s.\n", service.TemplateDir)
+		return nil
+	}
+
+	for _, t := range templates {
+		kind := "template"
+		if t.Form {
+			kind = "form"
+		}
+		fmt.Printf("%-20s %-8s %s\n", t.Slug, kind, t.Name)
+		if t.About != "" {
+			fmt.Printf("%-29s %s\n", "", truncate(t.About, 70))
+		}
+		if len(t.Labels) > 0 {
+			fmt.Printf("%-29s labels: %s\n", "", strings.Join(t.Labels, ", "))
+		}
+	}
+	return nil
+}
+
+func newTemplateService(gh github.Client) *service.TemplateService {
+	root, _ := config.FindRoot()
+	return service.NewTemplateService(gh, root)
+}
+
+// findTemplate returns the template named by --template, or nil when the
+// flag is not set.
+func findTemplate(ctx context.Context, gh github.Client) (*service.IssueTemplate, error) {
+	if flagTemplate == "" {
+		return nil, nil
+	}
+	return newTemplateService(gh).Find(ctx, flagTemplate)
+}
+
+// gatherFormInput builds issue input for an issue form by prompting for
+// each of its fields. A prompt given on the command line is passed to the
+// LLM alongside the answers.
+func gatherFormInput(cfg *config.Config, args []string, t *service.IssueTemplate) (service.IssueInput, error) {
+	if flagDescription != "" {
+		return service.IssueInput{}, fmt.Errorf("--description cannot be used with the %q issue form; its fields are prompted for instead", t.Slug)
+	}
+
+	input := service.IssueInput{
+		Title:     flagTitle,
+		Labels:    validateLabels(parseCSV(flagLabels), cfg.Project.LabelNames()),
+		Assignees: parseCSV(flagAssignees),
+	}
+	if len(args) > 0 {
+		input.Prompt = strings.Join(args, " ")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf("%s\n", t.Name)
+	if t.About != "" {
+		fmt.Println(t.About)
+	}
+	fmt.Println()
+
+	if input.Title == "" && input.Prompt == "" {
+		title, err := promptOptional(reader, "Title")
+		if err != nil {
+			return input, err
+		}
+		input.Title = title
+	}
+
+	answers := make(map[string][]string)
+	for _, f := range t.Inputs() {
+		values, err := promptFormField(reader, f)
+		if err != nil {
+			return input, err
+		}
+		answers[f.Key()] = values
+	}
+	input.Description = t.RenderForm(answers)
+
+	return input, nil
+}
+
+// promptFormField asks for a response to one form field until it passes
+// validation.
+func promptFormField(reader *bufio.Reader, f service.FormField) ([]string, error) {
+	label := f.Label
+	if f.Required {
+		label += " (required)"
+	}
+	fmt.Println(label)
+	if f.Description != "" {
+		fmt.Printf("  %s\n", f.Description)
+	}
+	for i, o := range f.Options {
+		fmt.Printf("  %d. %s\n", i+1, o.Label)
+	}
+
+	for {
+		values, err := readFormField(reader, f)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 && f.Value != "" && f.Type != service.FormCheckboxes {
+			values = []string{f.Value}
+		}
+		if err := f.Validate(values); err != nil {
+			fmt.Printf("  %v\n", err)
+			continue
+		}
+		fmt.Println()
+		return values, nil
+	}
+}
+
+func readFormField(reader *bufio.Reader, f service.FormField) ([]string, error) {
+	switch f.Type {
+	case service.FormTextarea:
+		fmt.Println("  (finish with an empty line)")
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil && line == "" {
+				return nil, fmt.Errorf("reading input: %w", err)
+			}
+			line = strings.TrimRight(line, "\r\n")
+			if line == "" {
+				break
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			return nil, nil
+		}
+		return []string{strings.Join(lines, "\n")}, nil
+
+	case service.FormDropdown, service.FormCheckboxes:
+		prompt := "Choice"
+		if f.Multiple || f.Type == service.FormCheckboxes {
+			prompt = "Choices (comma-separated)"
+		}
+		answer, err := promptOptional(reader, prompt)
+		if err != nil {
+			return nil, err
+		}
+		return optionChoices(f, parseCSV(answer)), nil
+
+	default:
+		answer, err := promptOptional(reader, "Answer")
+		if err != nil || answer == "" {
+			return nil, err
+		}
+		return []string{answer}, nil
+	}
+}
+
+// optionChoices maps option numbers to their labels, leaving other
+// answers as typed.
+func optionChoices(f service.FormField, answers []string) []string {
+	choices := make([]string, len(answers))
+	for i, a := range answers {
+		if n, err := strconv.Atoi(a); err == nil && n >= 1 && n <= len(f.Options) {
+			a = f.Options[n-1].Label
+		}
+		choices[i] = a
+	}
+	return choices
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	envAppID             = "GRIT_APP_ID"
+	envAppInstallationID = "GRIT_APP_INSTALLATION_ID"
+	envAppPrivateKey     = "GRIT_APP_PRIVATE_KEY"
+	envAppPrivateKeyPath = "GRIT_APP_PRIVATE_KEY_PATH"
+)
+
+// AppConfig configures authentication as a GitHub App installation instead
+// of with a personal access token.
+type AppConfig struct {
+	// ID is the app's numeric ID or its client ID.
+	ID string `yaml:"id"`
+	// InstallationID is the installation to act as. When unset, the app's
+	// installation on the project's repository is used.
+	InstallationID int64 `yaml:"installation_id,omitempty"`
+	// PrivateKeyPath is the path to the app's PEM private key. Relative
+	// paths are resolved against the project root. When unset, the key is
+	// read from GRIT_APP_PRIVATE_KEY or the keyring.
+	PrivateKeyPath string `yaml:"private_key_path,omitempty"`
+}
+
+// AppAuth returns the GitHub App settings for cfg, with GRIT_APP_ID,
+// GRIT_APP_INSTALLATION_ID and GRIT_APP_PRIVATE_KEY_PATH taking priority
+// over the config file. It returns nil when no app is configured.
+func AppAuth(cfg *Config) (*AppConfig, error) {
+	var app AppConfig
+	if cfg.App != nil {
+		app = *cfg.App
+	}
+
+	if id := os.Getenv(envAppID); id != "" {
+		app.ID = id
+	}
+	if id := os.Getenv(envAppInstallationID); id != "" {
+		n, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", envAppInstallationID, id, err)
+		}
+		app.InstallationID = n
+	}
+	if path := os.Getenv(envAppPrivateKeyPath); path != "" {
+		app.PrivateKeyPath = path
+	}
+
+	if app.ID == "" {
+		return nil, nil
+	}
+	return &app, nil
+}
+
+// AppKey returns the key the app's private key is stored under in the
+// keyring.
+func AppKey(app *AppConfig) string {
+	return "app/" + app.ID
+}
+
+// DeleteAppPrivateKey removes the app's stored private key from every
+// backend that holds it and returns the names of those backends.
+func DeleteAppPrivateKey(app *AppConfig) ([]string, error) {
+	return DeleteSecret(keyringService, AppKey(app))
+}
+
+// AppPrivateKey returns the app's PEM private key, checking in order the
+// GRIT_APP_PRIVATE_KEY environment variable, the configured key file and
+// the keyring.
+func AppPrivateKey(app *AppConfig) ([]byte, error) {
+	if key := os.Getenv(envAppPrivateKey); key != "" {
+		return []byte(key), nil
+	}
+
+	if app.PrivateKeyPath != "" {
+		path, err := resolvePath(app.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading GitHub App private key: %w", err)
+		}
+		return data, nil
+	}
+
+	key, err := (&SecretTokenStore{}).Get(AppKey(app))
+	if err != nil {
+		return nil, fmt.Errorf("no private key found for GitHub App %s; set private_key_path, set %s or run 'grit auth login --app-id'", app.ID, envAppPrivateKey)
+	}
+	return []byte(key), nil
+}
+
+// resolvePath expands a leading ~ and resolves relative paths against
+// the project root.
+func resolvePath(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~"); ok && (rest == "" || rest[0] == '/' || rest[0] == filepath.Separator) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("locating home directory: %w", err)
+		}
+		return filepath.Join(home, rest), nil
+	}
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+
+	root, err := FindRoot()
+	if errors.Is(err, ErrNoProject) {
+		return path, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, path), nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheDir returns the directory grit caches data fetched from GitHub in,
+// under the user cache directory.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "grit"), nil
+}
+
+// CacheConfig configures the cache of GitHub API responses.
+type CacheConfig struct {
+	// Dir is where responses are kept. A leading ~ is the home directory,
+	// and relative paths are resolved against the project root. When
+	// unset, the http directory under CacheDir is used.
+	Dir string `yaml:"dir,omitempty"`
+	// MaxSizeMB is the size, in megabytes, the cache is kept under. When
+	// unset, it is 50.
+	MaxSizeMB int `yaml:"max_size_mb,omitempty"`
+}
+
+// HTTPDir returns the directory GitHub API responses are cached in.
+func (c CacheConfig) HTTPDir() (string, error) {
+	if c.Dir != "" {
+		return resolvePath(c.Dir)
+	}
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "http"), nil
+}
+
+// MaxSize returns the size, in bytes, the cache is kept under, or zero
+// for the default.
+func (c CacheConfig) MaxSize() int64 {
+	return int64(max(c.MaxSizeMB, 0)) << 20
+}
+
+// RepoCachePath returns the path of a cache file for the project's
+// repository, so that each host and repository has its own copy. A port in
+// the host is kept with an underscore, since Windows forbids colons.
+func (p ProjectConfig) RepoCachePath(name string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, p.repoDir(), name), nil
+}
+
+// OfflineDir returns the directory in the project at root that keeps the
+// offline copy of the repository's issues.
+func (p ProjectConfig) OfflineDir(root string) string {
+	return filepath.Join(DirPath(root), "offline", p.repoDir())
+}
+
+func (p ProjectConfig) repoDir() string {
+	host := strings.ReplaceAll(p.HostName(), ":", "_")
+	return filepath.Join(host, p.Owner, p.Repo)
+}
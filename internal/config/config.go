@@ -1,19 +1,91 @@
 package config
 
+import "strings"
+
+// DefaultHost is the host name of the public GitHub instance.
+const DefaultHost = "github.com"
+
 // Config represents the complete grit configuration for a project.
 type Config struct {
-	Version int           `yaml:"version"`
-	Project ProjectConfig `yaml:"project"`
-	LLM     LLMConfig     `yaml:"llm"`
+	Version  int           `yaml:"version"`
+	Project  ProjectConfig `yaml:"project,omitempty"`
+	Projects []BoardConfig `yaml:"projects,omitempty"`
+	// Repositories lists other repositories, as owner/name, used by
+	// cross-repository listing and search.
+	Repositories []string `yaml:"repositories,omitempty"`
+	// App, when set, authenticates as a GitHub App installation instead of
+	// with a personal access token.
+	App   *AppConfig  `yaml:"github_app,omitempty"`
+	Auth  AuthConfig  `yaml:"auth,omitempty"`
+	Cache CacheConfig `yaml:"cache,omitempty"`
+	LLM   LLMConfig   `yaml:"llm"`
+
+	// home is the project's own repository when the configuration has been
+	// switched to another one with ForRepo.
+	home *ProjectConfig
 }
 
 // ProjectConfig defines the GitHub project settings.
 type ProjectConfig struct {
-	Owner       string   `yaml:"owner"`
-	Repo        string   `yaml:"repo"`
-	IssuePrefix string   `yaml:"issue_prefix,omitempty"`
-	Labels      []string `yaml:"labels,omitempty"`
-	Assignees   []string `yaml:"assignees,omitempty"`
+	Host        string        `yaml:"host,omitempty"`
+	Owner       string        `yaml:"owner"`
+	Repo        string        `yaml:"repo"`
+	IssuePrefix string        `yaml:"issue_prefix,omitempty"`
+	Labels      []LabelConfig `yaml:"labels,omitempty"`
+	Assignees   []string      `yaml:"assignees,omitempty"`
+	// OAuthClientID is the OAuth app used by 'grit auth login' on this
+	// host. It is only needed on GitHub Enterprise Server or to replace the
+	// built-in app.
+	OAuthClientID string `yaml:"oauth_client_id,omitempty"`
+}
+
+// HostName returns the configured GitHub host without a scheme, defaulting
+// to github.com.
+func (p ProjectConfig) HostName() string {
+	_, host := p.splitHost()
+	return host
+}
+
+// IsEnterprise reports whether the project targets a GitHub Enterprise
+// Server instance rather than github.com.
+func (p ProjectConfig) IsEnterprise() bool {
+	return p.HostName() != DefaultHost
+}
+
+// APIURL returns the REST API base URL for the configured host.
+func (p ProjectConfig) APIURL() string {
+	if !p.IsEnterprise() {
+		return "https://api.github.com"
+	}
+	return p.WebURL() + "/api/v3"
+}
+
+// GraphQLURL returns the GraphQL endpoint for the configured host.
+func (p ProjectConfig) GraphQLURL() string {
+	if !p.IsEnterprise() {
+		return "https://api.github.com/graphql"
+	}
+	return p.WebURL() + "/api/graphql"
+}
+
+// WebURL returns the browser base URL for the configured host.
+func (p ProjectConfig) WebURL() string {
+	scheme, host := p.splitHost()
+	return scheme + "://" + host
+}
+
+// splitHost separates an optional scheme from the configured host. A scheme
+// is only needed for non-TLS stand-in servers such as http://localhost:8080.
+func (p ProjectConfig) splitHost() (scheme, host string) {
+	host = strings.TrimSuffix(strings.TrimSpace(p.Host), "/")
+	scheme = "https"
+	if i := strings.Index(host, "://"); i >= 0 {
+		scheme, host = host[:i], host[i+3:]
+	}
+	if host == "" {
+		host = DefaultHost
+	}
+	return scheme, host
 }
 
 // LLMConfig defines the LLM provider settings.
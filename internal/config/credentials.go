@@ -0,0 +1,220 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/zalando/go-keyring"
+	"gopkg.in/yaml.v3"
+)
+
+// Token source names, as used in auth.token_sources.
+const (
+	SourceGritEnv       = "grit-env"
+	SourceKeyring       = "keyring"
+	SourceFile          = "file"
+	SourceEnv           = "env"
+	SourceGH            = "gh"
+	SourceGitCredential = "git-credential"
+)
+
+// DefaultTokenSources is the order token sources are tried in when
+// auth.token_sources is not set: grit's own variable and stored token,
+// then credentials already set up for other tools.
+var DefaultTokenSources = []string{SourceGritEnv, SourceKeyring, SourceEnv, SourceGH, SourceGitCredential}
+
+// AuthConfig configures where GitHub tokens are looked up.
+type AuthConfig struct {
+	// TokenSources lists the token sources to try, in order.
+	TokenSources []string `yaml:"token_sources,omitempty"`
+}
+
+var errReadOnlyStore = errors.New("tokens cannot be stored here")
+
+// NewTokenStore creates a store that tries the token sources configured in
+// cfg, or DefaultTokenSources, for the project's host. Tokens are saved to
+// the keyring, or the encrypted secret file without one.
+func NewTokenStore(cfg *Config) (*CompositeTokenStore, error) {
+	names := cfg.Auth.TokenSources
+	if len(names) == 0 {
+		names = DefaultTokenSources
+	}
+
+	scheme, host := cfg.Project.splitHost()
+	stores := make([]TokenStore, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case SourceGritEnv:
+			stores = append(stores, &EnvTokenStore{})
+		case SourceKeyring:
+			stores = append(stores, &SecretTokenStore{})
+		case SourceFile:
+			stores = append(stores, &FileTokenStore{})
+		case SourceEnv:
+			stores = append(stores, &GitHubEnvTokenStore{Enterprise: cfg.Project.IsEnterprise()})
+		case SourceGH:
+			stores = append(stores, &GHCLITokenStore{Host: host})
+		case SourceGitCredential:
+			stores = append(stores, &GitCredentialTokenStore{Protocol: scheme, Host: host})
+		default:
+			return nil, fmt.Errorf("unknown token source %q in auth.token_sources; use %s or %s", name, strings.Join(DefaultTokenSources, ", "), SourceFile)
+		}
+	}
+	return &CompositeTokenStore{stores: stores}, nil
+}
+
+// GitHubEnvTokenStore reads the GH_TOKEN and GITHUB_TOKEN variables used by
+// the gh CLI and GitHub Actions, or GH_ENTERPRISE_TOKEN and
+// GITHUB_ENTERPRISE_TOKEN for GitHub Enterprise Server.
+type GitHubEnvTokenStore struct {
+	Enterprise bool
+}
+
+func (s *GitHubEnvTokenStore) variables() []string {
+	if s.Enterprise {
+		return []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	return []string{"GH_TOKEN", "GITHUB_TOKEN"}
+}
+
+func (s *GitHubEnvTokenStore) Get(project string) (string, error) {
+	vars := s.variables()
+	for _, v := range vars {
+		if token := os.Getenv(v); token != "" {
+			return token, nil
+		}
+	}
+	return "", fmt.Errorf("environment variables %s not set", strings.Join(vars, " and "))
+}
+
+func (s *GitHubEnvTokenStore) Set(project string, token string) error {
+	return errReadOnlyStore
+}
+
+func (s *GitHubEnvTokenStore) Delete(project string) error {
+	return errReadOnlyStore
+}
+
+// GHCLITokenStore reads the token the gh CLI stored for a host, from its
+// hosts.yml file or, for newer versions of gh, the system keyring.
+type GHCLITokenStore struct {
+	Host string
+}
+
+func (s *GHCLITokenStore) Get(project string) (string, error) {
+	path, err := ghHostsPath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("gh is not logged in (no %s)", path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading gh hosts file: %w", err)
+	}
+
+	var hosts map[string]struct {
+		User       string `yaml:"user"`
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", fmt.Errorf("parsing gh hosts file: %w", err)
+	}
+
+	entry, ok := hosts[s.Host]
+	if !ok {
+		return "", fmt.Errorf("gh is not logged in to %s", s.Host)
+	}
+	if entry.OAuthToken != "" {
+		return entry.OAuthToken, nil
+	}
+
+	service := "gh:" + s.Host
+	if token, err := keyring.Get(service, ""); err == nil && token != "" {
+		return token, nil
+	}
+	if entry.User != "" {
+		if token, err := keyring.Get(service, entry.User); err == nil && token != "" {
+			return token, nil
+		}
+	}
+	return "", fmt.Errorf("no gh token found for %s", s.Host)
+}
+
+func (s *GHCLITokenStore) Set(project string, token string) error {
+	return errReadOnlyStore
+}
+
+func (s *GHCLITokenStore) Delete(project string) error {
+	return errReadOnlyStore
+}
+
+// ghHostsPath returns the location of gh's hosts.yml, following gh's own
+// lookup of GH_CONFIG_DIR, XDG_CONFIG_HOME and AppData.
+func ghHostsPath() (string, error) {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "hosts.yml"), nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh", "hosts.yml"), nil
+	}
+	if dir := os.Getenv("AppData"); runtime.GOOS == "windows" && dir != "" {
+		return filepath.Join(dir, "GitHub CLI", "hosts.yml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locating home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "gh", "hosts.yml"), nil
+}
+
+// gitCredentialTimeout bounds how long a credential helper may take.
+const gitCredentialTimeout = 10 * time.Second
+
+// GitCredentialTokenStore asks git's credential helpers for the password
+// stored for a host, as 'git credential fill' does. Git is never allowed
+// to prompt.
+type GitCredentialTokenStore struct {
+	Protocol string
+	Host     string
+}
+
+func (s *GitCredentialTokenStore) Get(project string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCredentialTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=%s\nhost=%s\n\n", s.Protocol, s.Host))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never", "GIT_ASKPASS=", "SSH_ASKPASS=")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no git credential for %s: %w", s.Host, err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		if password, ok := strings.CutPrefix(scanner.Text(), "password="); ok && password != "" {
+			return password, nil
+		}
+	}
+	return "", fmt.Errorf("no git credential for %s", s.Host)
+}
+
+func (s *GitCredentialTokenStore) Set(project string, token string) error {
+	return errReadOnlyStore
+}
+
+func (s *GitCredentialTokenStore) Delete(project string) error {
+	return errReadOnlyStore
+}
@@ -88,7 +88,7 @@ func FindRoot() (string, error) {
 
 		parent := filepath.Dir(dir)
 		if parent == dir {
-			return "", fmt.Errorf("not a grit project (no .grit directory found)")
+			return "", ErrNoProject
 		}
 		dir = parent
 	}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LabelConfig defines a repository label. In the config file a label can be
+// written either as a plain name or as a mapping with color and description.
+type LabelConfig struct {
+	Name        string `yaml:"name"`
+	Color       string `yaml:"color,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// UnmarshalYAML accepts both the plain string and the mapping form.
+func (l *LabelConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		l.Name = node.Value
+		return nil
+	}
+
+	type labelConfig LabelConfig
+	var lc labelConfig
+	if err := node.Decode(&lc); err != nil {
+		return err
+	}
+	if lc.Name == "" {
+		return fmt.Errorf("line %d: label is missing a name", node.Line)
+	}
+	*l = LabelConfig(lc)
+	l.Color = NormalizeColor(l.Color)
+	return nil
+}
+
+// MarshalYAML writes labels without a color or description as plain names,
+// keeping hand-written configs short.
+func (l LabelConfig) MarshalYAML() (any, error) {
+	if l.Color == "" && l.Description == "" {
+		return l.Name, nil
+	}
+	type labelConfig LabelConfig
+	return labelConfig(l), nil
+}
+
+// NormalizeColor strips a leading '#' and lowercases a hex color so it
+// matches the form used by the GitHub API.
+func NormalizeColor(color string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(color), "#"))
+}
+
+// LabelNames returns the names of the configured labels.
+func (p ProjectConfig) LabelNames() []string {
+	names := make([]string, len(p.Labels))
+	for i, l := range p.Labels {
+		names[i] = l.Name
+	}
+	return names
+}
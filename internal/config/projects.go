@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BoardConfig identifies a GitHub Projects (v2) board that issues can be
+// added to. A board is found by ID when one is set, otherwise by owner and
+// number, as in https://github.com/orgs/OWNER/projects/NUMBER.
+type BoardConfig struct {
+	// Name is the short name used with --project.
+	Name    string `yaml:"name"`
+	ID      string `yaml:"id,omitempty"`
+	Owner   string `yaml:"owner,omitempty"`
+	Number  int    `yaml:"number,omitempty"`
+	Default bool   `yaml:"default,omitempty"`
+	// Fields maps aliases used on the command line, such as "status" or
+	// "estimate", to the field names on the board.
+	Fields map[string]string `yaml:"fields,omitempty"`
+}
+
+// FieldName returns the board field an alias refers to. Aliases are matched
+// without regard to case, and names without an alias are returned as given.
+func (b BoardConfig) FieldName(alias string) string {
+	for k, v := range b.Fields {
+		if strings.EqualFold(k, alias) {
+			return v
+		}
+	}
+	return alias
+}
+
+// Board returns the configured board with the given name. An empty name
+// selects the default board: the one marked default, or the only one.
+func (c *Config) Board(name string) (*BoardConfig, error) {
+	if len(c.Projects) == 0 {
+		return nil, fmt.Errorf("no projects configured; add one under projects in .grit/config.yaml")
+	}
+
+	if name == "" {
+		for i := range c.Projects {
+			if c.Projects[i].Default {
+				return &c.Projects[i], nil
+			}
+		}
+		if len(c.Projects) == 1 {
+			return &c.Projects[0], nil
+		}
+		return nil, fmt.Errorf("several projects configured; choose one with --project or mark one as default")
+	}
+
+	for i := range c.Projects {
+		if strings.EqualFold(c.Projects[i].Name, name) {
+			return &c.Projects[i], nil
+		}
+	}
+	return nil, fmt.Errorf("project %q is not configured", name)
+}
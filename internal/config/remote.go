@@ -0,0 +1,195 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Remote is a git remote that points at a GitHub repository.
+type Remote struct {
+	Name  string
+	URL   string
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// FullName returns the remote's repository as owner/name.
+func (r Remote) FullName() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// GitRemotes returns the remotes of the git repository containing dir, in
+// the order they appear in its config. Remotes whose URL is not of the
+// form host/owner/repo are skipped. It returns nil when dir is not inside a
+// git repository.
+func GitRemotes(dir string) ([]Remote, error) {
+	gitDir, err := findGitDir(dir)
+	if err != nil || gitDir == "" {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(gitDir, "config"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading git config: %w", err)
+	}
+
+	var remotes []Remote
+	for _, r := range parseGitRemotes(data) {
+		host, owner, repo, ok := ParseRemoteURL(r.URL)
+		if !ok {
+			continue
+		}
+		r.Host, r.Owner, r.Repo = host, owner, repo
+		remotes = append(remotes, r)
+	}
+	return remotes, nil
+}
+
+// findGitDir walks up from dir looking for a .git directory, or a .git
+// file pointing at one as in worktrees and submodules.
+func findGitDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving directory: %w", err)
+	}
+
+	for {
+		path := filepath.Join(dir, ".git")
+		info, err := os.Stat(path)
+		if err == nil {
+			if info.IsDir() {
+				return path, nil
+			}
+			return readGitFile(path)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// readGitFile resolves a "gitdir: <path>" file. A worktree's git directory
+// keeps its config in the main repository, named by its commondir file.
+func readGitFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", nil
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		dir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(gitDir, dir)
+		}
+		return filepath.Clean(dir), nil
+	}
+	return gitDir, nil
+}
+
+// parseGitRemotes reads the url of each [remote "name"] section of a git
+// config file. Only the first url of a remote is used.
+func parseGitRemotes(data []byte) []Remote {
+	var remotes []Remote
+	current := -1
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			current = -1
+			section := strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+			kind, name, ok := strings.Cut(section, " ")
+			if ok && strings.EqualFold(kind, "remote") {
+				remotes = append(remotes, Remote{Name: strings.Trim(strings.TrimSpace(name), `"`)})
+				current = len(remotes) - 1
+			}
+			continue
+		}
+
+		if current < 0 || remotes[current].URL != "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "url") {
+			remotes[current].URL = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	n := 0
+	for _, r := range remotes {
+		if r.URL != "" {
+			remotes[n] = r
+			n++
+		}
+	}
+	return remotes[:n]
+}
+
+// ParseRemoteURL extracts the host, owner and repository from a git remote
+// URL. It accepts the scp-like SSH form (git@host:owner/repo.git) and
+// ssh://, git://, http:// and https:// URLs. The host is empty for
+// github.com.
+func ParseRemoteURL(raw string) (host, owner, repo string, ok bool) {
+	raw = strings.TrimSpace(raw)
+
+	var path string
+	if !strings.Contains(raw, "://") {
+		// scp-like syntax: [user@]host:path
+		hostPart, p, found := strings.Cut(raw, ":")
+		if !found || strings.Contains(hostPart, "/") {
+			return "", "", "", false
+		}
+		if i := strings.LastIndex(hostPart, "@"); i >= 0 {
+			hostPart = hostPart[i+1:]
+		}
+		host, path = hostPart, p
+	} else {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", "", "", false
+		}
+		switch u.Scheme {
+		case "ssh", "git", "http", "https", "git+ssh", "ssh+git":
+		default:
+			return "", "", "", false
+		}
+		host, path = u.Hostname(), u.Path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	owner, repo, found := strings.Cut(path, "/")
+	if !found || owner == "" || repo == "" || strings.Contains(repo, "/") || host == "" {
+		return "", "", "", false
+	}
+
+	host = strings.ToLower(host)
+	if host == DefaultHost || host == "ssh.github.com" || host == "www.github.com" {
+		host = ""
+	}
+	return host, owner, repo, true
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoProject is returned when no .grit directory is found above the
+// working directory.
+var ErrNoProject = errors.New("not a grit project (no .grit directory found)")
+
+// ParseRepo splits an "owner/name" repository reference.
+func ParseRepo(s string) (owner, name string, err error) {
+	owner, name, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid repository %q; use owner/name", s)
+	}
+	return owner, name, nil
+}
+
+// FullName returns the repository as owner/name.
+func (p ProjectConfig) FullName() string {
+	return p.Owner + "/" + p.Repo
+}
+
+// ForRepo returns a copy of the configuration that targets another
+// repository on the same host. The labels, default assignees and issue
+// prefix describe the original repository, so they are dropped; the LLM
+// settings, project boards and repository registry are kept. The original
+// repository's token is used when the new one has none of its own. A GitHub
+// App installation ID is dropped when the owner changes, so the app's
+// installation on the new repository is looked up instead.
+func (c *Config) ForRepo(repo string) (*Config, error) {
+	owner, name, err := ParseRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(owner, c.Project.Owner) && strings.EqualFold(name, c.Project.Repo) {
+		return c, nil
+	}
+
+	switched := *c
+	switched.Project = ProjectConfig{
+		Host:          c.Project.Host,
+		Owner:         owner,
+		Repo:          name,
+		OAuthClientID: c.Project.OAuthClientID,
+	}
+	if c.home == nil && c.Project.Repo != "" {
+		home := c.Project
+		switched.home = &home
+	}
+	if c.App != nil && !strings.EqualFold(owner, c.Project.Owner) {
+		app := *c.App
+		app.InstallationID = 0
+		switched.App = &app
+	}
+	return &switched, nil
+}
+
+// RepoList returns the project's own repository, if any, followed by the
+// registered repositories, without duplicates.
+func (c *Config) RepoList() []string {
+	var repos []string
+	if c.Project.Owner != "" && c.Project.Repo != "" {
+		repos = append(repos, c.Project.FullName())
+	}
+	for _, r := range c.Repositories {
+		if !containsRepo(repos, r) {
+			repos = append(repos, r)
+		}
+	}
+	return repos
+}
+
+// AddRepository registers a repository. It reports false when the
+// repository was already registered.
+func (c *Config) AddRepository(repo string) (bool, error) {
+	owner, name, err := ParseRepo(repo)
+	if err != nil {
+		return false, err
+	}
+	repo = owner + "/" + name
+	if containsRepo(c.Repositories, repo) {
+		return false, nil
+	}
+	c.Repositories = append(c.Repositories, repo)
+	return true, nil
+}
+
+// RemoveRepository unregisters a repository. It reports false when the
+// repository was not registered.
+func (c *Config) RemoveRepository(repo string) bool {
+	n := len(c.Repositories)
+	c.Repositories = slices.DeleteFunc(c.Repositories, func(r string) bool { return strings.EqualFold(r, repo) })
+	return len(c.Repositories) < n
+}
+
+// MergeRepositories adds repositories registered elsewhere, such as in the
+// user configuration, to this configuration's registry.
+func (c *Config) MergeRepositories(repos []string) {
+	for _, r := range repos {
+		if !containsRepo(c.Repositories, r) {
+			c.Repositories = append(c.Repositories, r)
+		}
+	}
+}
+
+func containsRepo(repos []string, repo string) bool {
+	return slices.ContainsFunc(repos, func(r string) bool { return strings.EqualFold(r, repo) })
+}
+
+// UserPath returns the path of the user configuration file, which holds
+// settings used outside any project: the LLM provider and the repository
+// registry.
+func UserPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return filepath.Join(dir, "grit", ConfigFile), nil
+}
+
+// LoadUser reads the user configuration. A missing file yields an empty
+// configuration without an LLM provider.
+func LoadUser() (*Config, error) {
+	path, err := UserPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{Version: 1, LLM: LLMConfig{Provider: "none"}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading user config file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing user config file: %w", err)
+	}
+	if cfg.LLM.Provider == "" {
+		cfg.LLM.Provider = "none"
+	}
+	return &cfg, nil
+}
+
+// SaveUser writes the user configuration.
+func SaveUser(cfg *Config) error {
+	path, err := UserPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating user config directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("marshalling config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing user config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,459 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	envSecretStore      = "GRIT_SECRET_STORE"
+	envSecretPassphrase = "GRIT_SECRETS_PASSPHRASE"
+	envSecretKeyFile    = "GRIT_SECRETS_KEY_FILE"
+
+	secretFileName    = "secrets.enc"
+	secretKeyFileName = "secrets.key"
+
+	pbkdf2Iterations = 600000
+	saltSize         = 16
+	keySize          = 32
+)
+
+// Secret backend names, as reported by SecretBackend.
+const (
+	BackendKeyring = "keyring"
+	BackendFile    = "file"
+)
+
+// ErrSecretNotFound is returned when no secret is stored under a name.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// errSecretFile wraps errors opening the secret file, such as a wrong
+// passphrase, which token lookup reports instead of moving on.
+var errSecretFile = errors.New("cannot open secret file")
+
+// PassphrasePrompt asks the user for the passphrase protecting the
+// encrypted secret file. create is true when the file is about to be
+// created, so the passphrase should be confirmed. It is nil when grit
+// cannot prompt, in which case GRIT_SECRETS_PASSPHRASE or a key file is
+// required.
+var PassphrasePrompt func(create bool) (string, error)
+
+// secretStore holds secrets by service and name, like the system keyring.
+type secretStore interface {
+	get(service, name string) (string, error)
+	set(service, name, value string) error
+	delete(service, name string) error
+}
+
+var (
+	backendOnce sync.Once
+	backendName string
+	fileStore   = &SecretFile{}
+)
+
+// SecretBackend returns where grit saves secrets: the system keyring, or
+// the encrypted file when no keyring is available. GRIT_SECRET_STORE set
+// to "keyring" or "file" overrides the choice.
+func SecretBackend() string {
+	backendOnce.Do(func() {
+		switch strings.ToLower(os.Getenv(envSecretStore)) {
+		case BackendFile:
+			backendName = BackendFile
+		case BackendKeyring:
+			backendName = BackendKeyring
+		default:
+			backendName = BackendFile
+			if keyringAvailable() {
+				backendName = BackendKeyring
+			}
+		}
+	})
+	return backendName
+}
+
+func secrets() secretStore {
+	if SecretBackend() == BackendFile {
+		return fileStore
+	}
+	return keyringStore{}
+}
+
+// keyringAvailable probes the system keyring. A lookup of a missing entry
+// succeeds with ErrNotFound when a keyring service is running.
+func keyringAvailable() bool {
+	_, err := keyring.Get(keyringService, "grit-keyring-probe")
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+// DeleteSecret removes a secret from every backend that holds it and
+// returns the names of those backends.
+func DeleteSecret(service, name string) ([]string, error) {
+	var removed []string
+	if keyringAvailable() {
+		err := keyringStore{}.delete(service, name)
+		switch {
+		case err == nil:
+			removed = append(removed, BackendKeyring)
+		case !errors.Is(err, ErrSecretNotFound):
+			return removed, err
+		}
+	}
+	if fileStore.Exists() {
+		err := fileStore.delete(service, name)
+		switch {
+		case err == nil:
+			removed = append(removed, BackendFile)
+		case !errors.Is(err, ErrSecretNotFound):
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+type keyringStore struct{}
+
+func (keyringStore) get(service, name string) (string, error) {
+	value, err := keyring.Get(service, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrSecretNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("accessing keyring: %w", err)
+	}
+	return value, nil
+}
+
+func (keyringStore) set(service, name, value string) error {
+	if err := keyring.Set(service, name, value); err != nil {
+		return fmt.Errorf("storing secret in keyring: %w", err)
+	}
+	return nil
+}
+
+func (keyringStore) delete(service, name string) error {
+	err := keyring.Delete(service, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrSecretNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("deleting secret from keyring: %w", err)
+	}
+	return nil
+}
+
+// SecretFile is an encrypted file of secrets under the user config
+// directory, used where no system keyring is available. Its contents are
+// sealed with AES-256-GCM under a key derived with PBKDF2 from a
+// passphrase or key file.
+type SecretFile struct {
+	mu         sync.Mutex
+	passphrase string
+}
+
+// secretEnvelope is the on-disk form of the secret file.
+type secretEnvelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// SecretFilePath returns the location of the encrypted secret file.
+func SecretFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return filepath.Join(dir, "grit", secretFileName), nil
+}
+
+// Exists reports whether the secret file has been created.
+func (f *SecretFile) Exists() bool {
+	path, err := SecretFilePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func (f *SecretFile) get(service, name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errSecretFile, err)
+	}
+	value, ok := entries[secretName(service, name)]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+func (f *SecretFile) set(service, name, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	entries[secretName(service, name)] = value
+	return f.save(entries)
+}
+
+func (f *SecretFile) delete(service, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	key := secretName(service, name)
+	if _, ok := entries[key]; !ok {
+		return ErrSecretNotFound
+	}
+	delete(entries, key)
+	return f.save(entries)
+}
+
+func secretName(service, name string) string {
+	return service + ":" + name
+}
+
+// load decrypts the secret file. A missing file yields no entries without
+// asking for a passphrase.
+func (f *SecretFile) load() (map[string]string, error) {
+	path, err := SecretFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading secret file: %w", err)
+	}
+
+	var env secretEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("parsing secret file %s: %w", path, err)
+	}
+	if env.Version != 1 || env.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported secret file format in %s", path)
+	}
+
+	passphrase, err := f.getPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newSecretCipher(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, env.Nonce, env.Data, nil)
+	if err != nil {
+		f.passphrase = ""
+		return nil, fmt.Errorf("decrypting %s: wrong passphrase or key file", path)
+	}
+
+	entries := map[string]string{}
+	if err := json.Unmarshal(plain, &entries); err != nil {
+		return nil, fmt.Errorf("parsing secret file contents: %w", err)
+	}
+	return entries, nil
+}
+
+// save encrypts entries under a fresh salt and nonce and writes the file
+// with 0600 permissions. The file is removed once it holds no secrets.
+func (f *SecretFile) save(entries map[string]string) error {
+	path, err := SecretFilePath()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing secret file: %w", err)
+		}
+		return nil
+	}
+
+	_, statErr := os.Stat(path)
+	passphrase, err := f.getPassphrase(errors.Is(statErr, os.ErrNotExist))
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("marshalling secrets: %w", err)
+	}
+
+	env := secretEnvelope{Version: 1, KDF: "pbkdf2-sha256", Iterations: pbkdf2Iterations, Salt: make([]byte, saltSize)}
+	if _, err := rand.Read(env.Salt); err != nil {
+		return fmt.Errorf("generating salt: %w", err)
+	}
+	gcm, err := newSecretCipher(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return err
+	}
+	env.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	env.Data = gcm.Seal(nil, env.Nonce, plain, nil)
+
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling secret file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating user config directory: %w", err)
+	}
+
+	// Write to a temporary file and rename, so a failed write never
+	// leaves a truncated file behind.
+	tmp, err := os.CreateTemp(filepath.Dir(path), secretFileName+".*")
+	if err != nil {
+		return fmt.Errorf("writing secret file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing secret file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing secret file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing secret file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing secret file: %w", err)
+	}
+	return nil
+}
+
+func newSecretCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// getPassphrase returns the passphrase from, in order,
+// GRIT_SECRETS_PASSPHRASE, the key file named by GRIT_SECRETS_KEY_FILE or
+// secrets.key in the user config directory, and PassphrasePrompt.
+func (f *SecretFile) getPassphrase(create bool) (string, error) {
+	if f.passphrase != "" {
+		return f.passphrase, nil
+	}
+
+	passphrase, err := readPassphrase(create)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("the secret file passphrase cannot be empty")
+	}
+	f.passphrase = passphrase
+	return passphrase, nil
+}
+
+func readPassphrase(create bool) (string, error) {
+	if p := os.Getenv(envSecretPassphrase); p != "" {
+		return p, nil
+	}
+
+	keyFile := os.Getenv(envSecretKeyFile)
+	if keyFile == "" {
+		dir, err := os.UserConfigDir()
+		if err == nil {
+			if path := filepath.Join(dir, "grit", secretKeyFileName); fileExists(path) {
+				keyFile = path
+			}
+		}
+	}
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return "", fmt.Errorf("reading secret key file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	if PassphrasePrompt == nil {
+		return "", fmt.Errorf("no system keyring is available and the secret file needs a passphrase; set %s or %s", envSecretPassphrase, envSecretKeyFile)
+	}
+	return PassphrasePrompt(create)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// FileTokenStore keeps GitHub tokens in the encrypted secret file.
+type FileTokenStore struct{}
+
+func (s *FileTokenStore) Get(project string) (string, error) {
+	token, err := fileStore.get(keyringService, project)
+	if errors.Is(err, ErrSecretNotFound) {
+		return "", fmt.Errorf("no token stored for %s", project)
+	}
+	return token, err
+}
+
+func (s *FileTokenStore) Set(project string, token string) error {
+	return fileStore.set(keyringService, project, token)
+}
+
+func (s *FileTokenStore) Delete(project string) error {
+	return fileStore.delete(keyringService, project)
+}
+
+// SecretTokenStore keeps GitHub tokens wherever grit saves secrets: the
+// system keyring, or the encrypted secret file when no keyring is
+// available.
+type SecretTokenStore struct{}
+
+func (s *SecretTokenStore) Get(project string) (string, error) {
+	token, err := secrets().get(keyringService, project)
+	if errors.Is(err, ErrSecretNotFound) {
+		return "", fmt.Errorf("no token stored for %s", project)
+	}
+	return token, err
+}
+
+func (s *SecretTokenStore) Set(project string, token string) error {
+	return secrets().set(keyringService, project, token)
+}
+
+func (s *SecretTokenStore) Delete(project string) error {
+	return secrets().delete(keyringService, project)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -74,37 +75,93 @@ type CompositeTokenStore struct {
 }
 
 // NewCompositeTokenStore creates a store that checks environment variables first,
-// then falls back to the system keyring.
+// then falls back to the system keyring or, without one, the encrypted
+// secret file.
 func NewCompositeTokenStore() *CompositeTokenStore {
 	return &CompositeTokenStore{
 		stores: []TokenStore{
 			&EnvTokenStore{},
-			&KeyringTokenStore{},
+			&SecretTokenStore{},
 		},
 	}
 }
 
 func (s *CompositeTokenStore) Get(project string) (string, error) {
+	token, _, err := s.Lookup(project)
+	return token, err
+}
+
+// Lookup returns the first token found and the name of the source it came
+// from.
+func (s *CompositeTokenStore) Lookup(project string) (token, source string, err error) {
+	var fileErr error
 	for _, store := range s.stores {
 		token, err := store.Get(project)
 		if err == nil {
-			return token, nil
+			return token, sourceName(store), nil
+		}
+		if errors.Is(err, errSecretFile) && fileErr == nil {
+			fileErr = err
 		}
 	}
-	return "", fmt.Errorf("no token found; run 'grit auth login' or set %s", envVarName)
+	if fileErr != nil {
+		return "", "", fileErr
+	}
+	return "", "", fmt.Errorf("no token found; run 'grit auth login' or set %s", envVarName)
+}
+
+// sourceName returns the auth.token_sources name of a store.
+func sourceName(store TokenStore) string {
+	switch store.(type) {
+	case *EnvTokenStore:
+		return SourceGritEnv
+	case *KeyringTokenStore:
+		return SourceKeyring
+	case *SecretTokenStore:
+		return SecretBackend()
+	case *FileTokenStore:
+		return SourceFile
+	case *GitHubEnvTokenStore:
+		return SourceEnv
+	case *GHCLITokenStore:
+		return SourceGH
+	case *GitCredentialTokenStore:
+		return SourceGitCredential
+	}
+	return "unknown"
 }
 
 func (s *CompositeTokenStore) Set(project string, token string) error {
-	return (&KeyringTokenStore{}).Set(project, token)
+	return (&SecretTokenStore{}).Set(project, token)
 }
 
 func (s *CompositeTokenStore) Delete(project string) error {
-	return (&KeyringTokenStore{}).Delete(project)
+	return (&SecretTokenStore{}).Delete(project)
 }
 
 // ProjectKey returns the unique identifier for a project's token storage.
+// Projects on GitHub Enterprise Server are keyed by host as well, so the same
+// owner/repo on different hosts never share a token.
 func ProjectKey(cfg *Config) string {
-	return fmt.Sprintf("%s/%s", cfg.Project.Owner, cfg.Project.Repo)
+	return projectKey(cfg.Project)
+}
+
+func projectKey(p ProjectConfig) string {
+	if p.IsEnterprise() {
+		return fmt.Sprintf("%s/%s/%s", p.HostName(), p.Owner, p.Repo)
+	}
+	return fmt.Sprintf("%s/%s", p.Owner, p.Repo)
+}
+
+// TokenKeys returns the keys to look a token up under, in order: the
+// project's own key and, for a configuration switched to another repository
+// with ForRepo, the key of the original project.
+func TokenKeys(cfg *Config) []string {
+	keys := []string{ProjectKey(cfg)}
+	if cfg.home != nil {
+		keys = append(keys, projectKey(*cfg.home))
+	}
+	return keys
 }
 
 // GetLLMKey retrieves the API key for the specified LLM provider.
@@ -114,20 +171,33 @@ func GetLLMKey(provider string) (string, error) {
 		return key, nil
 	}
 
-	key, err := keyring.Get(keyringLLMPrefix, provider)
-	if err == keyring.ErrNotFound {
+	key, err := secrets().get(keyringLLMPrefix, provider)
+	if errors.Is(err, ErrSecretNotFound) {
 		return "", fmt.Errorf("no LLM API key found for %s; set %s or run 'grit auth llm'", provider, envVarLLMKey)
 	}
 	if err != nil {
-		return "", fmt.Errorf("accessing keyring: %w", err)
+		return "", err
 	}
 	return key, nil
 }
 
-// SetLLMKey stores the API key for the specified LLM provider in the keyring.
+// SetLLMKey stores the API key for the specified LLM provider in the
+// keyring or, without one, the encrypted secret file.
 func SetLLMKey(provider, key string) error {
-	if err := keyring.Set(keyringLLMPrefix, provider, key); err != nil {
-		return fmt.Errorf("storing LLM key in keyring: %w", err)
+	if err := secrets().set(keyringLLMPrefix, provider, key); err != nil {
+		return fmt.Errorf("storing LLM key: %w", err)
 	}
 	return nil
 }
+
+// DeleteLLMKey removes the API key for the specified LLM provider from
+// every backend that holds it and returns the names of those backends.
+func DeleteLLMKey(provider string) ([]string, error) {
+	return DeleteSecret(keyringLLMPrefix, provider)
+}
+
+// DeleteToken removes a project's GitHub token from every backend that
+// holds it and returns the names of those backends.
+func DeleteToken(project string) ([]string, error) {
+	return DeleteSecret(keyringService, project)
+}
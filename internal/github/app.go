@@ -0,0 +1,264 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// appJWTLifetime is how long an app JWT is valid. GitHub rejects
+	// lifetimes over ten minutes.
+	appJWTLifetime = 9 * time.Minute
+	// appClockSkew backdates the JWT's issue time to allow for clock drift.
+	appClockSkew = time.Minute
+	// appTokenRefresh is how long before expiry a cached installation
+	// token is replaced.
+	appTokenRefresh = 5 * time.Minute
+)
+
+// TokenSource supplies the token sent with each request. It is called
+// before every request, so implementations should cache.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// WithTokenSource sets a source of tokens used instead of the static token
+// passed to NewHTTPClient, for tokens that expire such as GitHub App
+// installation tokens.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *HTTPClient) {
+		c.tokenSource = ts
+	}
+}
+
+// AppCredentials identifies a GitHub App installation.
+type AppCredentials struct {
+	// AppID is the app's numeric ID or its client ID.
+	AppID string
+	// InstallationID is the installation to act as. When zero, the
+	// installation on Owner/Repo is looked up.
+	InstallationID int64
+	// PrivateKey is the app's PEM-encoded RSA private key.
+	PrivateKey []byte
+	Owner      string
+	Repo       string
+}
+
+// AppTokenSource exchanges a GitHub App's signed JWT for installation
+// tokens. Tokens are cached and replaced shortly before they expire.
+type AppTokenSource struct {
+	baseURL        string
+	appID          string
+	key            *rsa.PrivateKey
+	installationID int64
+	owner          string
+	repo           string
+	httpClient     *http.Client
+	now            func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewAppTokenSource creates a token source for the app installation in
+// creds, using the REST API at baseURL.
+func NewAppTokenSource(baseURL string, creds AppCredentials) (*AppTokenSource, error) {
+	if creds.AppID == "" {
+		return nil, errors.New("GitHub App ID is not set")
+	}
+	if creds.InstallationID == 0 && (creds.Owner == "" || creds.Repo == "") {
+		return nil, errors.New("GitHub App installation ID is not set")
+	}
+	key, err := ParseAppPrivateKey(creds.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AppTokenSource{
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		appID:          creds.AppID,
+		key:            key,
+		installationID: creds.InstallationID,
+		owner:          creds.Owner,
+		repo:           creds.Repo,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		now:            time.Now,
+	}, nil
+}
+
+// ParseAppPrivateKey parses a PEM-encoded RSA private key in PKCS #1 or
+// PKCS #8 form, as downloaded from the app's settings page.
+func ParseAppPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("GitHub App private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// Token returns a cached installation token, fetching a new one when none
+// is cached or the cached one is about to expire.
+func (s *AppTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.now().Add(appTokenRefresh).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	jwt, err := s.signJWT()
+	if err != nil {
+		return "", err
+	}
+
+	if s.installationID == 0 {
+		id, err := s.findInstallation(ctx, jwt)
+		if err != nil {
+			return "", err
+		}
+		s.installationID = id
+	}
+
+	var resp struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	path := fmt.Sprintf("/app/installations/%d/access_tokens", s.installationID)
+	if err := s.request(ctx, http.MethodPost, path, jwt, &resp); err != nil {
+		return "", fmt.Errorf("creating GitHub App installation token: %w", err)
+	}
+	if resp.Token == "" {
+		return "", errors.New("creating GitHub App installation token: empty token in response")
+	}
+
+	s.token, s.expiry = resp.Token, resp.ExpiresAt
+	return s.token, nil
+}
+
+// Identity names the app installation the tokens act for. It lets cached
+// responses be revalidated across installation tokens.
+func (s *AppTokenSource) Identity() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("app %s installation %d on %s", s.appID, s.installationID, s.baseURL)
+}
+
+// Expiry returns when the cached installation token expires, or the zero
+// time when none has been fetched.
+func (s *AppTokenSource) Expiry() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.expiry
+}
+
+// InstallationID returns the installation the source acts as, which is
+// zero until it has been looked up.
+func (s *AppTokenSource) InstallationID() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.installationID
+}
+
+func (s *AppTokenSource) findInstallation(ctx context.Context, jwt string) (int64, error) {
+	var installation struct {
+		ID int64 `json:"id"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/installation", s.owner, s.repo)
+	if err := s.request(ctx, http.MethodGet, path, jwt, &installation); err != nil {
+		var nf *NotFoundError
+		if errors.As(err, &nf) {
+			return 0, fmt.Errorf("GitHub App %s is not installed on %s/%s", s.appID, s.owner, s.repo)
+		}
+		return 0, fmt.Errorf("finding GitHub App installation: %w", err)
+	}
+	return installation.ID, nil
+}
+
+func (s *AppTokenSource) request(ctx context.Context, method, path, jwt string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return newAPIError(resp, body)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
+
+// signJWT creates the RS256-signed JWT that authenticates as the app.
+func (s *AppTokenSource) signJWT() (string, error) {
+	now := s.now()
+
+	// A numeric app ID is sent as a number; a client ID as a string.
+	var issuer any = s.appID
+	if id, err := strconv.ParseInt(s.appID, 10, 64); err == nil {
+		issuer = id
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-appClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": issuer,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing GitHub App JWT: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
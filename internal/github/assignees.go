@@ -0,0 +1,18 @@
+package github
+
+import "context"
+
+// ListAssignees returns the users who can be assigned to issues in the
+// repository.
+func (c *HTTPClient) ListAssignees(ctx context.Context) ([]User, error) {
+	path := c.repoPath("/assignees?per_page=%d", maxPerPage)
+
+	var users []User
+	for user, err := range paginate(ctx, c, path, func(page []User) []User { return page }) {
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
@@ -0,0 +1,249 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheSize is the size a ResponseCache is kept under when none is
+// given.
+const DefaultCacheSize = 50 << 20
+
+// ResponseCache keeps the responses to GET requests on disk along with
+// their ETag and Last-Modified validators, so that repeating a request can
+// ask GitHub whether it changed. A 304 Not Modified reply is answered from
+// the cache and does not count against the rate limit.
+//
+// The cache is best effort: entries that cannot be read or written are
+// treated as missing.
+type ResponseCache struct {
+	dir     string
+	maxSize int64
+
+	mu   sync.Mutex
+	size int64 // bytes on disk, or -1 until measured
+}
+
+// cachedResponse is a cache entry.
+type cachedResponse struct {
+	Header http.Header     `json:"header"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// NewResponseCache creates a cache kept in dir. Once its entries take up
+// more than maxSize bytes, the least recently used are removed; a maxSize
+// of zero or less means DefaultCacheSize.
+func NewResponseCache(dir string, maxSize int64) *ResponseCache {
+	if maxSize <= 0 {
+		maxSize = DefaultCacheSize
+	}
+	return &ResponseCache{dir: dir, maxSize: maxSize, size: -1}
+}
+
+// WithCache makes conditional requests for GETs, keeping responses in rc.
+func WithCache(rc *ResponseCache) Option {
+	return func(c *HTTPClient) {
+		c.cache = rc
+	}
+}
+
+// identifier is implemented by token sources whose tokens change but
+// always act for the same identity, so that responses cached with one token
+// can be revalidated with the next.
+type identifier interface {
+	Identity() string
+}
+
+// cacheKey names the entry for url, which includes the host. Who asked is
+// part of it, since what a response holds depends on that: the identity
+// of the token source if it has one, or else the token.
+func (c *HTTPClient) cacheKey(url, token string) string {
+	identity := "token " + token
+	if id, ok := c.tokenSource.(identifier); ok {
+		identity = id.Identity()
+	}
+	sum := sha256.Sum256([]byte(identity + "\n" + url))
+	return hex.EncodeToString(sum[:])
+}
+
+// path returns where the entry for key is kept: in a directory named after
+// the key's first two hex digits, to keep directories small.
+func (rc *ResponseCache) path(key string) string {
+	return filepath.Join(rc.dir, key[:2], key+".json")
+}
+
+// isEntry reports whether the file name in the directory shard is laid out
+// as path lays out entries. The cache directory may be shared with other
+// files, which eviction must leave alone.
+func isEntry(shard, name string) bool {
+	key, ok := strings.CutSuffix(name, ".json")
+	return ok && len(key) == 2*sha256.Size && isHex(key) && key[:2] == shard
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil && strings.ToLower(s) == s
+}
+
+// get returns the entry for key, marking it as recently used.
+func (rc *ResponseCache) get(key string) (*cachedResponse, bool) {
+	path := rc.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry cachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return &entry, true
+}
+
+// put stores the response to a request if it carries a validator to make
+// the next one conditional.
+func (rc *ResponseCache) put(key string, header http.Header, body []byte) {
+	if header.Get("ETag") == "" && header.Get("Last-Modified") == "" {
+		return
+	}
+	if len(body) == 0 || !json.Valid(body) {
+		return
+	}
+	data, err := json.Marshal(cachedResponse{Header: header, Body: body})
+	if err != nil || int64(len(data)) > rc.maxSize {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	path := rc.path(key)
+	var old int64
+	if info, err := os.Stat(path); err == nil {
+		old = info.Size()
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return
+	}
+
+	if rc.size < 0 {
+		rc.size = rc.measure()
+	} else {
+		rc.size += int64(len(data)) - old
+	}
+	if rc.size > rc.maxSize {
+		rc.evict()
+	}
+}
+
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// files returns the cache's entries. Only the entry directories are read,
+// never the rest of the cache directory.
+func (rc *ResponseCache) files() []cacheFile {
+	shards, _ := os.ReadDir(rc.dir)
+	var files []cacheFile
+	for _, shard := range shards {
+		if !shard.IsDir() || len(shard.Name()) != 2 || !isHex(shard.Name()) {
+			continue
+		}
+		dir := filepath.Join(rc.dir, shard.Name())
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			if !e.Type().IsRegular() || !isEntry(shard.Name(), e.Name()) {
+				continue
+			}
+			if info, err := e.Info(); err == nil {
+				files = append(files, cacheFile{filepath.Join(dir, e.Name()), info.Size(), info.ModTime()})
+			}
+		}
+	}
+	return files
+}
+
+func (rc *ResponseCache) measure() int64 {
+	var size int64
+	for _, f := range rc.files() {
+		size += f.size
+	}
+	return size
+}
+
+// evict removes the least recently used entries until the cache is back
+// under its size. Other processes may be using the cache too, so the size
+// is measured again first.
+func (rc *ResponseCache) evict() {
+	files := rc.files()
+	slices.SortFunc(files, func(a, b cacheFile) int { return a.modTime.Compare(b.modTime) })
+
+	rc.size = 0
+	for _, f := range files {
+		rc.size += f.size
+	}
+	for _, f := range files {
+		if rc.size <= rc.maxSize {
+			break
+		}
+		if err := os.Remove(f.path); err == nil || os.IsNotExist(err) {
+			rc.size -= f.size
+		}
+	}
+}
+
+// writeFileAtomic writes data to path through a temporary file, so that
+// readers never see part of an entry. Entries may hold private issues, so
+// only the user can read them.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// revalidate returns the headers that make a request for entry
+// conditional.
+func (entry *cachedResponse) revalidate() http.Header {
+	h := make(http.Header)
+	if etag := entry.Header.Get("ETag"); etag != "" {
+		h.Set("If-None-Match", etag)
+	}
+	if modified := entry.Header.Get("Last-Modified"); modified != "" {
+		h.Set("If-Modified-Since", modified)
+	}
+	return h
+}
+
+// notModified returns the headers of entry updated with those of a 304
+// reply, such as the rate limit and a renewed ETag.
+func (entry *cachedResponse) notModified(reply http.Header) http.Header {
+	h := entry.Header.Clone()
+	for k, v := range reply {
+		h[k] = v
+	}
+	return h
+}
@@ -1,14 +1,51 @@
 package github
 
-import "context"
+import (
+	"context"
+	"iter"
+)
 
 type Client interface {
 	CreateIssue(ctx context.Context, req CreateIssueRequest) (*Issue, error)
-	CloseIssue(ctx context.Context, number int, comment string) (*Issue, error)
+	CloseIssue(ctx context.Context, number int, req CloseIssueRequest) (*Issue, error)
+	ReopenIssue(ctx context.Context, number int, comment string) (*Issue, error)
 	GetIssue(ctx context.Context, number int) (*Issue, error)
-	ListIssues(ctx context.Context, req ListIssuesRequest) ([]Issue, error)
+	GetIssueByRef(ctx context.Context, ref IssueRef) (*Issue, error)
+	ListIssues(ctx context.Context, req ListIssuesRequest) (*ListIssuesResponse, error)
+	ListAllIssues(ctx context.Context, req ListIssuesRequest) iter.Seq2[Issue, error]
 	AddComment(ctx context.Context, number int, body string) (*IssueComment, error)
+	ListComments(ctx context.Context, number int) ([]IssueComment, error)
+	UpdateComment(ctx context.Context, id int64, body string) (*IssueComment, error)
+	DeleteComment(ctx context.Context, id int64) error
 	AssignIssue(ctx context.Context, number int, assignees []string) (*Issue, error)
 	UpdateIssue(ctx context.Context, number int, req UpdateIssueRequest) (*Issue, error)
 	SearchIssues(ctx context.Context, req SearchIssuesRequest) (*SearchIssuesResponse, error)
+	SearchAllIssues(ctx context.Context, req SearchIssuesRequest) iter.Seq2[Issue, error]
+	AddSubIssue(ctx context.Context, parent, child IssueRef) (*Issue, error)
+	RemoveSubIssue(ctx context.Context, parent, child IssueRef) (*Issue, error)
+	ReprioritizeSubIssue(ctx context.Context, parent int, child IssueRef, pos SubIssuePosition) (*Issue, error)
+	ListSubIssues(ctx context.Context, parent int) ([]Issue, error)
+	GetParentIssue(ctx context.Context, number int) (*Issue, error)
+	AddBlockedBy(ctx context.Context, number, blocker IssueRef) (*Issue, error)
+	RemoveBlockedBy(ctx context.Context, number, blocker IssueRef) (*Issue, error)
+	ListBlockedBy(ctx context.Context, number int) ([]Issue, error)
+	ListBlocking(ctx context.Context, number int) ([]Issue, error)
+	ListLinkedPullRequests(ctx context.Context, number int) ([]LinkedPullRequest, error)
+	ListTimeline(ctx context.Context, number int) ([]TimelineEvent, error)
+	ListAssignees(ctx context.Context) ([]User, error)
+	ListLabels(ctx context.Context) ([]Label, error)
+	CreateLabel(ctx context.Context, req LabelRequest) (*Label, error)
+	UpdateLabel(ctx context.Context, name string, req LabelRequest) (*Label, error)
+	DeleteLabel(ctx context.Context, name string) error
+	ListMilestones(ctx context.Context, state string) ([]Milestone, error)
+	CreateMilestone(ctx context.Context, req CreateMilestoneRequest) (*Milestone, error)
+	UpdateMilestone(ctx context.Context, number int, req UpdateMilestoneRequest) (*Milestone, error)
+	GetProject(ctx context.Context, owner string, number int) (*Project, error)
+	GetProjectByID(ctx context.Context, id string) (*Project, error)
+	AddProjectItem(ctx context.Context, projectID, contentID string) (string, error)
+	UpdateProjectItemField(ctx context.Context, projectID, itemID, fieldID string, value ProjectFieldValue) error
+	ListProjectItems(ctx context.Context, number int) ([]ProjectItem, error)
+	ListDirectory(ctx context.Context, dir string) ([]ContentEntry, error)
+	GetFileContent(ctx context.Context, file string) ([]byte, error)
+	RateLimit() RateLimit
 }
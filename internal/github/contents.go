@@ -0,0 +1,59 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ContentEntry is a file or directory in a repository, as returned by the
+// contents API. Content is only populated when a single file is fetched.
+type ContentEntry struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Content  string `json:"content,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// ListDirectory returns the entries of a directory on the default branch.
+func (c *HTTPClient) ListDirectory(ctx context.Context, dir string) ([]ContentEntry, error) {
+	var entries []ContentEntry
+	path := c.repoPath("/contents/%s", escapePath(dir))
+	if err := c.do(ctx, http.MethodGet, path, nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetFileContent returns the decoded contents of a file on the default branch.
+func (c *HTTPClient) GetFileContent(ctx context.Context, file string) ([]byte, error) {
+	var entry ContentEntry
+	path := c.repoPath("/contents/%s", escapePath(file))
+	if err := c.do(ctx, http.MethodGet, path, nil, &entry); err != nil {
+		return nil, err
+	}
+	if entry.Type != "file" {
+		return nil, fmt.Errorf("%s is a %s, not a file", file, entry.Type)
+	}
+	if entry.Encoding != "base64" {
+		return []byte(entry.Content), nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(entry.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", file, err)
+	}
+	return data, nil
+}
+
+func escapePath(p string) string {
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,108 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// AddBlockedBy records that number is blocked by blocker. Either issue may
+// be in another repository.
+func (c *HTTPClient) AddBlockedBy(ctx context.Context, number, blocker IssueRef) (*Issue, error) {
+	blockerIssue, err := c.GetIssueByRef(ctx, blocker)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue Issue
+	path := c.refPath(number, "/issues/%d/dependencies/blocked_by", number.Number)
+	body := dependencyRequest{IssueID: blockerIssue.ID}
+	if err := c.do(ctx, http.MethodPost, path, body, &issue); err != nil {
+		return nil, c.changeErr(ctx, err, number, dependenciesAPI,
+			fmt.Sprintf("%s cannot be marked as blocked by %s", number, blocker))
+	}
+	return &issue, nil
+}
+
+// RemoveBlockedBy removes blocker from the issues blocking number.
+func (c *HTTPClient) RemoveBlockedBy(ctx context.Context, number, blocker IssueRef) (*Issue, error) {
+	blockerIssue, err := c.GetIssueByRef(ctx, blocker)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue Issue
+	path := c.refPath(number, "/issues/%d/dependencies/blocked_by/%d", number.Number, blockerIssue.ID)
+	if err := c.do(ctx, http.MethodDelete, path, nil, &issue); err != nil {
+		return nil, c.changeErr(ctx, err, number, dependenciesAPI,
+			fmt.Sprintf("%s is not blocked by %s", number, blocker))
+	}
+	return &issue, nil
+}
+
+// ListBlockedBy returns the issues that block number.
+func (c *HTTPClient) ListBlockedBy(ctx context.Context, number int) ([]Issue, error) {
+	return c.listDependencies(ctx, number, "blocked_by")
+}
+
+// ListBlocking returns the issues that number blocks.
+func (c *HTTPClient) ListBlocking(ctx context.Context, number int) ([]Issue, error) {
+	return c.listDependencies(ctx, number, "blocking")
+}
+
+func (c *HTTPClient) listDependencies(ctx context.Context, number int, kind string) ([]Issue, error) {
+	path := c.repoPath("/issues/%d/dependencies/%s?per_page=%d", number, kind, maxPerPage)
+
+	var issues []Issue
+	for issue, err := range paginate(ctx, c, path, func(page []Issue) []Issue { return page }) {
+		if err != nil {
+			return nil, c.unsupportedErr(ctx, err, IssueRef{Number: number}, dependenciesAPI)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// relationship is an issue relationship API, named for errors.
+type relationship struct {
+	feature string
+	// list is the path of the endpoint listing an issue's relationships,
+	// which answers for every issue on servers with the API.
+	list string
+}
+
+var (
+	subIssuesAPI    = relationship{"sub-issues", "/issues/%d/sub_issues"}
+	dependenciesAPI = relationship{"issue dependencies", "/issues/%d/dependencies/blocked_by"}
+)
+
+// unsupportedErr reports ErrNotSupported when the list endpoint of rel
+// answers 404 for an issue that does exist, which is how servers without
+// the feature respond.
+func (c *HTTPClient) unsupportedErr(ctx context.Context, err error, ref IssueRef, rel relationship) error {
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		return err
+	}
+	if _, gerr := c.GetIssueByRef(ctx, ref); gerr != nil {
+		return gerr
+	}
+	return fmt.Errorf("%s: %w", rel.feature, ErrNotSupported)
+}
+
+// changeErr explains a 404 from changing a relationship of ref. It comes
+// either from a server without rel, told apart by listing ref's
+// relationships, or from the relationship or an issue not existing, which
+// is returned as a NotFoundError prefixed with missing.
+func (c *HTTPClient) changeErr(ctx context.Context, err error, ref IssueRef, rel relationship, missing string) error {
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		return err
+	}
+	path := c.refPath(ref, rel.list+"?per_page=1", ref.Number)
+	if perr := c.do(ctx, http.MethodGet, path, nil, nil); perr != nil {
+		return c.unsupportedErr(ctx, perr, ref, rel)
+	}
+	return fmt.Errorf("%s: %w", missing, err)
+}
@@ -0,0 +1,127 @@
+package github_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// relationshipServer serves issues 1 to 9 without any relationships. With
+// supported unset it answers every relationship endpoint with 404, like a
+// server without the sub-issues and dependencies APIs.
+func relationshipServer(t *testing.T, supported bool) *github.HTTPClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var number int
+		if _, err := fmt.Sscanf(r.URL.Path, "/repos/acme/widgets/issues/%d", &number); err != nil || number < 1 || number > 9 {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+			return
+		}
+		rest := strings.TrimPrefix(r.URL.Path, fmt.Sprintf("/repos/acme/widgets/issues/%d", number))
+		switch {
+		case rest == "":
+			fmt.Fprintf(w, `{"number":%d,"id":%d}`, number, 1000+number)
+		case supported && r.Method == http.MethodGet:
+			fmt.Fprint(w, `[]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return github.NewHTTPClient("acme", "widgets", "token", github.WithBaseURL(srv.URL))
+}
+
+func TestRelationshipNotFound(t *testing.T) {
+	calls := []struct {
+		name string
+		call func(*github.HTTPClient) error
+	}{
+		{"remove sub-issue", func(c *github.HTTPClient) error {
+			_, err := c.RemoveSubIssue(t.Context(), github.IssueRef{Number: 1}, github.IssueRef{Number: 2})
+			return err
+		}},
+		{"remove blocked by", func(c *github.HTTPClient) error {
+			_, err := c.RemoveBlockedBy(t.Context(), github.IssueRef{Number: 1}, github.IssueRef{Number: 2})
+			return err
+		}},
+	}
+	for _, tt := range calls {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(relationshipServer(t, true))
+			var notFound *github.NotFoundError
+			if !errors.As(err, &notFound) || errors.Is(err, github.ErrNotSupported) {
+				t.Errorf("with the API: err = %v, want a NotFoundError", err)
+			}
+
+			err = tt.call(relationshipServer(t, false))
+			if !errors.Is(err, github.ErrNotSupported) {
+				t.Errorf("without the API: err = %v, want ErrNotSupported", err)
+			}
+		})
+	}
+}
+
+func TestRelationshipListNotSupported(t *testing.T) {
+	client := relationshipServer(t, false)
+
+	if _, err := client.ListSubIssues(t.Context(), 1); !errors.Is(err, github.ErrNotSupported) {
+		t.Errorf("err = %v, want ErrNotSupported", err)
+	}
+	_, err := client.ListBlockedBy(t.Context(), 42)
+	var notFound *github.NotFoundError
+	if !errors.As(err, &notFound) || errors.Is(err, github.ErrNotSupported) {
+		t.Errorf("missing issue: err = %v, want a NotFoundError", err)
+	}
+}
+
+func TestRelationshipAcrossRepositories(t *testing.T) {
+	// Issues have the ID 1000 times their repository's position in repos
+	// plus their number, so the IDs sent show which issue was looked up.
+	repos := []string{"acme/widgets", "acme/api"}
+	var changes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i, repo := range repos {
+			var number int
+			if _, err := fmt.Sscanf(r.URL.Path, "/repos/"+repo+"/issues/%d", &number); err != nil {
+				continue
+			}
+			if r.Method == http.MethodGet {
+				fmt.Fprintf(w, `{"number":%d,"id":%d}`, number, 1000*(i+1)+number)
+				return
+			}
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			changes = append(changes, fmt.Sprintf("%s %s %v", r.Method, r.URL.Path, body))
+			fmt.Fprintf(w, `{"number":%d}`, number)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+	client := github.NewHTTPClient("acme", "widgets", "token", github.WithBaseURL(srv.URL))
+
+	local, other := github.IssueRef{Number: 3}, github.IssueRef{Repo: "acme/api", Number: 9}
+	if _, err := client.AddBlockedBy(t.Context(), local, other); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AddSubIssue(t.Context(), other, local); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"POST /repos/acme/widgets/issues/3/dependencies/blocked_by map[issue_id:2009]",
+		"POST /repos/acme/api/issues/9/sub_issues map[replace_parent:true sub_issue_id:1003]",
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("changes:\n%s\nwant:\n%s", strings.Join(changes, "\n"), strings.Join(want, "\n"))
+	}
+}
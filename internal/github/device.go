@@ -0,0 +1,186 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// slowDownStep is added to the polling interval on each slow_down
+	// response, as required by RFC 8628.
+	slowDownStep = 5 * time.Second
+)
+
+// ErrDeviceCodeExpired is returned when the user does not authorize the
+// device before its code expires.
+var ErrDeviceCodeExpired = errors.New("the device code expired before it was authorized; run the login again")
+
+// ErrAccessDenied is returned when the user cancels the authorization.
+var ErrAccessDenied = errors.New("authorization was denied")
+
+// DeviceCode is the response to a device authorization request.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// OAuthToken is an access token issued by the device flow.
+type OAuthToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	Scope       string `json:"scope"`
+}
+
+// DeviceFlow runs the OAuth device authorization flow against a GitHub
+// host, for signing in without pasting a token.
+type DeviceFlow struct {
+	webURL     string
+	clientID   string
+	scopes     []string
+	httpClient *http.Client
+	sleep      func(context.Context, time.Duration) error
+}
+
+// NewDeviceFlow creates a device flow for the OAuth app clientID on the
+// GitHub instance at webURL, such as https://github.com.
+func NewDeviceFlow(webURL, clientID string, scopes []string) *DeviceFlow {
+	return &DeviceFlow{
+		webURL:     strings.TrimSuffix(webURL, "/"),
+		clientID:   clientID,
+		scopes:     scopes,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		sleep:      sleepContext,
+	}
+}
+
+// RequestCode starts the flow, returning the code the user enters at the
+// verification URL.
+func (f *DeviceFlow) RequestCode(ctx context.Context) (*DeviceCode, error) {
+	form := url.Values{"client_id": {f.clientID}}
+	if len(f.scopes) > 0 {
+		form.Set("scope", strings.Join(f.scopes, " "))
+	}
+
+	var code DeviceCode
+	if err := f.post(ctx, "/login/device/code", form, &code); err != nil {
+		return nil, fmt.Errorf("requesting device code: %w", err)
+	}
+	if code.DeviceCode == "" {
+		return nil, errors.New("requesting device code: empty device code in response")
+	}
+	return &code, nil
+}
+
+// PollToken waits for the user to authorize the device, polling at the
+// interval the server asks for and backing off when told to slow down.
+func (f *DeviceFlow) PollToken(ctx context.Context, code *DeviceCode) (*OAuthToken, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = slowDownStep
+	}
+	var deadline time.Time
+	if code.ExpiresIn > 0 {
+		deadline = time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	}
+
+	form := url.Values{
+		"client_id":   {f.clientID},
+		"device_code": {code.DeviceCode},
+		"grant_type":  {deviceGrantType},
+	}
+
+	for {
+		if !deadline.IsZero() && time.Now().Add(interval).After(deadline) {
+			return nil, ErrDeviceCodeExpired
+		}
+		if err := f.sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			OAuthToken
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+			Interval         int    `json:"interval"`
+		}
+		if err := f.post(ctx, "/login/oauth/access_token", form, &resp); err != nil {
+			return nil, fmt.Errorf("polling for access token: %w", err)
+		}
+
+		switch resp.Error {
+		case "":
+			if resp.AccessToken == "" {
+				return nil, errors.New("polling for access token: empty token in response")
+			}
+			return &resp.OAuthToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			if resp.Interval > 0 {
+				interval = time.Duration(resp.Interval) * time.Second
+			} else {
+				interval += slowDownStep
+			}
+		case "expired_token":
+			return nil, ErrDeviceCodeExpired
+		case "access_denied":
+			return nil, ErrAccessDenied
+		default:
+			if resp.ErrorDescription != "" {
+				return nil, fmt.Errorf("device authorization failed: %s (%s)", resp.ErrorDescription, resp.Error)
+			}
+			return nil, fmt.Errorf("device authorization failed: %s", resp.Error)
+		}
+	}
+}
+
+// post sends a form to the OAuth endpoint. OAuth errors come back with a
+// 200 status and an error field, which the caller inspects.
+func (f *DeviceFlow) post(ctx context.Context, path string, form url.Values, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.webURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return newAPIError(resp, body)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
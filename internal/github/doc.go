@@ -1,5 +1,11 @@
 // Package github provides a client for interacting with the GitHub API.
 //
 // It supports creating, closing, and commenting on issues, as well as
-// managing issue assignments.
+// managing issue assignments. Error responses are returned as typed errors
+// (NotFoundError, UnauthorizedError, ForbiddenError, RateLimitError and
+// ValidationError) that can be inspected with errors.As.
+//
+// Requests authenticate with a static token or, through WithTokenSource,
+// with expiring tokens such as those issued by AppTokenSource to a GitHub
+// App installation.
 package github
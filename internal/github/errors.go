@@ -0,0 +1,248 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotSupported is returned when the GitHub server does not offer an API
+// the client needs, typically an older GitHub Enterprise Server release.
+var ErrNotSupported = errors.New("not supported by this GitHub server")
+
+// APIError is the common representation of an error response from the
+// GitHub API. The more specific error types embed it and unwrap to it, so
+// callers can use errors.As with either the specific type or *APIError.
+type APIError struct {
+	StatusCode       int
+	Method           string
+	Path             string
+	Message          string
+	DocumentationURL string
+	Errors           []FieldError
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("github api error (%d)", e.StatusCode)
+	}
+	return fmt.Sprintf("github api error (%d): %s", e.StatusCode, e.Message)
+}
+
+// FieldError describes a single validation failure reported by the API.
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+	Value    any    `json:"value,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// UnmarshalJSON accepts both the structured form and the plain string form
+// that some endpoints use for entries in the errors array.
+func (f *FieldError) UnmarshalJSON(data []byte) error {
+	var msg string
+	if err := json.Unmarshal(data, &msg); err == nil {
+		f.Code = "custom"
+		f.Message = msg
+		return nil
+	}
+
+	type fieldError FieldError
+	var fe fieldError
+	if err := json.Unmarshal(data, &fe); err != nil {
+		return err
+	}
+	*f = FieldError(fe)
+	return nil
+}
+
+func (f FieldError) Error() string {
+	resource := strings.ToLower(f.Resource)
+	switch f.Code {
+	case "missing":
+		return fmt.Sprintf("%s does not exist", resource)
+	case "missing_field":
+		return fmt.Sprintf("%s is required", f.Field)
+	case "invalid":
+		switch {
+		case f.Value != nil && (resource == "label" || f.Field == "labels"):
+			return fmt.Sprintf("label '%v' does not exist", f.Value)
+		case f.Value != nil && f.Field == "assignees":
+			return fmt.Sprintf("user '%v' cannot be assigned", f.Value)
+		case f.Value != nil:
+			return fmt.Sprintf("invalid %s '%v'", f.Field, f.Value)
+		}
+		return fmt.Sprintf("invalid %s", f.Field)
+	case "already_exists":
+		if f.Value != nil {
+			return fmt.Sprintf("%s '%v' already exists", resource, f.Value)
+		}
+		return fmt.Sprintf("%s with this %s already exists", resource, f.Field)
+	case "unprocessable":
+		return fmt.Sprintf("%s cannot be processed", f.Field)
+	}
+	if f.Message != "" {
+		return f.Message
+	}
+	return fmt.Sprintf("%s %s: %s", resource, f.Field, f.Code)
+}
+
+// NotFoundError is returned for 404 responses. GitHub also answers 404 for
+// private resources the token cannot see.
+type NotFoundError struct {
+	APIError
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("not found: %s", e.Path)
+}
+
+func (e *NotFoundError) Unwrap() error { return &e.APIError }
+
+// UnauthorizedError is returned when the token is missing, invalid or expired.
+type UnauthorizedError struct {
+	APIError
+}
+
+func (e *UnauthorizedError) Error() string {
+	return "bad credentials: the GitHub token is invalid or expired"
+}
+
+func (e *UnauthorizedError) Unwrap() error { return &e.APIError }
+
+// ForbiddenError is returned when the token is valid but not allowed to
+// perform the request, usually because of a missing scope or permission.
+type ForbiddenError struct {
+	APIError
+	// AcceptedScopes lists the OAuth scopes that would satisfy the request.
+	AcceptedScopes []string
+	// TokenScopes lists the OAuth scopes granted to a classic token.
+	TokenScopes []string
+	// AcceptedPermissions lists the fine-grained permissions that would
+	// satisfy the request, such as "issues=write".
+	AcceptedPermissions []string
+}
+
+// MissingScopes returns the accepted scopes when the token holds none of them.
+func (e *ForbiddenError) MissingScopes() []string {
+	if len(e.AcceptedScopes) == 0 {
+		return nil
+	}
+	granted := make(map[string]bool, len(e.TokenScopes))
+	for _, s := range e.TokenScopes {
+		granted[s] = true
+	}
+	for _, s := range e.AcceptedScopes {
+		if granted[s] {
+			return nil
+		}
+	}
+	return e.AcceptedScopes
+}
+
+func (e *ForbiddenError) Error() string {
+	if missing := e.MissingScopes(); len(missing) > 0 {
+		return fmt.Sprintf("token lacks %s scope", strings.Join(missing, " or "))
+	}
+	if len(e.AcceptedPermissions) > 0 {
+		return fmt.Sprintf("token lacks %s permission", strings.Join(e.AcceptedPermissions, " or "))
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("forbidden: %s", e.Message)
+	}
+	return "forbidden"
+}
+
+func (e *ForbiddenError) Unwrap() error { return &e.APIError }
+
+// RateLimitError is returned when a rate limit was hit and the required
+// wait exceeded the client's retry budget.
+type RateLimitError struct {
+	APIError
+	// Reset is when the client may retry.
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	wait := time.Until(e.Reset).Round(time.Second)
+	if wait <= 0 {
+		return "github rate limit exceeded"
+	}
+	return fmt.Sprintf("github rate limit exceeded; retry in %s", wait)
+}
+
+func (e *RateLimitError) Unwrap() error { return &e.APIError }
+
+// ValidationError is returned for 422 responses and carries the per-field
+// details reported by the API.
+type ValidationError struct {
+	APIError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("validation failed: %s", e.Message)
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) Unwrap() error { return &e.APIError }
+
+// newAPIError converts an error response into the most specific error type.
+func newAPIError(resp *http.Response, body []byte) error {
+	base := parseAPIError(resp, body)
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return &NotFoundError{APIError: base}
+	case http.StatusUnauthorized:
+		return &UnauthorizedError{APIError: base}
+	case http.StatusForbidden:
+		return &ForbiddenError{
+			APIError:            base,
+			AcceptedScopes:      splitHeaderList(resp.Header.Get("X-Accepted-OAuth-Scopes")),
+			TokenScopes:         splitHeaderList(resp.Header.Get("X-OAuth-Scopes")),
+			AcceptedPermissions: splitHeaderList(resp.Header.Get("X-Accepted-GitHub-Permissions")),
+		}
+	case http.StatusUnprocessableEntity:
+		return &ValidationError{APIError: base}
+	}
+	return &base
+}
+
+func parseAPIError(resp *http.Response, body []byte) APIError {
+	base := APIError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		base.Method = resp.Request.Method
+		base.Path = resp.Request.URL.Path
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+		base.Message = errResp.Message
+		base.DocumentationURL = errResp.DocumentationURL
+		base.Errors = errResp.Errors
+	} else {
+		base.Message = strings.TrimSpace(string(body))
+	}
+
+	return base
+}
+
+func splitHeaderList(v string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,22 @@
+// Package githubtest provides an in-memory fake of the GitHub REST API for
+// testing code built on the github package without network access.
+//
+// A Server serves the issues, comments, search, labels, milestones and
+// assignees endpoints for the repositories seeded into it, with GitHub's
+// pagination Link headers, ETags, error bodies and rate limit headers.
+// Failures, including rate limiting and dropped connections, can be scripted
+// per endpoint with Fail.
+//
+//	srv := githubtest.NewServer()
+//	defer srv.Close()
+//
+//	repo := srv.Repo("acme", "widgets")
+//	repo.AddIssue(github.Issue{Title: "Crash on startup"})
+//
+//	client := srv.Client("acme", "widgets")
+//	issue, err := client.GetIssue(ctx, 1)
+//
+// The API is also served under /api/v3, as on GitHub Enterprise Server, so
+// grit itself can be pointed at the fake by setting the project host to
+// srv.URL and GRIT_PAT to any token.
+package githubtest
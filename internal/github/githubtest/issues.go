@@ -0,0 +1,364 @@
+package githubtest
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// issueNumber parses the {number} path value, answering 404 when it is not
+// an issue in repo.
+func issueNumber(w http.ResponseWriter, r *http.Request, repo *Repo) (*github.Issue, bool) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		notFound(w)
+		return nil, false
+	}
+	issue := repo.issue(number)
+	if issue == nil {
+		notFound(w)
+		return nil, false
+	}
+	return issue, true
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	q := r.URL.Query()
+
+	state := cmp.Or(q.Get("state"), "open")
+	if state != "open" && state != "closed" && state != "all" {
+		writeValidation(w, github.FieldError{Resource: "Issue", Field: "state", Code: "invalid", Value: state})
+		return
+	}
+
+	var labels []string
+	if q.Get("labels") != "" {
+		labels = strings.Split(q.Get("labels"), ",")
+	}
+
+	var since time.Time
+	if q.Get("since") != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, q.Get("since")); err != nil {
+			writeValidation(w, github.FieldError{Resource: "Issue", Field: "since", Code: "invalid", Value: q.Get("since")})
+			return
+		}
+	}
+
+	var issues []github.Issue
+	for _, issue := range repo.issues {
+		if state != "all" && issue.State != state {
+			continue
+		}
+		if issue.UpdatedAt.Before(since) {
+			continue
+		}
+		if !hasLabels(issue, labels) || !matchAssignee(issue, q.Get("assignee")) || !matchMilestone(issue, q.Get("milestone")) {
+			continue
+		}
+		issues = append(issues, *issue)
+	}
+
+	sortIssues(issues, repo, cmp.Or(q.Get("sort"), "created"), q.Get("direction") != "asc")
+	writeJSON(w, http.StatusOK, nonNil(paginate(w, r, issues)))
+}
+
+func hasLabels(issue *github.Issue, labels []string) bool {
+	for _, want := range labels {
+		want = strings.TrimSpace(want)
+		if !slices.ContainsFunc(issue.Labels, func(l github.Label) bool { return strings.EqualFold(l.Name, want) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAssignee filters by a login, "*" for any assignee or "none".
+func matchAssignee(issue *github.Issue, assignee string) bool {
+	switch assignee {
+	case "":
+		return true
+	case "*":
+		return len(issue.Assignees) > 0
+	case "none":
+		return len(issue.Assignees) == 0
+	}
+	return slices.ContainsFunc(issue.Assignees, func(u github.User) bool { return strings.EqualFold(u.Login, assignee) })
+}
+
+// matchMilestone filters by a milestone number, "*" for any milestone or
+// "none".
+func matchMilestone(issue *github.Issue, milestone string) bool {
+	switch milestone {
+	case "":
+		return true
+	case "*":
+		return issue.Milestone != nil
+	case "none":
+		return issue.Milestone == nil
+	}
+	n, err := strconv.Atoi(milestone)
+	return err == nil && issue.Milestone != nil && issue.Milestone.Number == n
+}
+
+// sortIssues orders issues by "created", "updated" or "comments", breaking
+// ties by number.
+func sortIssues(issues []github.Issue, repo *Repo, by string, desc bool) {
+	slices.SortStableFunc(issues, func(a, b github.Issue) int {
+		c := 0
+		switch by {
+		case "updated":
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		case "comments":
+			c = cmp.Compare(len(repo.comments[a.Number]), len(repo.comments[b.Number]))
+		default:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		c = cmp.Or(c, cmp.Compare(a.Number, b.Number))
+		if desc {
+			return -c
+		}
+		return c
+	})
+}
+
+// nonNil returns an empty slice for nil, so that empty lists are encoded
+// as [] rather than null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	issue, ok := issueNumber(w, r, repo)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	var req struct {
+		Title     string   `json:"title"`
+		Body      string   `json:"body"`
+		Labels    []string `json:"labels"`
+		Assignees []string `json:"assignees"`
+		Milestone *int     `json:"milestone"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		writeValidation(w, github.FieldError{Resource: "Issue", Field: "title", Code: "missing_field"})
+		return
+	}
+
+	assignees, ok := resolveAssignees(w, repo, req.Assignees)
+	if !ok {
+		return
+	}
+	var milestone *github.Milestone
+	if req.Milestone != nil {
+		if milestone, ok = resolveMilestone(w, repo, *req.Milestone); !ok {
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	issue := &github.Issue{
+		ID:            s.id(),
+		Number:        repo.nextNumber(),
+		Title:         req.Title,
+		Body:          req.Body,
+		State:         "open",
+		Labels:        repo.resolveLabels(req.Labels),
+		Assignees:     assignees,
+		Milestone:     milestone,
+		RepositoryURL: repo.apiURL(),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	issue.NodeID = "I_" + strconv.FormatInt(issue.ID, 10)
+	issue.HTMLURL = repo.htmlURL() + "/issues/" + strconv.Itoa(issue.Number)
+	repo.issues = append(repo.issues, issue)
+
+	w.Header().Set("Location", repo.apiURL()+"/issues/"+strconv.Itoa(issue.Number))
+	writeJSON(w, http.StatusCreated, issue)
+}
+
+// resolveAssignees checks logins against the repository's assignable users,
+// answering 422 for the first that cannot be assigned.
+func resolveAssignees(w http.ResponseWriter, repo *Repo, logins []string) ([]github.User, bool) {
+	users := []github.User{}
+	for _, login := range logins {
+		a := repo.assignee(login)
+		if a == "" {
+			writeValidation(w, github.FieldError{Resource: "Issue", Field: "assignees", Code: "invalid", Value: login})
+			return nil, false
+		}
+		if !slices.ContainsFunc(users, func(u github.User) bool { return strings.EqualFold(u.Login, a) }) {
+			users = append(users, github.User{Login: a})
+		}
+	}
+	return users, true
+}
+
+func resolveMilestone(w http.ResponseWriter, repo *Repo, number int) (*github.Milestone, bool) {
+	m := repo.milestone(number)
+	if m == nil {
+		writeValidation(w, github.FieldError{Resource: "Issue", Field: "milestone", Code: "invalid", Value: number})
+		return nil, false
+	}
+	return m, true
+}
+
+func (s *Server) updateIssue(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	issue, ok := issueNumber(w, r, repo)
+	if !ok {
+		return
+	}
+
+	var req map[string]json.RawMessage
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	// Validate everything before changing anything, so a rejected update
+	// leaves the issue as it was.
+	updated := *issue
+	if raw, ok := req["title"]; ok {
+		if json.Unmarshal(raw, &updated.Title) != nil || strings.TrimSpace(updated.Title) == "" {
+			writeValidation(w, github.FieldError{Resource: "Issue", Field: "title", Code: "missing_field"})
+			return
+		}
+	}
+	if raw, ok := req["body"]; ok {
+		updated.Body = ""
+		_ = json.Unmarshal(raw, &updated.Body)
+	}
+	if raw, ok := req["state"]; ok {
+		if json.Unmarshal(raw, &updated.State) != nil || (updated.State != "open" && updated.State != "closed") {
+			writeValidation(w, github.FieldError{Resource: "Issue", Field: "state", Code: "invalid", Value: string(raw)})
+			return
+		}
+	}
+	var labels []string
+	rawLabels, setLabels := req["labels"]
+	if setLabels && json.Unmarshal(rawLabels, &labels) != nil {
+		writeValidation(w, github.FieldError{Resource: "Issue", Field: "labels", Code: "invalid"})
+		return
+	}
+	if raw, ok := req["assignees"]; ok {
+		var logins []string
+		if json.Unmarshal(raw, &logins) != nil {
+			writeValidation(w, github.FieldError{Resource: "Issue", Field: "assignees", Code: "invalid"})
+			return
+		}
+		if updated.Assignees, ok = resolveAssignees(w, repo, logins); !ok {
+			return
+		}
+	}
+	if raw, ok := req["milestone"]; ok {
+		var number *int
+		if json.Unmarshal(raw, &number) != nil {
+			writeValidation(w, github.FieldError{Resource: "Issue", Field: "milestone", Code: "invalid"})
+			return
+		}
+		updated.Milestone = nil
+		if number != nil {
+			if updated.Milestone, ok = resolveMilestone(w, repo, *number); !ok {
+				return
+			}
+		}
+	}
+
+	if setLabels {
+		updated.Labels = repo.resolveLabels(labels)
+	}
+	updated.UpdatedAt = time.Now().UTC()
+	*issue = updated
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	issue, ok := issueNumber(w, r, repo)
+	if !ok {
+		return
+	}
+	comments := make([]github.IssueComment, len(repo.comments[issue.Number]))
+	for i, c := range repo.comments[issue.Number] {
+		comments[i] = *c
+	}
+	writeJSON(w, http.StatusOK, nonNil(paginate(w, r, comments)))
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	issue, ok := issueNumber(w, r, repo)
+	if !ok {
+		return
+	}
+	var req github.CreateCommentRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		writeValidation(w, github.FieldError{Resource: "IssueComment", Field: "body", Code: "missing_field"})
+		return
+	}
+
+	c := repo.addComment(issue.Number, s.login, req.Body)
+	issue.UpdatedAt = c.CreatedAt
+	writeJSON(w, http.StatusCreated, c)
+}
+
+// commentID parses the {id} path value, answering 404 when it is not a
+// comment in repo.
+func commentID(w http.ResponseWriter, r *http.Request, repo *Repo) (*github.IssueComment, int, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		notFound(w)
+		return nil, 0, false
+	}
+	c, number := repo.comment(id)
+	if c == nil {
+		notFound(w)
+		return nil, 0, false
+	}
+	return c, number, true
+}
+
+func (s *Server) updateComment(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	c, _, ok := commentID(w, r, repo)
+	if !ok {
+		return
+	}
+	var req github.CreateCommentRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		writeValidation(w, github.FieldError{Resource: "IssueComment", Field: "body", Code: "missing_field"})
+		return
+	}
+	c.Body = req.Body
+	c.UpdatedAt = time.Now().UTC()
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (s *Server) deleteComment(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	c, number, ok := commentID(w, r, repo)
+	if !ok {
+		return
+	}
+	repo.comments[number] = slices.DeleteFunc(repo.comments[number], func(x *github.IssueComment) bool { return x == c })
+	repo.issue(number).Comments--
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,137 @@
+package githubtest
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+func (s *Server) listLabels(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	labels := make([]github.Label, len(repo.labels))
+	for i, l := range repo.labels {
+		labels[i] = *l
+	}
+	writeJSON(w, http.StatusOK, nonNil(paginate(w, r, labels)))
+}
+
+func (s *Server) createLabel(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	var req struct {
+		Name        string `json:"name"`
+		Color       string `json:"color"`
+		Description string `json:"description"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeValidation(w, github.FieldError{Resource: "Label", Field: "name", Code: "missing_field"})
+		return
+	}
+	if repo.label(req.Name) != nil {
+		writeValidation(w, github.FieldError{Resource: "Label", Field: "name", Code: "already_exists", Value: req.Name})
+		return
+	}
+
+	l := repo.addLabel(github.Label{Name: req.Name, Color: req.Color, Description: req.Description})
+	writeJSON(w, http.StatusCreated, l)
+}
+
+func (s *Server) updateLabel(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	l := repo.label(r.PathValue("name"))
+	if l == nil {
+		notFound(w)
+		return
+	}
+	var req struct {
+		NewName     *string `json:"new_name"`
+		Color       *string `json:"color"`
+		Description *string `json:"description"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.NewName != nil {
+		if other := repo.label(*req.NewName); other != nil && other != l {
+			writeValidation(w, github.FieldError{Resource: "Label", Field: "name", Code: "already_exists", Value: *req.NewName})
+			return
+		}
+		l.Name = *req.NewName
+	}
+	if req.Color != nil {
+		l.Color = strings.TrimPrefix(*req.Color, "#")
+	}
+	if req.Description != nil {
+		l.Description = *req.Description
+	}
+
+	for _, issue := range repo.issues {
+		for i := range issue.Labels {
+			if issue.Labels[i].ID == l.ID {
+				issue.Labels[i] = *l
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, l)
+}
+
+func (s *Server) deleteLabel(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	l := repo.label(r.PathValue("name"))
+	if l == nil {
+		notFound(w)
+		return
+	}
+	repo.labels = slices.DeleteFunc(repo.labels, func(x *github.Label) bool { return x == l })
+	for _, issue := range repo.issues {
+		issue.Labels = slices.DeleteFunc(issue.Labels, func(x github.Label) bool { return x.ID == l.ID })
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listMilestones(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	state := cmp.Or(r.URL.Query().Get("state"), "open")
+
+	var milestones []github.Milestone
+	for _, m := range repo.milestones {
+		if state != "all" && m.State != state {
+			continue
+		}
+		counted := *m
+		counted.OpenIssues, counted.ClosedIssues = 0, 0
+		for _, issue := range repo.issues {
+			if issue.Milestone == nil || issue.Milestone.Number != m.Number {
+				continue
+			}
+			if issue.State == "open" {
+				counted.OpenIssues++
+			} else {
+				counted.ClosedIssues++
+			}
+		}
+		milestones = append(milestones, counted)
+	}
+
+	// GitHub sorts by due date, with milestones without one last.
+	slices.SortStableFunc(milestones, func(a, b github.Milestone) int {
+		switch {
+		case a.DueOn == nil && b.DueOn == nil:
+			return cmp.Compare(a.Number, b.Number)
+		case a.DueOn == nil:
+			return 1
+		case b.DueOn == nil:
+			return -1
+		}
+		return cmp.Or(a.DueOn.Compare(*b.DueOn), cmp.Compare(a.Number, b.Number))
+	})
+	writeJSON(w, http.StatusOK, nonNil(paginate(w, r, milestones)))
+}
+
+func (s *Server) listAssignees(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	users := make([]github.User, len(repo.assignees))
+	for i, login := range repo.assignees {
+		users[i] = github.User{Login: login}
+	}
+	writeJSON(w, http.StatusOK, nonNil(paginate(w, r, users)))
+}
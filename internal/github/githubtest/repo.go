@@ -0,0 +1,302 @@
+package githubtest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// defaultLabelColor is the grey GitHub gives labels created without a
+// color.
+const defaultLabelColor = "ededed"
+
+// Repo is a repository held by a Server. Its methods seed and inspect the
+// repository directly, without going through the API.
+type Repo struct {
+	srv   *Server
+	owner string
+	name  string
+
+	issues     []*github.Issue
+	comments   map[int][]*github.IssueComment
+	labels     []*github.Label
+	milestones []*github.Milestone
+	// assignees lists the users who can be assigned. When empty, any login
+	// can be.
+	assignees []string
+}
+
+// FullName returns the repository as owner/name.
+func (r *Repo) FullName() string {
+	return r.owner + "/" + r.name
+}
+
+func (r *Repo) apiURL() string {
+	return fmt.Sprintf("%s/repos/%s/%s", r.srv.URL, r.owner, r.name)
+}
+
+func (r *Repo) htmlURL() string {
+	return fmt.Sprintf("%s/%s/%s", r.srv.URL, r.owner, r.name)
+}
+
+// AddIssue adds an issue and returns it as stored. Number, ID, State, URLs
+// and timestamps are filled in when unset, and labels and milestones it
+// refers to are added to the repository. Set PullRequest to add a pull
+// request, which the issues endpoints return alongside issues.
+func (r *Repo) AddIssue(issue github.Issue) github.Issue {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+
+	if issue.Number == 0 {
+		issue.Number = r.nextNumber()
+	}
+	issue.ID = r.srv.id()
+	if issue.NodeID == "" {
+		issue.NodeID = fmt.Sprintf("I_%d", issue.ID)
+	}
+	if issue.State == "" {
+		issue.State = "open"
+	}
+	issue.HTMLURL = fmt.Sprintf("%s/issues/%d", r.htmlURL(), issue.Number)
+	issue.RepositoryURL = r.apiURL()
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = time.Now().UTC()
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = issue.CreatedAt
+	}
+
+	names := make([]string, len(issue.Labels))
+	for i, l := range issue.Labels {
+		if r.label(l.Name) == nil {
+			r.addLabel(l)
+		}
+		names[i] = l.Name
+	}
+	issue.Labels = r.resolveLabels(names)
+	if issue.Milestone != nil {
+		if m := r.milestone(issue.Milestone.Number); m != nil {
+			issue.Milestone = m
+		} else {
+			issue.Milestone = r.addMilestone(*issue.Milestone)
+		}
+	}
+	issue.Assignees = slices.Clone(issue.Assignees)
+	issue.Comments = 0
+
+	stored := issue
+	r.issues = append(r.issues, &stored)
+	slices.SortFunc(r.issues, func(a, b *github.Issue) int { return a.Number - b.Number })
+	return stored
+}
+
+// Issue returns the issue with the given number.
+func (r *Repo) Issue(number int) (github.Issue, bool) {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+
+	if issue := r.issue(number); issue != nil {
+		return *issue, true
+	}
+	return github.Issue{}, false
+}
+
+// Issues returns every issue and pull request in number order.
+func (r *Repo) Issues() []github.Issue {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+
+	issues := make([]github.Issue, len(r.issues))
+	for i, issue := range r.issues {
+		issues[i] = *issue
+	}
+	return issues
+}
+
+// AddComment adds a comment by login to an issue and returns it. It panics
+// if the issue does not exist.
+func (r *Repo) AddComment(number int, login, body string) github.IssueComment {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+
+	if r.issue(number) == nil {
+		panic(fmt.Sprintf("githubtest: %s has no issue #%d", r.FullName(), number))
+	}
+	return *r.addComment(number, login, body)
+}
+
+// Comments returns the comments on an issue, oldest first.
+func (r *Repo) Comments(number int) []github.IssueComment {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+
+	comments := make([]github.IssueComment, len(r.comments[number]))
+	for i, c := range r.comments[number] {
+		comments[i] = *c
+	}
+	return comments
+}
+
+// AddLabel adds a label and returns it as stored.
+func (r *Repo) AddLabel(label github.Label) github.Label {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+	return *r.addLabel(label)
+}
+
+// Labels returns the repository's labels.
+func (r *Repo) Labels() []github.Label {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+
+	labels := make([]github.Label, len(r.labels))
+	for i, l := range r.labels {
+		labels[i] = *l
+	}
+	return labels
+}
+
+// AddMilestone adds a milestone and returns it as stored. Number and State
+// are filled in when unset.
+func (r *Repo) AddMilestone(m github.Milestone) github.Milestone {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+	return *r.addMilestone(m)
+}
+
+// AddAssignees adds users who can be assigned to issues. Until some are
+// added, any login can be assigned.
+func (r *Repo) AddAssignees(logins ...string) {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+
+	for _, login := range logins {
+		if !slices.ContainsFunc(r.assignees, func(a string) bool { return strings.EqualFold(a, login) }) {
+			r.assignees = append(r.assignees, login)
+		}
+	}
+}
+
+// The methods below expect the caller to hold r.srv.mu.
+
+func (r *Repo) nextNumber() int {
+	if len(r.issues) == 0 {
+		return 1
+	}
+	return r.issues[len(r.issues)-1].Number + 1
+}
+
+func (r *Repo) issue(number int) *github.Issue {
+	for _, issue := range r.issues {
+		if issue.Number == number {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (r *Repo) addComment(number int, login, body string) *github.IssueComment {
+	now := time.Now().UTC()
+	c := &github.IssueComment{
+		ID:        r.srv.id(),
+		Body:      body,
+		User:      github.User{Login: login},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	c.HTMLURL = fmt.Sprintf("%s/issues/%d#issuecomment-%d", r.htmlURL(), number, c.ID)
+	r.comments[number] = append(r.comments[number], c)
+	r.issue(number).Comments++
+	return c
+}
+
+// comment returns the comment with the given ID and the issue it is on.
+func (r *Repo) comment(id int64) (*github.IssueComment, int) {
+	for number, comments := range r.comments {
+		for _, c := range comments {
+			if c.ID == id {
+				return c, number
+			}
+		}
+	}
+	return nil, 0
+}
+
+func (r *Repo) addLabel(label github.Label) *github.Label {
+	label.ID = r.srv.id()
+	label.Color = strings.TrimPrefix(label.Color, "#")
+	if label.Color == "" {
+		label.Color = defaultLabelColor
+	}
+	stored := label
+	r.labels = append(r.labels, &stored)
+	return &stored
+}
+
+// label finds a label by name, ignoring case as GitHub does.
+func (r *Repo) label(name string) *github.Label {
+	for _, l := range r.labels {
+		if strings.EqualFold(l.Name, name) {
+			return l
+		}
+	}
+	return nil
+}
+
+// resolveLabels returns the labels called names, creating any that do not
+// exist as GitHub does when an issue is labeled with a new name.
+func (r *Repo) resolveLabels(names []string) []github.Label {
+	labels := []github.Label{}
+	for _, name := range names {
+		l := r.label(name)
+		if l == nil {
+			l = r.addLabel(github.Label{Name: name})
+		}
+		if !slices.ContainsFunc(labels, func(x github.Label) bool { return x.ID == l.ID }) {
+			labels = append(labels, *l)
+		}
+	}
+	return labels
+}
+
+func (r *Repo) addMilestone(m github.Milestone) *github.Milestone {
+	if m.Number == 0 {
+		for _, existing := range r.milestones {
+			m.Number = max(m.Number, existing.Number)
+		}
+		m.Number++
+	}
+	if m.State == "" {
+		m.State = "open"
+	}
+	m.HTMLURL = fmt.Sprintf("%s/milestone/%d", r.htmlURL(), m.Number)
+	stored := m
+	r.milestones = append(r.milestones, &stored)
+	return &stored
+}
+
+func (r *Repo) milestone(number int) *github.Milestone {
+	for _, m := range r.milestones {
+		if m.Number == number {
+			return m
+		}
+	}
+	return nil
+}
+
+// assignee returns login as spelled by the repository's assignable user,
+// or an empty string when login cannot be assigned.
+func (r *Repo) assignee(login string) string {
+	if len(r.assignees) == 0 {
+		return login
+	}
+	for _, a := range r.assignees {
+		if strings.EqualFold(a, login) {
+			return a
+		}
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package githubtest
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// searchQuery is a parsed issue search query. Qualifiers the server does
+// not know are matched as text, as GitHub does.
+type searchQuery struct {
+	repos     []string
+	kind      string // "issue", "pr" or empty for both
+	state     string
+	labels    []string
+	noLabel   bool
+	assignee  string
+	milestone string
+	terms     []string
+}
+
+func parseSearchQuery(q string) searchQuery {
+	var sq searchQuery
+	for _, token := range splitQuery(q) {
+		key, value, ok := strings.Cut(token, ":")
+		value = strings.Trim(value, `"`)
+		if !ok || value == "" {
+			sq.terms = append(sq.terms, strings.ToLower(strings.Trim(token, `"`)))
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "repo":
+			sq.repos = append(sq.repos, strings.ToLower(value))
+		case "is", "type":
+			switch value {
+			case "issue", "pr":
+				sq.kind = value
+			case "open", "closed":
+				sq.state = value
+			}
+		case "state":
+			sq.state = value
+		case "label":
+			sq.labels = append(sq.labels, value)
+		case "assignee":
+			sq.assignee = value
+		case "milestone":
+			sq.milestone = value
+		case "no":
+			switch value {
+			case "assignee":
+				sq.assignee = "none"
+			case "milestone":
+				sq.milestone = "none"
+			case "label":
+				sq.noLabel = true
+			}
+		default:
+			sq.terms = append(sq.terms, strings.ToLower(token))
+		}
+	}
+	return sq
+}
+
+// splitQuery splits a search query on spaces outside double quotes.
+func splitQuery(q string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ' ' && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+func (sq searchQuery) matches(issue *github.Issue) bool {
+	switch {
+	case sq.kind == "issue" && issue.IsPullRequest(), sq.kind == "pr" && !issue.IsPullRequest():
+		return false
+	case sq.state != "" && issue.State != sq.state:
+		return false
+	case sq.noLabel && len(issue.Labels) > 0:
+		return false
+	case !hasLabels(issue, sq.labels):
+		return false
+	case sq.assignee != "" && !matchAssignee(issue, sq.assignee):
+		return false
+	}
+
+	switch sq.milestone {
+	case "":
+	case "none":
+		if issue.Milestone != nil {
+			return false
+		}
+	default:
+		if issue.Milestone == nil || !strings.EqualFold(issue.Milestone.Title, sq.milestone) {
+			return false
+		}
+	}
+
+	text := strings.ToLower(issue.Title + "\n" + issue.Body)
+	for _, term := range sq.terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) searchIssues(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if strings.TrimSpace(q.Get("q")) == "" {
+		writeValidation(w, github.FieldError{Resource: "Search", Field: "q", Code: "missing"})
+		return
+	}
+
+	perPage, page := pageParams(r)
+	if (page-1)*perPage >= maxSearchResults {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Only the first %d search results are available", maxSearchResults))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sq := parseSearchQuery(q.Get("q"))
+	var items []github.Issue
+	repos := make(map[string]*Repo)
+	for key, repo := range s.repos {
+		if len(sq.repos) > 0 && !slices.Contains(sq.repos, key) {
+			continue
+		}
+		for _, issue := range repo.issues {
+			if sq.matches(issue) {
+				items = append(items, *issue)
+				repos[issue.HTMLURL] = repo
+			}
+		}
+	}
+
+	// Results from several repositories are ordered by the chosen field,
+	// or by most recently updated in place of relevance.
+	by, desc := q.Get("sort"), q.Get("order") != "asc"
+	if by == "" {
+		by, desc = "updated", true
+	}
+	slices.SortStableFunc(items, func(a, b github.Issue) int {
+		ra, rb := repos[a.HTMLURL], repos[b.HTMLURL]
+		c := 0
+		switch by {
+		case "created":
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		case "comments":
+			c = len(ra.comments[a.Number]) - len(rb.comments[b.Number])
+		default:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		}
+		if c == 0 {
+			c = strings.Compare(a.HTMLURL, b.HTMLURL)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+
+	total := len(items)
+	items = items[:min(len(items), maxSearchResults)]
+	writeJSON(w, http.StatusOK, struct {
+		TotalCount        int            `json:"total_count"`
+		IncompleteResults bool           `json:"incomplete_results"`
+		Items             []github.Issue `json:"items"`
+	}{total, false, nonNil(paginate(w, r, items))})
+}
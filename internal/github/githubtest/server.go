@@ -0,0 +1,549 @@
+package githubtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+const (
+	// DefaultLogin is the user the server authenticates every token as.
+	DefaultLogin = "octocat"
+
+	// Rate limit resources, as reported in X-RateLimit-Resource.
+	ResourceCore   = "core"
+	ResourceSearch = "search"
+
+	// enterprisePrefix is the API root on GitHub Enterprise Server, which
+	// the server also answers under.
+	enterprisePrefix = "/api/v3"
+	defaultPerPage   = 30
+	maxPerPage       = 100
+	// maxSearchResults is how many results GitHub's search API will page
+	// through.
+	maxSearchResults = 1000
+	docsURL          = "https://docs.github.com/rest"
+)
+
+// Server is a fake GitHub REST API backed by in-memory repositories.
+// It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, without a trailing slash.
+	URL string
+
+	srv   *httptest.Server
+	mux   *http.ServeMux
+	token string
+	login string
+
+	mu         sync.Mutex
+	repos      map[string]*Repo
+	nextID     int64
+	failures   []*Failure
+	requests   []Request
+	rateLimits map[string]*rateLimit
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithToken makes the server reject requests that do not carry token as a
+// bearer token. By default any token is accepted.
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithLogin sets the user the server authenticates requests as. Comments
+// created through the API are attributed to this user.
+func WithLogin(login string) Option {
+	return func(s *Server) {
+		s.login = login
+	}
+}
+
+// WithRateLimit sets how many requests to a rate limit resource are allowed
+// in each window. The defaults match GitHub's for authenticated users: 5000
+// core requests an hour and 30 searches a minute.
+func WithRateLimit(resource string, limit int, window time.Duration) Option {
+	return func(s *Server) {
+		s.rateLimits[resource] = &rateLimit{limit: limit, remaining: limit, window: window}
+	}
+}
+
+// NewServer starts a fake GitHub API server with no repositories. Call
+// Close when done with it.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		login: DefaultLogin,
+		repos: make(map[string]*Repo),
+		rateLimits: map[string]*rateLimit{
+			ResourceCore:   {limit: 5000, remaining: 5000, window: time.Hour},
+			ResourceSearch: {limit: 30, remaining: 30, window: time.Minute},
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux = http.NewServeMux()
+	s.routes()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a client for the repository owner/repo that talks to the
+// server. Options are applied after those pointing it at the server.
+func (s *Server) Client(owner, repo string, opts ...github.Option) *github.HTTPClient {
+	token := s.token
+	if token == "" {
+		token = "githubtest"
+	}
+	base := []github.Option{
+		github.WithBaseURL(s.URL),
+		github.WithGraphQLURL(s.URL + "/graphql"),
+		github.WithHTTPClient(s.srv.Client()),
+	}
+	return github.NewHTTPClient(owner, repo, token, append(base, opts...)...)
+}
+
+// Repo returns the repository owner/name, creating it if it does not exist.
+func (s *Server) Repo(owner, name string) *Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(owner + "/" + name)
+	r, ok := s.repos[key]
+	if !ok {
+		r = &Repo{srv: s, owner: owner, name: name, comments: make(map[int][]*github.IssueComment)}
+		s.repos[key] = r
+	}
+	return r
+}
+
+// repo returns an existing repository. The caller must hold s.mu.
+func (s *Server) repo(owner, name string) (*Repo, bool) {
+	r, ok := s.repos[strings.ToLower(owner+"/"+name)]
+	return r, ok
+}
+
+// id returns a new unique ID for an issue, comment or label. The caller
+// must hold s.mu.
+func (s *Server) id() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	// Path is the request path, without any /api/v3 prefix.
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Failure makes matching requests fail instead of being served.
+type Failure struct {
+	// Method is the HTTP method to match, or empty for any.
+	Method string
+	// Path is a path.Match pattern for the request path, without any
+	// /api/v3 prefix, such as "/repos/*/*/issues". Empty matches any path.
+	Path string
+	// Times is how many matching requests fail before the failure is
+	// used up. Zero fails every matching request.
+	Times int
+
+	// Status is the response status, 500 when unset.
+	Status int
+	// Message is the error message in the response body, the status text
+	// when unset.
+	Message string
+	// Errors are the validation errors included in the response body.
+	Errors []github.FieldError
+	// Header holds extra response headers, such as Retry-After.
+	Header http.Header
+	// Drop closes the connection without a response instead.
+	Drop bool
+}
+
+// ServerError fails the next times requests matching method and pattern
+// with a 502, which clients retry for idempotent methods.
+func ServerError(method, pattern string, times int) Failure {
+	return Failure{Method: method, Path: pattern, Times: times, Status: http.StatusBadGateway}
+}
+
+// SecondaryRateLimit fails the next times requests matching method and
+// pattern as GitHub does when a secondary rate limit is hit, asking the
+// client to retry after retryAfter.
+func SecondaryRateLimit(method, pattern string, retryAfter time.Duration, times int) Failure {
+	return Failure{
+		Method:  method,
+		Path:    pattern,
+		Times:   times,
+		Status:  http.StatusForbidden,
+		Message: "You have exceeded a secondary rate limit. Please wait a few minutes before you try again.",
+		Header:  http.Header{"Retry-After": {strconv.Itoa(int(retryAfter / time.Second))}},
+	}
+}
+
+// Fail scripts a failure. Failures are checked in the order they were
+// added, before authentication and rate limiting.
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// ClearFailures removes every scripted failure.
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = nil
+}
+
+// matchFailure returns the first failure matching the request and uses up
+// one of its times. The caller must hold s.mu.
+func (s *Server) matchFailure(method, p string) *Failure {
+	for i, f := range s.failures {
+		if f.Method != "" && !strings.EqualFold(f.Method, method) {
+			continue
+		}
+		if f.Path != "" {
+			if ok, _ := path.Match(f.Path, p); !ok {
+				continue
+			}
+		}
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = append(s.failures[:i:i], s.failures[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+type rateLimit struct {
+	limit     int
+	remaining int
+	window    time.Duration
+	reset     time.Time
+}
+
+// SetRateLimitRemaining sets how many requests to resource are left in the
+// current window, for example zero to make the next one fail.
+func (s *Server) SetRateLimitRemaining(resource string, remaining int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rl := s.rateLimits[resource]
+	if rl == nil {
+		return
+	}
+	rl.take(time.Now(), 0)
+	rl.remaining = min(remaining, rl.limit)
+}
+
+// take starts a new window if the current one has passed and uses up n
+// requests, reporting whether they were allowed.
+func (rl *rateLimit) take(now time.Time, n int) bool {
+	if !now.Before(rl.reset) {
+		rl.remaining = rl.limit
+		rl.reset = now.Add(rl.window)
+	}
+	if rl.remaining < n {
+		return false
+	}
+	rl.remaining -= n
+	return true
+}
+
+func (rl *rateLimit) setHeaders(h http.Header, resource string) {
+	h.Set("X-RateLimit-Limit", strconv.Itoa(rl.limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(rl.remaining))
+	h.Set("X-RateLimit-Used", strconv.Itoa(rl.limit-rl.remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(rl.reset.Unix(), 10))
+	h.Set("X-RateLimit-Resource", resource)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if p, ok := strings.CutPrefix(r.URL.Path, enterprisePrefix); ok && (p == "" || p[0] == '/') {
+		r.URL.Path = p
+		r.URL.RawPath = ""
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	failure := s.matchFailure(r.Method, r.URL.Path)
+	s.mu.Unlock()
+
+	if failure != nil {
+		writeFailure(w, failure)
+		return
+	}
+
+	if s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token && r.Header.Get("Authorization") != "token "+s.token {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+
+	resource := ResourceCore
+	if strings.HasPrefix(r.URL.Path, "/search/") {
+		resource = ResourceSearch
+	}
+
+	// GET responses carry an ETag. Like GitHub, the server answers a
+	// matching If-None-Match with 304 Not Modified, which is not counted
+	// against the rate limit.
+	var rec *httptest.ResponseRecorder
+	if r.Method == http.MethodGet {
+		rec = httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, r)
+		if rec.Code == http.StatusOK {
+			etag := fmt.Sprintf(`W/"%x"`, sha256.Sum256(rec.Body.Bytes()))
+			rec.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				s.mu.Lock()
+				s.rateLimits[resource].setHeaders(w.Header(), resource)
+				s.mu.Unlock()
+				w.Header().Set("ETag", etag)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+	}
+
+	s.mu.Lock()
+	rl := s.rateLimits[resource]
+	allowed := rl.take(time.Now(), 1)
+	rl.setHeaders(w.Header(), resource)
+	s.mu.Unlock()
+	if !allowed {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("API rate limit exceeded for user %s.", s.login))
+		return
+	}
+
+	if rec == nil {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(rec.Body.Bytes())
+}
+
+func writeFailure(w http.ResponseWriter, f *Failure) {
+	if f.Drop {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	for k, v := range f.Header {
+		w.Header()[k] = v
+	}
+	status := f.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	message := f.Message
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	writeJSON(w, status, github.ErrorResponse{Message: message, DocumentationURL: docsURL, Errors: f.Errors})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, github.ErrorResponse{Message: message, DocumentationURL: docsURL})
+}
+
+// writeValidation answers 422 with a single field error, as GitHub does
+// for invalid request bodies.
+func writeValidation(w http.ResponseWriter, fe github.FieldError) {
+	writeJSON(w, http.StatusUnprocessableEntity, github.ErrorResponse{
+		Message:          "Validation Failed",
+		DocumentationURL: docsURL,
+		Errors:           []github.FieldError{fe},
+	})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// pageParams reads per_page and page, applying GitHub's default and maximum
+// page sizes.
+func pageParams(r *http.Request) (perPage, page int) {
+	perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	perPage = min(perPage, maxPerPage)
+
+	page, err = strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	return perPage, page
+}
+
+// paginate returns the requested page of items and sets the Link header
+// pointing at the first, previous, next and last pages, as GitHub does.
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) []T {
+	perPage, page := pageParams(r)
+	last := max(1, (len(items)+perPage-1)/perPage)
+
+	var links []string
+	link := func(p int, rel string) {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(p))
+		if q.Get("per_page") != "" {
+			q.Set("per_page", strconv.Itoa(perPage))
+		}
+		u := url.URL{Scheme: "http", Host: r.Host, Path: requestPath(r), RawQuery: q.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=%q", u.String(), rel))
+	}
+	if page < last {
+		link(page+1, "next")
+		link(last, "last")
+	}
+	if page > 1 {
+		link(1, "first")
+		link(min(page-1, last), "prev")
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	return items[start:end]
+}
+
+// requestPath returns the path the client requested, including any
+// /api/v3 prefix removed by serveHTTP, so that Link headers point back at
+// the same API root.
+func requestPath(r *http.Request) string {
+	if strings.HasPrefix(r.RequestURI, enterprisePrefix) {
+		return enterprisePrefix + r.URL.Path
+	}
+	return r.URL.Path
+}
+
+// decodeBody decodes a JSON request body, answering 400 when it is
+// malformed.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return false
+	}
+	return true
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /user", s.getUser)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}", s.withRepo(s.getRepository))
+
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/issues", s.withRepo(s.listIssues))
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/issues", s.withRepo(s.createIssue))
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}", s.withRepo(s.getIssue))
+	s.mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/{number}", s.withRepo(s.updateIssue))
+
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/comments", s.withRepo(s.listComments))
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/comments", s.withRepo(s.createComment))
+	s.mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/comments/{id}", s.withRepo(s.updateComment))
+	s.mux.HandleFunc("DELETE /repos/{owner}/{repo}/issues/comments/{id}", s.withRepo(s.deleteComment))
+
+	s.mux.HandleFunc("GET /search/issues", s.searchIssues)
+
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/labels", s.withRepo(s.listLabels))
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/labels", s.withRepo(s.createLabel))
+	s.mux.HandleFunc("PATCH /repos/{owner}/{repo}/labels/{name}", s.withRepo(s.updateLabel))
+	s.mux.HandleFunc("DELETE /repos/{owner}/{repo}/labels/{name}", s.withRepo(s.deleteLabel))
+
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/milestones", s.withRepo(s.listMilestones))
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/assignees", s.withRepo(s.listAssignees))
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) { notFound(w) })
+}
+
+// withRepo looks up the request's repository and calls h with the server
+// locked, answering 404 for unknown repositories.
+func (s *Server) withRepo(h func(http.ResponseWriter, *http.Request, *Repo)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		repo, ok := s.repo(r.PathValue("owner"), r.PathValue("repo"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		h(w, r, repo)
+	}
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-OAuth-Scopes", "repo")
+	writeJSON(w, http.StatusOK, map[string]string{"login": s.login})
+}
+
+func (s *Server) getRepository(w http.ResponseWriter, r *http.Request, repo *Repo) {
+	writeJSON(w, http.StatusOK, github.RepositoryInfo{
+		FullName:    repo.FullName(),
+		HasIssues:   true,
+		Permissions: &github.RepoPermissions{Admin: true, Maintain: true, Push: true, Triage: true, Pull: true},
+	})
+}
@@ -0,0 +1,257 @@
+package githubtest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/github/githubtest"
+)
+
+// get sends a GET to the server with the given headers and returns the
+// response with its body read.
+func get(t *testing.T, srv *githubtest.Server, path string, header http.Header) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, body
+}
+
+func seed(srv *githubtest.Server, n int) *githubtest.Repo {
+	repo := srv.Repo("acme", "widgets")
+	for range n {
+		repo.AddIssue(github.Issue{Title: "issue"})
+	}
+	return repo
+}
+
+func TestPaginationLinks(t *testing.T) {
+	srv := githubtest.NewServer()
+	defer srv.Close()
+	seed(srv, 25)
+
+	tests := []struct {
+		page      string
+		wantCount int
+		wantRels  []string
+	}{
+		{"1", 10, []string{"next", "last"}},
+		{"2", 10, []string{"next", "last", "first", "prev"}},
+		{"3", 5, []string{"first", "prev"}},
+	}
+	for _, tt := range tests {
+		t.Run("page "+tt.page, func(t *testing.T) {
+			resp, body := get(t, srv, "/repos/acme/widgets/issues?per_page=10&page="+tt.page, nil)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+
+			var issues []github.Issue
+			if err := json.Unmarshal(body, &issues); err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != tt.wantCount {
+				t.Errorf("got %d issues, want %d", len(issues), tt.wantCount)
+			}
+
+			link := resp.Header.Get("Link")
+			for _, rel := range tt.wantRels {
+				if !strings.Contains(link, `rel="`+rel+`"`) {
+					t.Errorf("Link %q has no rel=%q", link, rel)
+				}
+			}
+			if strings.Count(link, "rel=") != len(tt.wantRels) {
+				t.Errorf("Link %q, want only %v", link, tt.wantRels)
+			}
+		})
+	}
+}
+
+func TestPaginationLinksKeepEnterprisePrefix(t *testing.T) {
+	srv := githubtest.NewServer()
+	defer srv.Close()
+	seed(srv, 3)
+
+	resp, _ := get(t, srv, "/api/v3/repos/acme/widgets/issues?per_page=1", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if link := resp.Header.Get("Link"); !strings.Contains(link, srv.URL+"/api/v3/repos/acme/widgets/issues?") {
+		t.Errorf("Link %q does not point back under /api/v3", link)
+	}
+}
+
+func TestETagNotModified(t *testing.T) {
+	srv := githubtest.NewServer()
+	defer srv.Close()
+	repo := seed(srv, 1)
+
+	resp, _ := get(t, srv, "/repos/acme/widgets/issues/1", nil)
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+
+	resp, body := get(t, srv, "/repos/acme/widgets/issues/1", http.Header{"If-None-Match": {etag}})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("status = %d, want 304", resp.StatusCode)
+	}
+	if len(body) != 0 {
+		t.Errorf("304 has a body: %q", body)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != remaining {
+		t.Errorf("X-RateLimit-Remaining = %s after a 304, want %s unchanged", got, remaining)
+	}
+
+	repo.AddComment(1, "hubot", "changes the issue")
+	resp, _ = get(t, srv, "/repos/acme/widgets/issues/1", http.Header{"If-None-Match": {etag}})
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d after the issue changed, want 200", resp.StatusCode)
+	}
+	if resp.Header.Get("ETag") == etag {
+		t.Error("ETag did not change with the issue")
+	}
+}
+
+func TestErrorBodies(t *testing.T) {
+	srv := githubtest.NewServer()
+	defer srv.Close()
+	seed(srv, 1)
+	client := srv.Client("acme", "widgets")
+
+	t.Run("not found", func(t *testing.T) {
+		resp, body := get(t, srv, "/repos/acme/widgets/issues/99", nil)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("status = %d, want 404", resp.StatusCode)
+		}
+		var e github.ErrorResponse
+		if err := json.Unmarshal(body, &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Message != "Not Found" || e.DocumentationURL == "" {
+			t.Errorf("body = %+v, want GitHub's Not Found error", e)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := client.CreateIssue(t.Context(), github.CreateIssueRequest{Title: " "})
+		var validation *github.ValidationError
+		if !errors.As(err, &validation) {
+			t.Fatalf("err = %v, want a ValidationError", err)
+		}
+		if len(validation.Errors) != 1 || validation.Errors[0].Field != "title" || validation.Errors[0].Code != "missing_field" {
+			t.Errorf("errors = %+v, want title missing_field", validation.Errors)
+		}
+	})
+
+	t.Run("bad credentials", func(t *testing.T) {
+		srv := githubtest.NewServer(githubtest.WithToken("secret"))
+		defer srv.Close()
+		srv.Repo("acme", "widgets")
+
+		wrong := github.NewHTTPClient("acme", "widgets", "wrong", github.WithBaseURL(srv.URL))
+		_, err := wrong.ListLabels(t.Context())
+		var unauthorized *github.UnauthorizedError
+		if !errors.As(err, &unauthorized) {
+			t.Errorf("err = %v, want an UnauthorizedError", err)
+		}
+		if _, err := srv.Client("acme", "widgets").ListLabels(t.Context()); err != nil {
+			t.Errorf("with the right token: %v", err)
+		}
+	})
+}
+
+func TestFail(t *testing.T) {
+	srv := githubtest.NewServer()
+	defer srv.Close()
+	seed(srv, 1)
+
+	srv.Fail(githubtest.Failure{
+		Method:  http.MethodGet,
+		Path:    "/repos/*/*/issues/*",
+		Times:   2,
+		Status:  http.StatusServiceUnavailable,
+		Message: "try later",
+	})
+
+	for i := range 2 {
+		resp, body := get(t, srv, "/repos/acme/widgets/issues/1", nil)
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("request %d: status = %d, want 503", i+1, resp.StatusCode)
+		}
+		if !strings.Contains(string(body), "try later") {
+			t.Errorf("request %d: body %q lacks the message", i+1, body)
+		}
+	}
+	if resp, _ := get(t, srv, "/repos/acme/widgets/issues/1", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("after the failure was used up: status = %d, want 200", resp.StatusCode)
+	}
+
+	t.Run("other paths", func(t *testing.T) {
+		srv.Fail(githubtest.Failure{Path: "/repos/*/*/labels"})
+		defer srv.ClearFailures()
+		if resp, _ := get(t, srv, "/repos/acme/widgets/issues", nil); resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d for an unmatched path, want 200", resp.StatusCode)
+		}
+		if resp, _ := get(t, srv, "/repos/acme/widgets/labels", nil); resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("status = %d for a matched path, want 500", resp.StatusCode)
+		}
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		srv.Fail(githubtest.Failure{Drop: true})
+		defer srv.ClearFailures()
+		if _, err := http.Get(srv.URL + "/repos/acme/widgets/issues"); err == nil {
+			t.Error("request succeeded, want the connection dropped")
+		}
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	srv := githubtest.NewServer(githubtest.WithRateLimit(githubtest.ResourceCore, 2, time.Hour))
+	defer srv.Close()
+	seed(srv, 1)
+
+	for i := range 2 {
+		resp, _ := get(t, srv, "/repos/acme/widgets/issues", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, resp.StatusCode)
+		}
+	}
+
+	resp, body := get(t, srv, "/repos/acme/widgets/issues", nil)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", resp.StatusCode)
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" || resp.Header.Get("X-RateLimit-Reset") == "" {
+		t.Errorf("rate limit headers = %v", resp.Header)
+	}
+	if !strings.Contains(string(body), "API rate limit exceeded") {
+		t.Errorf("body = %q", body)
+	}
+
+	// Searches have their own limit.
+	if resp, _ := get(t, srv, "/search/issues?q=issue", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("search: status = %d, want 200", resp.StatusCode)
+	}
+}
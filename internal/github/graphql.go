@@ -0,0 +1,58 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []GraphQLError  `json:"errors,omitempty"`
+}
+
+// GraphQLError is a single error entry returned by the GraphQL API.
+type GraphQLError struct {
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// GraphQLErrors is returned when a GraphQL response contains errors.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, ge := range e {
+		msgs[i] = ge.Message
+	}
+	return fmt.Sprintf("github graphql error: %s", strings.Join(msgs, "; "))
+}
+
+// GraphQL executes a query or mutation against the GraphQL endpoint and
+// decodes the data field into result.
+func (c *HTTPClient) GraphQL(ctx context.Context, query string, variables map[string]any, result any) error {
+	var resp graphQLResponse
+	req := graphQLRequest{Query: query, Variables: variables}
+	if _, err := c.doWithHeader(ctx, http.MethodPost, c.graphqlURL, req, &resp); err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		return GraphQLErrors(resp.Errors)
+	}
+
+	if result != nil && len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, result); err != nil {
+			return fmt.Errorf("parsing graphql data: %w", err)
+		}
+	}
+	return nil
+}
@@ -6,84 +6,260 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+const (
+	defaultMaxRetries      = 3
+	defaultMaxWait         = time.Minute
+	retryBase              = 500 * time.Millisecond
+	secondaryRateLimitBase = time.Minute
+)
+
 // HTTPClient implements Client using the GitHub REST API.
 type HTTPClient struct {
 	baseURL    string
+	graphqlURL string
 	token      string
 	owner      string
 	repo       string
 	httpClient *http.Client
+	maxRetries int
+	maxWait    time.Duration
+
+	tokenSource TokenSource
+	cache       *ResponseCache
+
+	mu        sync.Mutex
+	rateLimit RateLimit
+}
+
+// Option configures an HTTPClient.
+type Option func(*HTTPClient)
+
+// WithBaseURL points the client at a different REST API root, such as the
+// /api/v3 endpoint of a GitHub Enterprise Server instance.
+func WithBaseURL(baseURL string) Option {
+	return func(c *HTTPClient) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithGraphQLURL sets the GraphQL endpoint used alongside the REST API.
+func WithGraphQLURL(graphqlURL string) Option {
+	return func(c *HTTPClient) {
+		c.graphqlURL = graphqlURL
+	}
+}
+
+// WithMaxRetries sets how many times a failed request is retried.
+func WithMaxRetries(n int) Option {
+	return func(c *HTTPClient) {
+		c.maxRetries = n
+	}
+}
+
+// WithMaxWait sets the longest single delay the client will sleep before
+// retrying. Requests that would need to wait longer fail immediately.
+func WithMaxWait(d time.Duration) Option {
+	return func(c *HTTPClient) {
+		c.maxWait = d
+	}
+}
+
+// WithHTTPClient sets the HTTP client requests are sent with, for example
+// one that trusts a test server's certificate.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *HTTPClient) {
+		c.httpClient = hc
+	}
 }
 
 // NewHTTPClient creates a new GitHub API client for the specified repository.
-func NewHTTPClient(owner, repo, token string) *HTTPClient {
-	return &HTTPClient{
-		baseURL: "https://api.github.com",
-		token:   token,
-		owner:   owner,
-		repo:    repo,
+func NewHTTPClient(owner, repo, token string, opts ...Option) *HTTPClient {
+	c := &HTTPClient{
+		baseURL:    "https://api.github.com",
+		graphqlURL: "https://api.github.com/graphql",
+		token:      token,
+		owner:      owner,
+		repo:       repo,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		maxRetries: defaultMaxRetries,
+		maxWait:    defaultMaxWait,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
+
+// RateLimit returns the rate limit state observed on the most recent response.
+func (c *HTTPClient) RateLimit() RateLimit {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rateLimit
 }
 
 func (c *HTTPClient) do(ctx context.Context, method, path string, body, result any) error {
-	var bodyReader io.Reader
+	_, err := c.doWithHeader(ctx, method, path, body, result)
+	return err
+}
+
+// doWithHeader performs the request like do and also returns the response
+// headers. The path may be an absolute URL, as found in Link headers.
+func (c *HTTPClient) doWithHeader(ctx context.Context, method, path string, body, result any) (http.Header, error) {
+	var payload []byte
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
-			return fmt.Errorf("marshaling request body: %w", err)
+			return nil, fmt.Errorf("marshaling request body: %w", err)
 		}
-		bodyReader = bytes.NewReader(jsonBody)
+		payload = jsonBody
+	}
+
+	url := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		url = c.baseURL + path
+	}
+
+	token := c.token
+	if c.tokenSource != nil {
+		var err error
+		if token, err = c.tokenSource.Token(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var key string
+	var cached *cachedResponse
+	var conditional http.Header
+	if c.cache != nil && method == http.MethodGet {
+		key = c.cacheKey(url, token)
+		if entry, ok := c.cache.get(key); ok {
+			cached, conditional = entry, entry.revalidate()
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, respBody, err := c.send(ctx, method, url, token, payload, conditional)
+		if err != nil {
+			if ctx.Err() == nil && isIdempotent(method) && attempt < c.maxRetries {
+				if werr := c.sleep(ctx, backoff(retryBase, attempt)); werr == nil {
+					continue
+				}
+			}
+			return nil, err
+		}
+
+		if resp.StatusCode >= 400 {
+			if isRateLimited(resp, respBody) {
+				delay := rateLimitDelay(resp.Header, attempt, time.Now(), c.maxWait)
+				if attempt < c.maxRetries && delay <= c.maxWait {
+					if err := c.sleep(ctx, delay); err != nil {
+						return nil, fmt.Errorf("waiting for rate limit reset: %w", err)
+					}
+					continue
+				}
+				return nil, &RateLimitError{
+					APIError: parseAPIError(resp, respBody),
+					Reset:    time.Now().Add(delay),
+				}
+			}
+
+			if isRetryableStatus(resp.StatusCode) && isIdempotent(method) && attempt < c.maxRetries {
+				if err := c.sleep(ctx, backoff(retryBase, attempt)); err == nil {
+					continue
+				}
+			}
+
+			return nil, newAPIError(resp, respBody)
+		}
+
+		header := resp.Header
+		switch {
+		case resp.StatusCode == http.StatusNotModified && cached != nil:
+			header, respBody = cached.notModified(resp.Header), cached.Body
+		case key != "" && resp.StatusCode == http.StatusOK:
+			c.cache.put(key, resp.Header, respBody)
+		}
+
+		if result != nil && len(respBody) > 0 {
+			if err := json.Unmarshal(respBody, result); err != nil {
+				return nil, fmt.Errorf("parsing response: %w", err)
+			}
+		}
+
+		return header, nil
+	}
+}
+
+// send performs a single HTTP round trip, with any extra headers, and
+// records the rate limit headers.
+func (c *HTTPClient) send(ctx context.Context, method, url, token string, payload []byte, extra http.Header) (*http.Response, []byte, error) {
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
 	}
 
-	url := c.baseURL + path
 	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return nil, nil, fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-	if body != nil {
+	if payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	for k, v := range extra {
+		req.Header[k] = v
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("executing request: %w", err)
+		return nil, nil, fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("reading response body: %w", err)
+		return nil, nil, fmt.Errorf("reading response body: %w", err)
 	}
 
-	if resp.StatusCode >= 400 {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Message != "" {
-			return fmt.Errorf("github api error (%d): %s", resp.StatusCode, errResp.Message)
-		}
-		return fmt.Errorf("github api error (%d): %s", resp.StatusCode, string(respBody))
+	if rl, ok := parseRateLimit(resp.Header); ok {
+		c.mu.Lock()
+		c.rateLimit = rl
+		c.mu.Unlock()
 	}
 
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("parsing response: %w", err)
-		}
+	return resp, respBody, nil
+}
+
+// sleep waits for d, returning early with an error if the context is
+// cancelled or its deadline would pass before the wait completes.
+func (c *HTTPClient) sleep(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return context.DeadlineExceeded
 	}
 
-	return nil
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 func (c *HTTPClient) repoPath(format string, args ...any) string {
@@ -91,7 +267,35 @@ func (c *HTTPClient) repoPath(format string, args ...any) string {
 	return prefix + fmt.Sprintf(format, args...)
 }
 
-func (c *HTTPClient) ListIssues(ctx context.Context, req ListIssuesRequest) ([]Issue, error) {
+// refPath is repoPath for the repository of ref.
+func (c *HTTPClient) refPath(ref IssueRef, format string, args ...any) string {
+	if ref.Repo == "" {
+		return c.repoPath(format, args...)
+	}
+	return "/repos/" + ref.Repo + fmt.Sprintf(format, args...)
+}
+
+func (c *HTTPClient) ListIssues(ctx context.Context, req ListIssuesRequest) (*ListIssuesResponse, error) {
+	var issues []Issue
+	header, err := c.doWithHeader(ctx, http.MethodGet, c.listIssuesPath(req), nil, &issues)
+	if err != nil {
+		return nil, err
+	}
+	return &ListIssuesResponse{Issues: issues, HasNext: nextPageURL(header) != ""}, nil
+}
+
+// ListAllIssues returns an iterator over every issue matching req, following
+// pagination links until the last page. req.Page is used as the first page.
+func (c *HTTPClient) ListAllIssues(ctx context.Context, req ListIssuesRequest) iter.Seq2[Issue, error] {
+	if req.PerPage <= 0 {
+		req.PerPage = maxPerPage
+	}
+	return paginate(ctx, c, c.listIssuesPath(req), func(page []Issue) []Issue {
+		return page
+	})
+}
+
+func (c *HTTPClient) listIssuesPath(req ListIssuesRequest) string {
 	params := url.Values{}
 
 	if req.State != "" {
@@ -103,6 +307,12 @@ func (c *HTTPClient) ListIssues(ctx context.Context, req ListIssuesRequest) ([]I
 	if req.Labels != "" {
 		params.Set("labels", req.Labels)
 	}
+	if req.Milestone != "" {
+		params.Set("milestone", req.Milestone)
+	}
+	if !req.Since.IsZero() {
+		params.Set("since", req.Since.UTC().Format(time.RFC3339))
+	}
 	if req.PerPage > 0 {
 		params.Set("per_page", strconv.Itoa(req.PerPage))
 	}
@@ -114,12 +324,7 @@ func (c *HTTPClient) ListIssues(ctx context.Context, req ListIssuesRequest) ([]I
 	if encoded := params.Encode(); encoded != "" {
 		path += "?" + encoded
 	}
-
-	var issues []Issue
-	if err := c.do(ctx, http.MethodGet, path, nil, &issues); err != nil {
-		return nil, err
-	}
-	return issues, nil
+	return path
 }
 
 func (c *HTTPClient) CreateIssue(ctx context.Context, req CreateIssueRequest) (*Issue, error) {
@@ -132,17 +337,24 @@ func (c *HTTPClient) CreateIssue(ctx context.Context, req CreateIssueRequest) (*
 }
 
 func (c *HTTPClient) GetIssue(ctx context.Context, number int) (*Issue, error) {
+	return c.GetIssueByRef(ctx, IssueRef{Number: number})
+}
+
+// GetIssueByRef returns an issue that may be in another repository.
+func (c *HTTPClient) GetIssueByRef(ctx context.Context, ref IssueRef) (*Issue, error) {
 	var issue Issue
-	path := c.repoPath("/issues/%d", number)
+	path := c.refPath(ref, "/issues/%d", ref.Number)
 	if err := c.do(ctx, http.MethodGet, path, nil, &issue); err != nil {
 		return nil, err
 	}
 	return &issue, nil
 }
 
-func (c *HTTPClient) CloseIssue(ctx context.Context, number int, comment string) (*Issue, error) {
-	if comment != "" {
-		if _, err := c.AddComment(ctx, number, comment); err != nil {
+// CloseIssue closes an issue, first posting req.Comment if it is set. An
+// empty req.Reason lets GitHub record the default reason, completed.
+func (c *HTTPClient) CloseIssue(ctx context.Context, number int, req CloseIssueRequest) (*Issue, error) {
+	if req.Comment != "" {
+		if _, err := c.AddComment(ctx, number, req.Comment); err != nil {
 			return nil, fmt.Errorf("adding closing comment: %w", err)
 		}
 	}
@@ -150,6 +362,26 @@ func (c *HTTPClient) CloseIssue(ctx context.Context, number int, comment string)
 	var issue Issue
 	path := c.repoPath("/issues/%d", number)
 	body := map[string]string{"state": "closed"}
+	if req.Reason != "" {
+		body["state_reason"] = req.Reason
+	}
+	if err := c.do(ctx, http.MethodPatch, path, body, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// ReopenIssue reopens a closed issue, first posting comment if it is set.
+func (c *HTTPClient) ReopenIssue(ctx context.Context, number int, comment string) (*Issue, error) {
+	if comment != "" {
+		if _, err := c.AddComment(ctx, number, comment); err != nil {
+			return nil, fmt.Errorf("adding reopening comment: %w", err)
+		}
+	}
+
+	var issue Issue
+	path := c.repoPath("/issues/%d", number)
+	body := map[string]string{"state": "open", "state_reason": StateReasonReopened}
 	if err := c.do(ctx, http.MethodPatch, path, body, &issue); err != nil {
 		return nil, err
 	}
@@ -166,6 +398,35 @@ func (c *HTTPClient) AddComment(ctx context.Context, number int, body string) (*
 	return &comment, nil
 }
 
+// ListComments returns every comment on an issue in chronological order.
+func (c *HTTPClient) ListComments(ctx context.Context, number int) ([]IssueComment, error) {
+	path := c.repoPath("/issues/%d/comments?per_page=%d", number, maxPerPage)
+
+	var comments []IssueComment
+	for comment, err := range paginate(ctx, c, path, func(page []IssueComment) []IssueComment { return page }) {
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+func (c *HTTPClient) UpdateComment(ctx context.Context, id int64, body string) (*IssueComment, error) {
+	var comment IssueComment
+	path := c.repoPath("/issues/comments/%d", id)
+	req := CreateCommentRequest{Body: body}
+	if err := c.do(ctx, http.MethodPatch, path, req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (c *HTTPClient) DeleteComment(ctx context.Context, id int64) error {
+	path := c.repoPath("/issues/comments/%d", id)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
 func (c *HTTPClient) AssignIssue(ctx context.Context, number int, assignees []string) (*Issue, error) {
 	var issue Issue
 	path := c.repoPath("/issues/%d", number)
@@ -187,12 +448,22 @@ func (c *HTTPClient) UpdateIssue(ctx context.Context, number int, req UpdateIssu
 	if req.State != nil {
 		body["state"] = *req.State
 	}
+	if req.StateReason != nil {
+		body["state_reason"] = *req.StateReason
+	}
 	if req.Labels != nil {
 		body["labels"] = req.Labels
 	}
 	if req.Assignees != nil {
 		body["assignees"] = req.Assignees
 	}
+	if req.Milestone != nil {
+		if *req.Milestone == 0 {
+			body["milestone"] = nil
+		} else {
+			body["milestone"] = *req.Milestone
+		}
+	}
 
 	var issue Issue
 	path := c.repoPath("/issues/%d", number)
@@ -203,7 +474,37 @@ func (c *HTTPClient) UpdateIssue(ctx context.Context, number int, req UpdateIssu
 }
 
 func (c *HTTPClient) SearchIssues(ctx context.Context, req SearchIssuesRequest) (*SearchIssuesResponse, error) {
-	qualifiers := []string{fmt.Sprintf("repo:%s/%s", c.owner, c.repo), "is:issue"}
+	var resp SearchIssuesResponse
+	header, err := c.doWithHeader(ctx, http.MethodGet, c.searchIssuesPath(req), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	resp.HasNext = nextPageURL(header) != ""
+	return &resp, nil
+}
+
+// SearchAllIssues returns an iterator over every search result, following
+// pagination links. GitHub caps search results at 1000 items.
+func (c *HTTPClient) SearchAllIssues(ctx context.Context, req SearchIssuesRequest) iter.Seq2[Issue, error] {
+	if req.PerPage <= 0 {
+		req.PerPage = maxPerPage
+	}
+	return paginate(ctx, c, c.searchIssuesPath(req), func(page SearchIssuesResponse) []Issue {
+		return page.Items
+	})
+}
+
+func (c *HTTPClient) searchIssuesPath(req SearchIssuesRequest) string {
+	var qualifiers []string
+	if len(req.Repos) == 0 {
+		qualifiers = append(qualifiers, fmt.Sprintf("repo:%s/%s", c.owner, c.repo))
+	}
+	for _, r := range req.Repos {
+		qualifiers = append(qualifiers, "repo:"+r)
+	}
+	if !req.IncludePullRequests {
+		qualifiers = append(qualifiers, "is:issue")
+	}
 
 	if req.State != "" && req.State != "all" {
 		qualifiers = append(qualifiers, "state:"+req.State)
@@ -216,12 +517,30 @@ func (c *HTTPClient) SearchIssues(ctx context.Context, req SearchIssuesRequest)
 			}
 		}
 	}
+	switch req.Assignee {
+	case "":
+	case "none":
+		qualifiers = append(qualifiers, "no:assignee")
+	default:
+		qualifiers = append(qualifiers, "assignee:"+req.Assignee)
+	}
+	switch req.Milestone {
+	case "":
+	case "none":
+		qualifiers = append(qualifiers, "no:milestone")
+	default:
+		qualifiers = append(qualifiers, fmt.Sprintf("milestone:%q", req.Milestone))
+	}
 	if req.Query != "" {
 		qualifiers = append(qualifiers, req.Query)
 	}
 
 	params := url.Values{}
 	params.Set("q", strings.Join(qualifiers, " "))
+	if req.Sort != "" {
+		params.Set("sort", req.Sort)
+		params.Set("order", "desc")
+	}
 	if req.PerPage > 0 {
 		params.Set("per_page", strconv.Itoa(req.PerPage))
 	}
@@ -229,11 +548,5 @@ func (c *HTTPClient) SearchIssues(ctx context.Context, req SearchIssuesRequest)
 		params.Set("page", strconv.Itoa(req.Page))
 	}
 
-	path := "/search/issues?" + params.Encode()
-
-	var resp SearchIssuesResponse
-	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
-		return nil, err
-	}
-	return &resp, nil
+	return "/search/issues?" + params.Encode()
 }
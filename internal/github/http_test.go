@@ -0,0 +1,218 @@
+package github_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/github/githubtest"
+)
+
+func newServer(t *testing.T, issues int) (*githubtest.Server, *githubtest.Repo) {
+	t.Helper()
+	srv := githubtest.NewServer()
+	t.Cleanup(srv.Close)
+	repo := srv.Repo("acme", "widgets")
+	for range issues {
+		repo.AddIssue(github.Issue{Title: "issue"})
+	}
+	return srv, repo
+}
+
+func TestListAllIssuesFollowsLinks(t *testing.T) {
+	srv, _ := newServer(t, 7)
+	client := srv.Client("acme", "widgets")
+
+	var numbers []int
+	for issue, err := range client.ListAllIssues(t.Context(), github.ListIssuesRequest{PerPage: 3}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		numbers = append(numbers, issue.Number)
+	}
+	if len(numbers) != 7 {
+		t.Errorf("got issues %v, want all 7", numbers)
+	}
+
+	var pages int
+	for _, r := range srv.Requests() {
+		if r.Path == "/repos/acme/widgets/issues" {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Errorf("fetched %d pages, want 3", pages)
+	}
+}
+
+func TestHasNext(t *testing.T) {
+	// Six issues fill exactly two pages of three, so a full last page must
+	// not be taken for there being more.
+	srv, _ := newServer(t, 6)
+	client := srv.Client("acme", "widgets")
+
+	for page, want := range map[int]bool{1: true, 2: false} {
+		resp, err := client.ListIssues(t.Context(), github.ListIssuesRequest{PerPage: 3, Page: page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Issues) != 3 || resp.HasNext != want {
+			t.Errorf("list page %d: %d issues, HasNext %v, want 3 and %v", page, len(resp.Issues), resp.HasNext, want)
+		}
+
+		search, err := client.SearchIssues(t.Context(), github.SearchIssuesRequest{Query: "issue", PerPage: 3, Page: page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(search.Items) != 3 || search.HasNext != want {
+			t.Errorf("search page %d: %d issues, HasNext %v, want 3 and %v", page, len(search.Items), search.HasNext, want)
+		}
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	srv, _ := newServer(t, 1)
+	client := srv.Client("acme", "widgets")
+
+	_, err := client.GetIssue(t.Context(), 42)
+	var notFound *github.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("missing issue: err = %v, want a NotFoundError", err)
+	}
+
+	srv.Fail(githubtest.Failure{Status: http.StatusForbidden, Message: "Resource not accessible by integration", Times: 1})
+	_, err = client.GetIssue(t.Context(), 1)
+	var forbidden *github.ForbiddenError
+	if !errors.As(err, &forbidden) {
+		t.Errorf("forbidden: err = %v, want a ForbiddenError", err)
+	}
+}
+
+func TestSecondaryRateLimitRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		failure githubtest.Failure
+	}{
+		{"retry after", githubtest.SecondaryRateLimit(http.MethodGet, "/repos/*/*/issues/*", 0, 2)},
+		{"no hint", githubtest.Failure{
+			Method:  http.MethodGet,
+			Path:    "/repos/*/*/issues/*",
+			Times:   2,
+			Status:  http.StatusForbidden,
+			Message: "You have exceeded a secondary rate limit.",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := newServer(t, 1)
+			client := srv.Client("acme", "widgets", github.WithMaxWait(10*time.Millisecond))
+			srv.Fail(tt.failure)
+
+			if _, err := client.GetIssue(t.Context(), 1); err != nil {
+				t.Fatalf("err = %v, want the request retried", err)
+			}
+			if got := len(srv.Requests()); got != 3 {
+				t.Errorf("sent %d requests, want 3", got)
+			}
+		})
+	}
+}
+
+func TestPrimaryRateLimit(t *testing.T) {
+	srv, _ := newServer(t, 1)
+	client := srv.Client("acme", "widgets", github.WithMaxWait(10*time.Millisecond))
+	srv.SetRateLimitRemaining(githubtest.ResourceCore, 0)
+
+	_, err := client.GetIssue(t.Context(), 1)
+	var rateLimited *github.RateLimitError
+	if !errors.As(err, &rateLimited) {
+		t.Fatalf("err = %v, want a RateLimitError", err)
+	}
+	if rateLimited.Reset.Before(time.Now()) {
+		t.Errorf("Reset = %v, want it in the future", rateLimited.Reset)
+	}
+	if got := len(srv.Requests()); got != 1 {
+		t.Errorf("sent %d requests, want no retry past maxWait", got)
+	}
+}
+
+func TestServerErrorRetry(t *testing.T) {
+	srv, _ := newServer(t, 1)
+	client := srv.Client("acme", "widgets", github.WithMaxRetries(1))
+
+	srv.Fail(githubtest.ServerError("", "/repos/*/*/issues*", 1))
+	if _, err := client.GetIssue(t.Context(), 1); err != nil {
+		t.Errorf("GET: err = %v, want it retried", err)
+	}
+
+	srv.Fail(githubtest.ServerError("", "/repos/*/*/issues*", 1))
+	if _, err := client.CreateIssue(t.Context(), github.CreateIssueRequest{Title: "new"}); err == nil {
+		t.Error("POST: err = nil, want the 502 returned rather than retried")
+	}
+	if got := len(srv.Repo("acme", "widgets").Issues()); got != 1 {
+		t.Errorf("%d issues, want the POST sent once and failed", got)
+	}
+}
+
+func TestCacheRevalidates(t *testing.T) {
+	srv, repo := newServer(t, 1)
+	dir := t.TempDir()
+	client := srv.Client("acme", "widgets", github.WithCache(github.NewResponseCache(dir, 0)))
+
+	for range 2 {
+		if _, err := client.GetIssue(t.Context(), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requests := srv.Requests()
+	if len(requests) != 2 || requests[1].Header.Get("If-None-Match") == "" {
+		t.Fatalf("second request was not conditional: %+v", requests)
+	}
+
+	repo.AddComment(1, "hubot", "new comment")
+	issue, err := client.GetIssue(t.Context(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Comments != 1 {
+		t.Errorf("Comments = %d, want the changed issue refetched", issue.Comments)
+	}
+}
+
+func TestCacheEvictsOnlyEntries(t *testing.T) {
+	srv, _ := newServer(t, 5)
+	dir := t.TempDir()
+	foreign := []string{filepath.Join(dir, "settings.json"), filepath.Join(dir, "ab", "notes.json")}
+	for _, path := range foreign {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each entry is a few hundred bytes, so a 1 KiB cache holds only some.
+	client := srv.Client("acme", "widgets", github.WithCache(github.NewResponseCache(dir, 1<<10)))
+	for n := 1; n <= 5; n++ {
+		if _, err := client.GetIssue(t.Context(), n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, path := range foreign {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("eviction removed a file that is not an entry: %v", err)
+		}
+	}
+	entries, _ := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	entries = slices.DeleteFunc(entries, func(path string) bool { return slices.Contains(foreign, path) })
+	if len(entries) == 0 || len(entries) == 5 {
+		t.Errorf("%d entries left, want some evicted", len(entries))
+	}
+}
@@ -0,0 +1,56 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListLabels returns every label defined in the repository.
+func (c *HTTPClient) ListLabels(ctx context.Context) ([]Label, error) {
+	path := c.repoPath("/labels?per_page=%d", maxPerPage)
+
+	var labels []Label
+	for label, err := range paginate(ctx, c, path, func(page []Label) []Label { return page }) {
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+func (c *HTTPClient) CreateLabel(ctx context.Context, req LabelRequest) (*Label, error) {
+	var label Label
+	path := c.repoPath("/labels")
+	if err := c.do(ctx, http.MethodPost, path, req, &label); err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+// UpdateLabel changes the label called name. Setting req.Name renames it.
+func (c *HTTPClient) UpdateLabel(ctx context.Context, name string, req LabelRequest) (*Label, error) {
+	body := map[string]string{}
+	if req.Name != "" && req.Name != name {
+		body["new_name"] = req.Name
+	}
+	if req.Color != "" {
+		body["color"] = req.Color
+	}
+	if req.Description != nil {
+		body["description"] = *req.Description
+	}
+
+	var label Label
+	path := c.repoPath("/labels/%s", url.PathEscape(name))
+	if err := c.do(ctx, http.MethodPatch, path, body, &label); err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+func (c *HTTPClient) DeleteLabel(ctx context.Context, name string) error {
+	path := c.repoPath("/labels/%s", url.PathEscape(name))
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
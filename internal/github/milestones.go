@@ -0,0 +1,47 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListMilestones returns the repository's milestones in the given state
+// (open, closed or all), ordered by due date.
+func (c *HTTPClient) ListMilestones(ctx context.Context, state string) ([]Milestone, error) {
+	params := url.Values{}
+	if state != "" {
+		params.Set("state", state)
+	}
+	params.Set("sort", "due_on")
+	params.Set("per_page", strconv.Itoa(maxPerPage))
+	path := c.repoPath("/milestones?%s", params.Encode())
+
+	var milestones []Milestone
+	for m, err := range paginate(ctx, c, path, func(page []Milestone) []Milestone { return page }) {
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, m)
+	}
+	return milestones, nil
+}
+
+func (c *HTTPClient) CreateMilestone(ctx context.Context, req CreateMilestoneRequest) (*Milestone, error) {
+	var milestone Milestone
+	path := c.repoPath("/milestones")
+	if err := c.do(ctx, http.MethodPost, path, req, &milestone); err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (c *HTTPClient) UpdateMilestone(ctx context.Context, number int, req UpdateMilestoneRequest) (*Milestone, error) {
+	var milestone Milestone
+	path := c.repoPath("/milestones/%d", number)
+	if err := c.do(ctx, http.MethodPatch, path, req, &milestone); err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
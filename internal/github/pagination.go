@@ -0,0 +1,48 @@
+package github
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"regexp"
+)
+
+// maxPerPage is the largest page size accepted by the GitHub REST API.
+const maxPerPage = 100
+
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="next"`)
+
+// nextPageURL returns the rel="next" target of a Link header, or an empty
+// string on the last page.
+func nextPageURL(h http.Header) string {
+	for _, link := range h.Values("Link") {
+		if m := nextLinkPattern.FindStringSubmatch(link); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// paginate walks every page starting at path by following Link headers,
+// yielding the items extracted from each decoded page. Iteration stops at
+// the first error, which is yielded once.
+func paginate[P, T any](ctx context.Context, c *HTTPClient, path string, items func(P) []T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		next := path
+		for next != "" {
+			var page P
+			header, err := c.doWithHeader(ctx, http.MethodGet, next, nil, &page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items(page) {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			next = nextPageURL(header)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Project field data types reported by the Projects v2 API.
+const (
+	ProjectFieldText         = "TEXT"
+	ProjectFieldNumber       = "NUMBER"
+	ProjectFieldDate         = "DATE"
+	ProjectFieldSingleSelect = "SINGLE_SELECT"
+	ProjectFieldIteration    = "ITERATION"
+)
+
+// Project is a GitHub Projects v2 board together with its fields.
+type Project struct {
+	ID     string
+	Title  string
+	Number int
+	URL    string
+	Fields []ProjectField
+}
+
+// ProjectField is a field of a project. Options is set for single-select
+// fields and Iterations for iteration fields.
+type ProjectField struct {
+	ID         string
+	Name       string
+	DataType   string
+	Options    []ProjectFieldOption
+	Iterations []ProjectIteration
+}
+
+type ProjectFieldOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ProjectIteration is one iteration of an iteration field. StartDate is in
+// YYYY-MM-DD form and Duration is in days.
+type ProjectIteration struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	StartDate string `json:"startDate"`
+	Duration  int    `json:"duration"`
+}
+
+// ProjectFieldValue is a value to store in a project field. Set exactly one
+// member, matching the field's data type.
+type ProjectFieldValue struct {
+	Text                 string
+	Number               *float64
+	Date                 string
+	SingleSelectOptionID string
+	IterationID          string
+}
+
+// ProjectItem is an issue's entry in a project and the values of its fields.
+type ProjectItem struct {
+	ID      string
+	Project Project
+	Values  []ProjectItemValue
+}
+
+// ProjectItemValue is the value of a single field, formatted for display.
+type ProjectItemValue struct {
+	Field string
+	Value string
+}
+
+const projectFragment = `
+fragment ProjectParts on ProjectV2 {
+  id
+  title
+  number
+  url
+  fields(first: 100) {
+    nodes {
+      ... on ProjectV2FieldCommon { id name dataType }
+      ... on ProjectV2SingleSelectField { options { id name } }
+      ... on ProjectV2IterationField {
+        configuration {
+          iterations { id title startDate duration }
+        }
+      }
+    }
+  }
+}`
+
+type projectNode struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	Fields struct {
+		Nodes []struct {
+			ID            string               `json:"id"`
+			Name          string               `json:"name"`
+			DataType      string               `json:"dataType"`
+			Options       []ProjectFieldOption `json:"options"`
+			Configuration *struct {
+				Iterations []ProjectIteration `json:"iterations"`
+			} `json:"configuration"`
+		} `json:"nodes"`
+	} `json:"fields"`
+}
+
+func (n *projectNode) project() *Project {
+	p := &Project{ID: n.ID, Title: n.Title, Number: n.Number, URL: n.URL}
+	for _, f := range n.Fields.Nodes {
+		if f.ID == "" {
+			continue
+		}
+		field := ProjectField{ID: f.ID, Name: f.Name, DataType: f.DataType, Options: f.Options}
+		if f.Configuration != nil {
+			field.Iterations = f.Configuration.Iterations
+		}
+		p.Fields = append(p.Fields, field)
+	}
+	return p
+}
+
+// GetProject looks up a project by the login of the user or organization
+// that owns it and its number, as shown in the project URL.
+func (c *HTTPClient) GetProject(ctx context.Context, owner string, number int) (*Project, error) {
+	query := `query($owner: String!, $number: Int!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner { projectV2(number: $number) { ...ProjectParts } }
+  }
+}` + projectFragment
+
+	var data struct {
+		RepositoryOwner *struct {
+			ProjectV2 *projectNode `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	vars := map[string]any{"owner": owner, "number": number}
+	if err := c.GraphQL(ctx, query, vars, &data); err != nil {
+		return nil, err
+	}
+	if data.RepositoryOwner == nil || data.RepositoryOwner.ProjectV2 == nil {
+		return nil, fmt.Errorf("project %s/%d not found", owner, number)
+	}
+	return data.RepositoryOwner.ProjectV2.project(), nil
+}
+
+// GetProjectByID looks up a project by its node ID.
+func (c *HTTPClient) GetProjectByID(ctx context.Context, id string) (*Project, error) {
+	query := `query($id: ID!) {
+  node(id: $id) { ... on ProjectV2 { ...ProjectParts } }
+}` + projectFragment
+
+	var data struct {
+		Node *projectNode `json:"node"`
+	}
+	if err := c.GraphQL(ctx, query, map[string]any{"id": id}, &data); err != nil {
+		return nil, err
+	}
+	if data.Node == nil || data.Node.ID == "" {
+		return nil, fmt.Errorf("project %s not found", id)
+	}
+	return data.Node.project(), nil
+}
+
+// AddProjectItem adds an issue, identified by its node ID, to a project and
+// returns the item ID. Adding an issue that is already in the project
+// returns its existing item.
+func (c *HTTPClient) AddProjectItem(ctx context.Context, projectID, contentID string) (string, error) {
+	query := `mutation($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) { item { id } }
+}`
+
+	var data struct {
+		AddProjectV2ItemByID struct {
+			Item struct {
+				ID string `json:"id"`
+			} `json:"item"`
+		} `json:"addProjectV2ItemById"`
+	}
+	vars := map[string]any{"project": projectID, "content": contentID}
+	if err := c.GraphQL(ctx, query, vars, &data); err != nil {
+		return "", err
+	}
+	return data.AddProjectV2ItemByID.Item.ID, nil
+}
+
+// UpdateProjectItemField sets a field on a project item.
+func (c *HTTPClient) UpdateProjectItemField(ctx context.Context, projectID, itemID, fieldID string, value ProjectFieldValue) error {
+	query := `mutation($project: ID!, $item: ID!, $field: ID!, $value: ProjectV2FieldValue!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: $value}) {
+    projectV2Item { id }
+  }
+}`
+
+	vars := map[string]any{
+		"project": projectID,
+		"item":    itemID,
+		"field":   fieldID,
+		"value":   value.input(),
+	}
+	return c.GraphQL(ctx, query, vars, nil)
+}
+
+func (v ProjectFieldValue) input() map[string]any {
+	switch {
+	case v.SingleSelectOptionID != "":
+		return map[string]any{"singleSelectOptionId": v.SingleSelectOptionID}
+	case v.IterationID != "":
+		return map[string]any{"iterationId": v.IterationID}
+	case v.Number != nil:
+		return map[string]any{"number": *v.Number}
+	case v.Date != "":
+		return map[string]any{"date": v.Date}
+	}
+	return map[string]any{"text": v.Text}
+}
+
+// ListProjectItems returns the projects an issue belongs to, with the
+// values of its fields in each.
+func (c *HTTPClient) ListProjectItems(ctx context.Context, number int) ([]ProjectItem, error) {
+	query := `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      projectItems(first: 20) {
+        nodes {
+          id
+          project { id title number url }
+          fieldValues(first: 50) {
+            nodes {
+              ... on ProjectV2ItemFieldSingleSelectValue { name field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldIterationValue { title field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldNumberValue { number field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldDateValue { date field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldTextValue { text field { ... on ProjectV2FieldCommon { name } } }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+	var data struct {
+		Repository struct {
+			Issue *struct {
+				ProjectItems struct {
+					Nodes []struct {
+						ID      string `json:"id"`
+						Project struct {
+							ID     string `json:"id"`
+							Title  string `json:"title"`
+							Number int    `json:"number"`
+							URL    string `json:"url"`
+						} `json:"project"`
+						FieldValues struct {
+							Nodes []struct {
+								Name   *string  `json:"name"`
+								Title  *string  `json:"title"`
+								Number *float64 `json:"number"`
+								Date   *string  `json:"date"`
+								Text   *string  `json:"text"`
+								Field  struct {
+									Name string `json:"name"`
+								} `json:"field"`
+							} `json:"nodes"`
+						} `json:"fieldValues"`
+					} `json:"nodes"`
+				} `json:"projectItems"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	vars := map[string]any{"owner": c.owner, "repo": c.repo, "number": number}
+	if err := c.GraphQL(ctx, query, vars, &data); err != nil {
+		return nil, err
+	}
+	if data.Repository.Issue == nil {
+		return nil, fmt.Errorf("issue #%d not found", number)
+	}
+
+	var items []ProjectItem
+	for _, n := range data.Repository.Issue.ProjectItems.Nodes {
+		item := ProjectItem{
+			ID: n.ID,
+			Project: Project{
+				ID:     n.Project.ID,
+				Title:  n.Project.Title,
+				Number: n.Project.Number,
+				URL:    n.Project.URL,
+			},
+		}
+		for _, v := range n.FieldValues.Nodes {
+			if v.Field.Name == "" {
+				continue
+			}
+			var value string
+			switch {
+			case v.Name != nil:
+				value = *v.Name
+			case v.Title != nil:
+				value = *v.Title
+			case v.Number != nil:
+				value = strconv.FormatFloat(*v.Number, 'f', -1, 64)
+			case v.Date != nil:
+				value = *v.Date
+			case v.Text != nil:
+				value = *v.Text
+			default:
+				continue
+			}
+			item.Values = append(item.Values, ProjectItemValue{Field: v.Field.Name, Value: value})
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pull request states reported by the GraphQL API.
+const (
+	PullRequestOpen   = "OPEN"
+	PullRequestClosed = "CLOSED"
+	PullRequestMerged = "MERGED"
+)
+
+// LinkedPullRequest is a pull request that references an issue.
+type LinkedPullRequest struct {
+	Number int
+	Title  string
+	// State is one of PullRequestOpen, PullRequestClosed or PullRequestMerged.
+	State string
+	URL   string
+	// Repository is the owner/name of the repository the pull request is in.
+	Repository string
+	// Closes is set when merging the pull request closes the issue.
+	Closes bool
+}
+
+const linkedPullRequestFields = `number title state url repository { nameWithOwner }`
+
+// ListLinkedPullRequests returns the pull requests that close or mention an
+// issue. Pull requests that close the issue come first.
+func (c *HTTPClient) ListLinkedPullRequests(ctx context.Context, number int) ([]LinkedPullRequest, error) {
+	query := `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      closedByPullRequestsReferences(first: 25, includeClosedPrs: true) {
+        nodes { ` + linkedPullRequestFields + ` }
+      }
+      timelineItems(first: 100, itemTypes: [CROSS_REFERENCED_EVENT]) {
+        nodes {
+          ... on CrossReferencedEvent {
+            source { ... on PullRequest { ` + linkedPullRequestFields + ` } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+	type pullRequestNode struct {
+		Number     int    `json:"number"`
+		Title      string `json:"title"`
+		State      string `json:"state"`
+		URL        string `json:"url"`
+		Repository struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	}
+	var data struct {
+		Repository struct {
+			Issue *struct {
+				ClosedBy struct {
+					Nodes []pullRequestNode `json:"nodes"`
+				} `json:"closedByPullRequestsReferences"`
+				TimelineItems struct {
+					Nodes []struct {
+						Source *pullRequestNode `json:"source"`
+					} `json:"nodes"`
+				} `json:"timelineItems"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	vars := map[string]any{"owner": c.owner, "repo": c.repo, "number": number}
+	if err := c.GraphQL(ctx, query, vars, &data); err != nil {
+		return nil, err
+	}
+	if data.Repository.Issue == nil {
+		return nil, fmt.Errorf("issue #%d not found", number)
+	}
+
+	var prs []LinkedPullRequest
+	seen := make(map[string]bool)
+	add := func(n pullRequestNode, closes bool) {
+		// Sources that are issues rather than pull requests decode empty.
+		if n.Number == 0 {
+			return
+		}
+		key := fmt.Sprintf("%s#%d", n.Repository.NameWithOwner, n.Number)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		prs = append(prs, LinkedPullRequest{
+			Number:     n.Number,
+			Title:      n.Title,
+			State:      n.State,
+			URL:        n.URL,
+			Repository: n.Repository.NameWithOwner,
+			Closes:     closes,
+		})
+	}
+
+	for _, n := range data.Repository.Issue.ClosedBy.Nodes {
+		add(n, true)
+	}
+	for _, item := range data.Repository.Issue.TimelineItems.Nodes {
+		if item.Source != nil {
+			add(*item.Source, false)
+		}
+	}
+	return prs, nil
+}
@@ -0,0 +1,127 @@
+package github
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit describes the most recently observed rate limit state for the
+// GitHub API.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Used      int
+	Reset     time.Time
+	Resource  string
+}
+
+// Known reports whether rate limit headers have been observed yet.
+func (r RateLimit) Known() bool {
+	return r.Limit > 0
+}
+
+// Low reports whether less than a tenth of the rate limit remains.
+func (r RateLimit) Low() bool {
+	return r.Known() && r.Remaining*10 < r.Limit
+}
+
+func parseRateLimit(h http.Header) (RateLimit, bool) {
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+
+	rl := RateLimit{
+		Limit:    limit,
+		Resource: h.Get("X-RateLimit-Resource"),
+	}
+	rl.Remaining, _ = strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	rl.Used, _ = strconv.Atoi(h.Get("X-RateLimit-Used"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+	return rl, true
+}
+
+// isRateLimited reports whether the response was rejected by either the
+// primary or the secondary rate limit.
+func isRateLimited(resp *http.Response, body []byte) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		if resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return true
+		}
+		return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+	}
+	return false
+}
+
+// rateLimitDelay returns how long to wait before retrying a rate limited
+// request. Retry-After takes precedence, then the primary limit reset time,
+// falling back to exponential backoff for secondary limits without hints.
+// Without a hint there is no time GitHub asked for, so the backoff is capped
+// at maxWait rather than giving up on the retry. A delay over maxWait is
+// returned as is for the caller to refuse.
+func rateLimitDelay(h http.Header, attempt int, now time.Time, maxWait time.Duration) time.Duration {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs >= 0 {
+		return addJitter(time.Duration(secs)*time.Second, maxWait)
+	}
+
+	if h.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			wait := time.Unix(reset, 0).Sub(now)
+			if wait < 0 {
+				wait = 0
+			}
+			return addJitter(wait, maxWait)
+		}
+	}
+
+	return min(backoff(secondaryRateLimitBase, attempt), maxWait)
+}
+
+// addJitter spreads out retries of a wait GitHub asked for. Whether the
+// wait fits in maxWait is decided before the jitter is added, which is
+// then kept within maxWait: a Retry-After equal to maxWait is still waited
+// for.
+func addJitter(wait, maxWait time.Duration) time.Duration {
+	if wait > maxWait {
+		return wait
+	}
+	return min(wait+jitter(time.Second), maxWait)
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// backoff returns an exponentially growing delay with up to 50% jitter.
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base << attempt
+	return d + jitter(d/2)
+}
+
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
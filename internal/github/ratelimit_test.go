@@ -0,0 +1,36 @@
+package github
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimitDelay(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		header  http.Header
+		maxWait time.Duration
+		min     time.Duration
+		max     time.Duration
+	}{
+		{"retry after within max wait", http.Header{"Retry-After": {"60"}}, time.Minute, time.Minute, time.Minute},
+		{"retry after jittered", http.Header{"Retry-After": {"10"}}, time.Minute, 10 * time.Second, 11 * time.Second},
+		{"retry after over max wait", http.Header{"Retry-After": {"61"}}, time.Minute, 61 * time.Second, 61 * time.Second},
+		{"reset within max wait", http.Header{
+			"X-Ratelimit-Remaining": {"0"},
+			"X-Ratelimit-Reset":     {strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)},
+		}, time.Minute, 29 * time.Second, 31 * time.Second},
+		{"no hint", http.Header{}, 5 * time.Second, 5 * time.Second, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rateLimitDelay(tt.header, 0, now, tt.maxWait)
+			if got < tt.min || got > tt.max {
+				t.Errorf("delay = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+		})
+	}
+}
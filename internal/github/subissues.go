@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// AddSubIssue attaches child to parent using the sub-issues API. Either
+// issue may be in another repository. If child already has a different
+// parent it is moved.
+func (c *HTTPClient) AddSubIssue(ctx context.Context, parent, child IssueRef) (*Issue, error) {
+	childIssue, err := c.GetIssueByRef(ctx, child)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue Issue
+	path := c.refPath(parent, "/issues/%d/sub_issues", parent.Number)
+	body := subIssueRequest{SubIssueID: childIssue.ID, ReplaceParent: true}
+	if err := c.do(ctx, http.MethodPost, path, body, &issue); err != nil {
+		return nil, c.changeErr(ctx, err, parent, subIssuesAPI,
+			fmt.Sprintf("%s cannot be added as a sub-issue of %s", child, parent))
+	}
+	return &issue, nil
+}
+
+// RemoveSubIssue detaches child from parent.
+func (c *HTTPClient) RemoveSubIssue(ctx context.Context, parent, child IssueRef) (*Issue, error) {
+	childIssue, err := c.GetIssueByRef(ctx, child)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue Issue
+	path := c.refPath(parent, "/issues/%d/sub_issue", parent.Number)
+	body := subIssueRequest{SubIssueID: childIssue.ID}
+	if err := c.do(ctx, http.MethodDelete, path, body, &issue); err != nil {
+		return nil, c.changeErr(ctx, err, parent, subIssuesAPI,
+			fmt.Sprintf("%s is not a sub-issue of %s", child, parent))
+	}
+	return &issue, nil
+}
+
+// ReprioritizeSubIssue moves child within parent's sub-issue list.
+func (c *HTTPClient) ReprioritizeSubIssue(ctx context.Context, parent int, child IssueRef, pos SubIssuePosition) (*Issue, error) {
+	if (pos.After.Number == 0) == (pos.Before.Number == 0) {
+		return nil, fmt.Errorf("exactly one of after or before must be set")
+	}
+
+	childIssue, err := c.GetIssueByRef(ctx, child)
+	if err != nil {
+		return nil, err
+	}
+
+	body := reprioritizeSubIssueRequest{SubIssueID: childIssue.ID}
+	sibling := pos.After
+	if pos.Before.Number != 0 {
+		sibling = pos.Before
+	}
+	siblingIssue, err := c.GetIssueByRef(ctx, sibling)
+	if err != nil {
+		return nil, err
+	}
+	if pos.After.Number != 0 {
+		body.AfterID = siblingIssue.ID
+	} else {
+		body.BeforeID = siblingIssue.ID
+	}
+
+	var issue Issue
+	path := c.repoPath("/issues/%d/sub_issues/priority", parent)
+	if err := c.do(ctx, http.MethodPatch, path, body, &issue); err != nil {
+		return nil, c.changeErr(ctx, err, IssueRef{Number: parent}, subIssuesAPI,
+			fmt.Sprintf("%s and %s are not both sub-issues of #%d", child, sibling, parent))
+	}
+	return &issue, nil
+}
+
+// ListSubIssues returns every sub-issue of parent in priority order.
+func (c *HTTPClient) ListSubIssues(ctx context.Context, parent int) ([]Issue, error) {
+	path := c.repoPath("/issues/%d/sub_issues?per_page=%d", parent, maxPerPage)
+
+	var issues []Issue
+	for issue, err := range paginate(ctx, c, path, func(page []Issue) []Issue { return page }) {
+		if err != nil {
+			return nil, c.unsupportedErr(ctx, err, IssueRef{Number: parent}, subIssuesAPI)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// GetParentIssue returns the parent of a sub-issue. Issues without a parent
+// yield a NotFoundError.
+func (c *HTTPClient) GetParentIssue(ctx context.Context, number int) (*Issue, error) {
+	var issue Issue
+	path := c.repoPath("/issues/%d/parent", number)
+	if err := c.do(ctx, http.MethodGet, path, nil, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
@@ -0,0 +1,17 @@
+package github
+
+import "context"
+
+// ListTimeline returns every event in an issue's timeline, oldest first.
+func (c *HTTPClient) ListTimeline(ctx context.Context, number int) ([]TimelineEvent, error) {
+	path := c.repoPath("/issues/%d/timeline?per_page=%d", number, maxPerPage)
+
+	var events []TimelineEvent
+	for event, err := range paginate(ctx, c, path, func(page []TimelineEvent) []TimelineEvent { return page }) {
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
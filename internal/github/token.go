@@ -0,0 +1,148 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Token types, as recognized from the token's prefix.
+const (
+	TokenClassic      = "classic"
+	TokenFineGrained  = "fine-grained"
+	TokenOAuth        = "OAuth"
+	TokenApp          = "GitHub App installation"
+	TokenAppUser      = "GitHub App user"
+	TokenUnrecognized = "unrecognized"
+)
+
+// TokenType returns the kind of a GitHub token from its prefix.
+func TokenType(token string) string {
+	switch {
+	case strings.HasPrefix(token, "ghp_"):
+		return TokenClassic
+	case strings.HasPrefix(token, "github_pat_"):
+		return TokenFineGrained
+	case strings.HasPrefix(token, "gho_"):
+		return TokenOAuth
+	case strings.HasPrefix(token, "ghs_"):
+		return TokenApp
+	case strings.HasPrefix(token, "ghu_"):
+		return TokenAppUser
+	}
+	// Classic tokens created before 2021 are 40 hex characters.
+	if len(token) == 40 && strings.Trim(strings.ToLower(token), "0123456789abcdef") == "" {
+		return TokenClassic
+	}
+	return TokenUnrecognized
+}
+
+// AuthClient describes the credentials a client authenticates with. It is
+// kept apart from Client, since only 'grit auth' needs it.
+type AuthClient interface {
+	GetTokenInfo(ctx context.Context) (*TokenInfo, error)
+	GetRepository(ctx context.Context) (*RepositoryInfo, error)
+}
+
+// TokenInfo describes the authenticated user and the token used.
+type TokenInfo struct {
+	Login string
+	// Scopes lists the OAuth scopes of a classic or OAuth token. It is nil
+	// for tokens without scopes, such as fine-grained tokens.
+	Scopes []string
+	// Expiry is when the token expires, or zero when it does not or the
+	// API did not say.
+	Expiry time.Time
+}
+
+// HasScope reports whether the token was granted scope, or a scope that
+// includes it.
+func (t *TokenInfo) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope || impliedScopes[s][scope] {
+			return true
+		}
+	}
+	return false
+}
+
+// impliedScopes maps a scope to the narrower scopes it grants.
+var impliedScopes = map[string]map[string]bool{
+	"repo":      {"public_repo": true, "repo:status": true, "repo_deployment": true, "repo:invite": true},
+	"project":   {"read:project": true},
+	"admin:org": {"write:org": true, "read:org": true},
+	"write:org": {"read:org": true},
+}
+
+// GetTokenInfo returns the authenticated user with the scopes and expiry
+// reported for the token.
+func (c *HTTPClient) GetTokenInfo(ctx context.Context) (*TokenInfo, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	header, err := c.doWithHeader(ctx, http.MethodGet, "/user", nil, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &TokenInfo{Login: user.Login}
+	if _, ok := header[http.CanonicalHeaderKey("X-OAuth-Scopes")]; ok {
+		info.Scopes = []string{}
+		for _, s := range strings.Split(header.Get("X-OAuth-Scopes"), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				info.Scopes = append(info.Scopes, s)
+			}
+		}
+	}
+	info.Expiry = parseTokenExpiry(header.Get("GitHub-Authentication-Token-Expiration"))
+	return info, nil
+}
+
+// parseTokenExpiry parses the token expiration header, which GitHub sends
+// as "2006-01-02 15:04:05 UTC" or with a numeric zone.
+func parseTokenExpiry(v string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// RepositoryInfo describes the client's repository and the authenticated
+// user's role in it.
+type RepositoryInfo struct {
+	FullName  string `json:"full_name"`
+	Private   bool   `json:"private"`
+	HasIssues bool   `json:"has_issues"`
+	Archived  bool   `json:"archived"`
+	// Permissions is the user's role on the repository. It is nil for
+	// GitHub App installation tokens.
+	Permissions *RepoPermissions `json:"permissions,omitempty"`
+}
+
+// RepoPermissions is a user's role on a repository.
+type RepoPermissions struct {
+	Admin    bool `json:"admin"`
+	Maintain bool `json:"maintain"`
+	Push     bool `json:"push"`
+	Triage   bool `json:"triage"`
+	Pull     bool `json:"pull"`
+}
+
+// CanTriage reports whether the role may manage issues: edit and close
+// other people's issues and set their labels and assignees. Opening and
+// commenting on issues only needs read access.
+func (p *RepoPermissions) CanTriage() bool {
+	return p.Admin || p.Maintain || p.Push || p.Triage
+}
+
+// GetRepository returns the client's repository.
+func (c *HTTPClient) GetRepository(ctx context.Context) (*RepositoryInfo, error) {
+	var repo RepositoryInfo
+	if err := c.do(ctx, http.MethodGet, c.repoPath(""), nil, &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
@@ -1,9 +1,25 @@
 package github
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 type Label struct {
-	Name string `json:"name"`
+	ID          int64  `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// LabelRequest creates or updates a label. Color is a hex value without
+// the leading '#'. A nil Description leaves the description unchanged on
+// update.
+type LabelRequest struct {
+	Name        string  `json:"name"`
+	Color       string  `json:"color,omitempty"`
+	Description *string `json:"description,omitempty"`
 }
 
 type User struct {
@@ -11,15 +27,110 @@ type User struct {
 }
 
 type Issue struct {
-	Number    int       `json:"number"`
-	Title     string    `json:"title"`
-	Body      string    `json:"body"`
-	State     string    `json:"state"`
-	HTMLURL   string    `json:"html_url"`
-	Labels    []Label   `json:"labels"`
-	Assignees []User    `json:"assignees"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int64      `json:"id"`
+	NodeID    string     `json:"node_id"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"`
+	HTMLURL   string     `json:"html_url"`
+	Labels    []Label    `json:"labels"`
+	Assignees []User     `json:"assignees"`
+	Milestone *Milestone `json:"milestone"`
+	// Comments is the number of comments on the issue.
+	Comments int `json:"comments"`
+	// PullRequest is set when the issue is a pull request.
+	PullRequest *PullRequestRef `json:"pull_request,omitempty"`
+	// Repository is only populated in responses that can span repositories,
+	// such as timeline cross-references.
+	Repository    *Repository `json:"repository,omitempty"`
+	RepositoryURL string      `json:"repository_url,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// IsPullRequest reports whether the issue is a pull request. The issues
+// endpoints return pull requests alongside issues.
+func (i Issue) IsPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+// RepoFullName returns the owner/name of the issue's repository, or an
+// empty string when the response did not include it.
+func (i Issue) RepoFullName() string {
+	if i.Repository != nil && i.Repository.FullName != "" {
+		return i.Repository.FullName
+	}
+	parts := strings.Split(strings.TrimSuffix(i.RepositoryURL, "/"), "/")
+	if len(parts) < 2 || i.RepositoryURL == "" {
+		return ""
+	}
+	return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+}
+
+// PullRequestRef marks an issue as a pull request.
+type PullRequestRef struct {
+	HTMLURL  string     `json:"html_url"`
+	MergedAt *time.Time `json:"merged_at"`
+}
+
+type Repository struct {
+	FullName string `json:"full_name"`
+}
+
+// TimelineEvent is a single entry in an issue's timeline. Which fields are
+// set depends on Event.
+type TimelineEvent struct {
+	ID          int64           `json:"id"`
+	Event       string          `json:"event"`
+	Actor       *User           `json:"actor"`
+	User        *User           `json:"user"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Label       *Label          `json:"label"`
+	Assignee    *User           `json:"assignee"`
+	Milestone   *Milestone      `json:"milestone"`
+	Rename      *Rename         `json:"rename"`
+	StateReason string          `json:"state_reason"`
+	CommitID    string          `json:"commit_id"`
+	Source      *TimelineSource `json:"source"`
+	Body        string          `json:"body"`
+}
+
+type Rename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TimelineSource identifies the issue or pull request behind a
+// cross-referenced event.
+type TimelineSource struct {
+	Type  string `json:"type"`
+	Issue *Issue `json:"issue"`
+}
+
+type Milestone struct {
+	Number       int        `json:"number"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	State        string     `json:"state"`
+	HTMLURL      string     `json:"html_url"`
+	OpenIssues   int        `json:"open_issues"`
+	ClosedIssues int        `json:"closed_issues"`
+	DueOn        *time.Time `json:"due_on"`
+	ClosedAt     *time.Time `json:"closed_at"`
+}
+
+type CreateMilestoneRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	DueOn       *time.Time `json:"due_on,omitempty"`
+}
+
+type UpdateMilestoneRequest struct {
+	Title       *string    `json:"title,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	State       *string    `json:"state,omitempty"`
+	DueOn       *time.Time `json:"due_on,omitempty"`
 }
 
 type CreateIssueRequest struct {
@@ -27,27 +138,56 @@ type CreateIssueRequest struct {
 	Body      string   `json:"body,omitempty"`
 	Labels    []string `json:"labels,omitempty"`
 	Assignees []string `json:"assignees,omitempty"`
+	Milestone int      `json:"milestone,omitempty"`
 }
 
 type ListIssuesRequest struct {
 	State    string
 	Assignee string
 	Labels   string
-	PerPage  int
-	Page     int
+	// Milestone is a milestone number, "*" for any milestone or "none".
+	Milestone string
+	// Since limits the results to issues updated at or after it.
+	Since   time.Time
+	PerPage int
+	Page    int
+	// IncludePullRequests keeps pull requests in the results. The issues
+	// endpoint cannot filter them, so they are dropped by the service layer.
+	IncludePullRequests bool
 }
 
 type SearchIssuesRequest struct {
-	Query   string
-	State   string
-	Labels  string
-	PerPage int
-	Page    int
+	Query  string
+	State  string
+	Labels string
+	// Milestone is a milestone title or "none".
+	Milestone string
+	PerPage   int
+	Page      int
+	// IncludePullRequests searches pull requests as well as issues.
+	IncludePullRequests bool
+	// Repos lists the repositories to search, as owner/name. The client's
+	// own repository is searched when it is empty.
+	Repos []string
+	// Assignee is a login or "none".
+	Assignee string
+	// Sort orders results, newest first, by "created", "updated" or
+	// "comments" instead of by relevance.
+	Sort string
+}
+
+// ListIssuesResponse is one page of issues.
+type ListIssuesResponse struct {
+	Issues []Issue
+	// HasNext reports whether the Link header points to a next page.
+	HasNext bool
 }
 
 type SearchIssuesResponse struct {
 	TotalCount int     `json:"total_count"`
 	Items      []Issue `json:"items"`
+	// HasNext reports whether the Link header points to a next page.
+	HasNext bool `json:"-"`
 }
 
 type CreateCommentRequest struct {
@@ -55,25 +195,78 @@ type CreateCommentRequest struct {
 }
 
 type IssueComment struct {
-	ID        int       `json:"id"`
+	ID        int64     `json:"id"`
 	Body      string    `json:"body"`
+	User      User      `json:"user"`
 	HTMLURL   string    `json:"html_url"`
 	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type UpdateIssueRequest struct {
-	Title     *string  `json:"title,omitempty"`
-	Body      *string  `json:"body,omitempty"`
-	State     *string  `json:"state,omitempty"`
-	Labels    []string `json:"labels,omitempty"`
-	Assignees []string `json:"assignees,omitempty"`
+	Title       *string  `json:"title,omitempty"`
+	Body        *string  `json:"body,omitempty"`
+	State       *string  `json:"state,omitempty"`
+	StateReason *string  `json:"state_reason,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Assignees   []string `json:"assignees,omitempty"`
+	// Milestone sets the milestone by number. A pointer to zero removes it.
+	Milestone *int `json:"milestone,omitempty"`
+}
+
+// Reasons GitHub records when an issue changes state.
+const (
+	StateReasonCompleted  = "completed"
+	StateReasonNotPlanned = "not_planned"
+	StateReasonDuplicate  = "duplicate"
+	StateReasonReopened   = "reopened"
+)
+
+type CloseIssueRequest struct {
+	Comment string
+	// Reason is one of the StateReason constants other than reopened.
+	Reason string
+}
+
+type subIssueRequest struct {
+	SubIssueID    int64 `json:"sub_issue_id"`
+	ReplaceParent bool  `json:"replace_parent,omitempty"`
+}
+
+type reprioritizeSubIssueRequest struct {
+	SubIssueID int64 `json:"sub_issue_id"`
+	AfterID    int64 `json:"after_id,omitempty"`
+	BeforeID   int64 `json:"before_id,omitempty"`
+}
+
+type dependencyRequest struct {
+	IssueID int64 `json:"issue_id"`
+}
+
+// IssueRef refers to an issue that may be in another repository than the
+// client's, as sub-issues and dependencies can be.
+type IssueRef struct {
+	// Repo is the owner/name of the issue's repository, or empty for the
+	// client's own.
+	Repo   string
+	Number int
+}
+
+// String formats the reference as GitHub writes it: #12 for an issue in
+// the client's repository and owner/name#12 for one in another.
+func (r IssueRef) String() string {
+	return fmt.Sprintf("%s#%d", r.Repo, r.Number)
+}
+
+// SubIssuePosition places a sub-issue relative to one of its siblings.
+// Exactly one of After or Before should be set to a sibling issue.
+type SubIssuePosition struct {
+	After  IssueRef
+	Before IssueRef
 }
 
 type ErrorResponse struct {
-	Message string `json:"message"`
-	Errors  []struct {
-		Resource string `json:"resource"`
-		Field    string `json:"field"`
-		Code     string `json:"code"`
-	} `json:"errors,omitempty"`
+	Message          string       `json:"message"`
+	DocumentationURL string       `json:"documentation_url,omitempty"`
+	Errors           []FieldError `json:"errors,omitempty"`
 }
@@ -59,7 +59,9 @@ func (c *AnthropicClient) GenerateIssue(ctx context.Context, req IssueRequest) (
 	}
 
 	var bodyInstruction string
-	if req.DescriptionHint != "" {
+	if req.Template != "" {
+		bodyInstruction = templateInstruction(req)
+	} else if req.DescriptionHint != "" {
 		bodyInstruction = fmt.Sprintf(`Expand and structure the following into a well-formatted GitHub issue body:
 "%s"
 
@@ -68,7 +68,9 @@ func (c *OllamaClient) GenerateIssue(ctx context.Context, req IssueRequest) (*Ge
 	}
 
 	var bodyInstruction string
-	if req.DescriptionHint != "" {
+	if req.Template != "" {
+		bodyInstruction = templateInstruction(req)
+	} else if req.DescriptionHint != "" {
 		bodyInstruction = fmt.Sprintf(`Expand and structure the following into a well-formatted GitHub issue body:
 "%s"
 
@@ -1,6 +1,9 @@
 package llm
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // IssueRequest contains the parameters for generating an issue.
 type IssueRequest struct {
@@ -10,9 +13,12 @@ type IssueRequest struct {
 	RepoContext     string
 	IssuePrefix     string
 	AllowedLabels   []string
-	GenerateTitle   bool
-	GenerateBody    bool
-	SuggestLabels   bool
+	// Template is the body skeleton of the repository's issue template. When
+	// set, the body follows it instead of the default layout.
+	Template      string
+	GenerateTitle bool
+	GenerateBody  bool
+	SuggestLabels bool
 }
 
 // GeneratedIssue contains the LLM-generated issue content.
@@ -23,6 +29,25 @@ type GeneratedIssue struct {
 	Reasoning string
 }
 
+// templateInstruction asks for a body that follows req.Template.
+func templateInstruction(req IssueRequest) string {
+	source := "the user's request"
+	if req.DescriptionHint != "" {
+		source = fmt.Sprintf("the following:\n\"%s\"", req.DescriptionHint)
+	}
+	return fmt.Sprintf(`Write the GitHub issue body from %s
+
+Follow this issue template. Keep its headings and their order, fill in each
+section, keep any content already provided for a section, and write
+"_No response_" under sections the input does not cover. Remove HTML comments
+and placeholder text.
+
+Template:
+%s
+
+Use markdown formatting.`, source, req.Template)
+}
+
 // filterLabels returns only labels that exist in the allowed list.
 func filterLabels(suggested, allowed []string) []string {
 	allowedSet := make(map[string]bool, len(allowed))
@@ -0,0 +1,224 @@
+package offline
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// Kinds of change queued in the outbox.
+const (
+	KindCreate  = "create"
+	KindEdit    = "edit"
+	KindAssign  = "assign"
+	KindClose   = "close"
+	KindReopen  = "reopen"
+	KindComment = "comment"
+)
+
+// Change is a change made while offline, waiting in the outbox for Sync to
+// send it to GitHub.
+type Change struct {
+	ID   int    `json:"id"`
+	Kind string `json:"kind"`
+	// Number is the issue changed. An issue created offline is a draft
+	// with a negative number, replaced by the one GitHub gives it when it
+	// is synced.
+	Number int `json:"number"`
+	// Base is the issue's UpdatedAt on GitHub when the change was queued.
+	// Sync refuses the change if the issue has been updated since.
+	Base     time.Time `json:"base,omitzero"`
+	QueuedAt time.Time `json:"queued_at"`
+
+	Create *github.CreateIssueRequest `json:"create,omitempty"`
+	// Edit holds the fields set by edit and assign changes.
+	Edit *Edit `json:"edit,omitempty"`
+	// Reason is the state reason of a close.
+	Reason  string `json:"reason,omitempty"`
+	Comment string `json:"comment,omitempty"`
+
+	// Conflict explains why the last sync refused the change.
+	Conflict string `json:"conflict,omitempty"`
+	// Error is GitHub's answer when the last sync sent the change and it
+	// was rejected.
+	Error string `json:"error,omitempty"`
+}
+
+// Edit is an UpdateIssueRequest that keeps its meaning through JSON: nil
+// fields are left unchanged, and empty label and assignee lists clear them.
+type Edit struct {
+	Title       *string   `json:"title,omitempty"`
+	Body        *string   `json:"body,omitempty"`
+	State       *string   `json:"state,omitempty"`
+	StateReason *string   `json:"state_reason,omitempty"`
+	Labels      *[]string `json:"labels,omitempty"`
+	Assignees   *[]string `json:"assignees,omitempty"`
+	Milestone   *int      `json:"milestone,omitempty"`
+}
+
+func newEdit(req github.UpdateIssueRequest) *Edit {
+	e := &Edit{
+		Title:       req.Title,
+		Body:        req.Body,
+		State:       req.State,
+		StateReason: req.StateReason,
+		Milestone:   req.Milestone,
+	}
+	if req.Labels != nil {
+		e.Labels = &req.Labels
+	}
+	if req.Assignees != nil {
+		e.Assignees = &req.Assignees
+	}
+	return e
+}
+
+func (e *Edit) request() github.UpdateIssueRequest {
+	req := github.UpdateIssueRequest{
+		Title:       e.Title,
+		Body:        e.Body,
+		State:       e.State,
+		StateReason: e.StateReason,
+		Milestone:   e.Milestone,
+	}
+	if e.Labels != nil {
+		req.Labels = append([]string{}, *e.Labels...)
+	}
+	if e.Assignees != nil {
+		req.Assignees = append([]string{}, *e.Assignees...)
+	}
+	return req
+}
+
+// String describes the change, such as "close #12".
+func (ch Change) String() string {
+	switch ch.Kind {
+	case KindCreate:
+		return fmt.Sprintf("create %s %q", FormatNumber(ch.Number), ch.Create.Title)
+	case KindComment:
+		return fmt.Sprintf("comment on %s", FormatNumber(ch.Number))
+	}
+	return fmt.Sprintf("%s %s", ch.Kind, FormatNumber(ch.Number))
+}
+
+// creates reports whether the change creates the draft number.
+func (ch Change) creates(number int) bool {
+	return ch.Kind == KindCreate && ch.Number == number
+}
+
+// draftPrefix starts the names of drafts, such as draft-1.
+const draftPrefix = "draft-"
+
+// IsDraft reports whether number belongs to an issue created offline that
+// GitHub has not numbered yet. Drafts are numbered -1, -2 and so on, so
+// they never collide with an issue on GitHub.
+func IsDraft(number int) bool {
+	return number < 0
+}
+
+// FormatNumber names an issue: #12, or draft-1 for a draft.
+func FormatNumber(number int) string {
+	if IsDraft(number) {
+		return draftPrefix + strconv.Itoa(-number)
+	}
+	return "#" + strconv.Itoa(number)
+}
+
+// ParseDraft parses the name of a draft, such as draft-1, into its number.
+func ParseDraft(name string) (int, bool) {
+	n, found := strings.CutPrefix(name, draftPrefix)
+	if !found {
+		return 0, false
+	}
+	number, err := strconv.Atoi(n)
+	if err != nil || number <= 0 {
+		return 0, false
+	}
+	return -number, true
+}
+
+// localIssue returns the issue a create change will make.
+func (ch Change) localIssue(labels []github.Label, milestones []github.Milestone) github.Issue {
+	issue := github.Issue{
+		Number:    ch.Number,
+		Title:     ch.Create.Title,
+		Body:      ch.Create.Body,
+		State:     "open",
+		Labels:    labelsNamed(ch.Create.Labels, labels),
+		Assignees: users(ch.Create.Assignees),
+		CreatedAt: ch.QueuedAt,
+		UpdatedAt: ch.QueuedAt,
+	}
+	if ch.Create.Milestone != 0 {
+		issue.Milestone = milestone(ch.Create.Milestone, milestones)
+	}
+	return issue
+}
+
+// localComment returns the comment a comment change will post.
+func (ch Change) localComment(login string) github.IssueComment {
+	return github.IssueComment{
+		Body:      ch.Comment,
+		User:      github.User{Login: login},
+		CreatedAt: ch.QueuedAt,
+		UpdatedAt: ch.QueuedAt,
+	}
+}
+
+// apply makes the change to issue as GitHub would.
+func (ch Change) apply(issue *github.Issue, labels []github.Label, milestones []github.Milestone) {
+	switch ch.Kind {
+	case KindEdit, KindAssign:
+		e := ch.Edit
+		if e.Title != nil {
+			issue.Title = *e.Title
+		}
+		if e.Body != nil {
+			issue.Body = *e.Body
+		}
+		if e.State != nil {
+			issue.State = *e.State
+		}
+		if e.Labels != nil {
+			issue.Labels = labelsNamed(*e.Labels, labels)
+		}
+		if e.Assignees != nil {
+			issue.Assignees = users(*e.Assignees)
+		}
+		if e.Milestone != nil {
+			issue.Milestone = nil
+			if *e.Milestone != 0 {
+				issue.Milestone = milestone(*e.Milestone, milestones)
+			}
+		}
+	case KindClose:
+		issue.State = "closed"
+	case KindReopen:
+		issue.State = "open"
+	case KindComment:
+		issue.Comments++
+	}
+	issue.UpdatedAt = ch.QueuedAt
+}
+
+func users(logins []string) []github.User {
+	users := make([]github.User, len(logins))
+	for i, login := range logins {
+		users[i] = github.User{Login: login}
+	}
+	return users
+}
+
+// milestone returns the milestone numbered number, or one with just the
+// number when the store has not seen it.
+func milestone(number int, milestones []github.Milestone) *github.Milestone {
+	if i := slices.IndexFunc(milestones, func(m github.Milestone) bool { return m.Number == number }); i >= 0 {
+		m := milestones[i]
+		return &m
+	}
+	return &github.Milestone{Number: number}
+}
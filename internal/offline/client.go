@@ -0,0 +1,532 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// ErrOffline is returned for what cannot be done without GitHub while it
+// is unreachable, or offline mode is forced.
+var ErrOffline = errors.New("offline")
+
+// retryInterval is how long the client stays offline after GitHub could
+// not be reached before trying it again.
+const retryInterval = time.Minute
+
+// Client implements github.Client on top of another client, falling back
+// to a Store when GitHub cannot be reached.
+type Client struct {
+	remote github.Client
+	store  *Store
+	repo   string
+	forced bool
+
+	mu        sync.Mutex
+	downUntil time.Time
+	fromStore bool
+	queued    int
+}
+
+// NewClient returns a client for repo, given as owner/name, that sends
+// calls to remote and keeps store up to date. With forced set, remote is
+// never contacted and everything is served from or queued in store.
+func NewClient(remote github.Client, store *Store, repo string, forced bool) *Client {
+	return &Client{
+		remote: remote,
+		store:  store,
+		repo:   repo,
+		forced: forced,
+	}
+}
+
+// Store returns the client's store.
+func (c *Client) Store() *Store {
+	return c.store
+}
+
+// ServedFromStore reports whether results have been served from the store
+// because GitHub was unreachable.
+func (c *Client) ServedFromStore() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fromStore
+}
+
+// Queued returns how many changes the client has added to the outbox.
+func (c *Client) Queued() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.queued
+}
+
+// online reports whether GitHub should be tried.
+func (c *Client) online() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.forced && !time.Now().Before(c.downUntil)
+}
+
+// lost reports whether err shows GitHub to be unreachable, in which case
+// the client goes offline for a while.
+func (c *Client) lost(err error) bool {
+	if !unreachable(err) {
+		return false
+	}
+	c.goOffline()
+	return true
+}
+
+// lostBeforeSending is lost for writes: it reports only errors that show
+// the request never reached GitHub, so queueing it cannot apply it twice.
+func (c *Client) lostBeforeSending(err error) bool {
+	if !notSent(err) {
+		return false
+	}
+	c.goOffline()
+	return true
+}
+
+func (c *Client) goOffline() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.downUntil = time.Now().Add(retryInterval)
+}
+
+func (c *Client) servedFromStore() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fromStore = true
+}
+
+// unreachable reports whether err is a failure to talk to GitHub at all,
+// rather than an answer from it.
+func unreachable(err error) bool {
+	var apiErr *github.APIError
+	if err == nil || errors.Is(err, context.Canceled) || errors.As(err, &apiErr) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// notSent reports whether err shows a request failed before it was sent,
+// because the host could not be resolved or connected to.
+func notSent(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(err, &dnsErr) || errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// mustBeOnline calls fn unless the client is offline, for calls the store
+// cannot answer.
+func mustBeOnline[T any](c *Client, fn func() (T, error)) (T, error) {
+	var zero T
+	if !c.online() {
+		return zero, fmt.Errorf("%w: this needs a connection to GitHub", ErrOffline)
+	}
+	v, err := fn()
+	if c.lost(err) {
+		return zero, fmt.Errorf("%w: %w", ErrOffline, err)
+	}
+	return v, err
+}
+
+// keep records results fetched from GitHub in the store. The store is a
+// best-effort copy, so failing to save it does not fail the call.
+func (c *Client) keep(issues ...github.Issue) {
+	var own []github.Issue
+	for _, issue := range issues {
+		if c.ownIssue(issue) {
+			own = append(own, issue)
+		}
+	}
+	if len(own) > 0 {
+		_ = c.store.putIssues(own)
+	}
+}
+
+// ownIssue reports whether issue is in the client's repository. Only
+// search results can come from others, and they name their repository.
+func (c *Client) ownIssue(issue github.Issue) bool {
+	return issue.RepositoryURL == "" ||
+		strings.HasSuffix(strings.ToLower(issue.RepositoryURL), "/repos/"+strings.ToLower(c.repo))
+}
+
+func (c *Client) GetIssue(ctx context.Context, number int) (*github.Issue, error) {
+	if c.online() && !IsDraft(number) {
+		issue, err := c.remote.GetIssue(ctx, number)
+		if !c.lost(err) {
+			if err == nil {
+				c.keep(*issue)
+			}
+			return issue, err
+		}
+	}
+	c.servedFromStore()
+	return c.store.issue(number)
+}
+
+// GetIssueByRef serves issues in the client's repository like GetIssue.
+// The store holds no others, so those need GitHub.
+func (c *Client) GetIssueByRef(ctx context.Context, ref github.IssueRef) (*github.Issue, error) {
+	if ref.Repo == "" || strings.EqualFold(ref.Repo, c.repo) {
+		return c.GetIssue(ctx, ref.Number)
+	}
+	return mustBeOnline(c, func() (*github.Issue, error) { return c.remote.GetIssueByRef(ctx, ref) })
+}
+
+func (c *Client) ListIssues(ctx context.Context, req github.ListIssuesRequest) (*github.ListIssuesResponse, error) {
+	if c.online() {
+		resp, err := c.remote.ListIssues(ctx, req)
+		if !c.lost(err) {
+			if err == nil {
+				c.keep(resp.Issues...)
+			}
+			return resp, err
+		}
+	}
+	c.servedFromStore()
+	issues := listIssues(c.store.issues(), req)
+	return &github.ListIssuesResponse{
+		Issues:  page(issues, req.PerPage, req.Page),
+		HasNext: hasNextPage(len(issues), req.PerPage, req.Page),
+	}, nil
+}
+
+func (c *Client) ListAllIssues(ctx context.Context, req github.ListIssuesRequest) iter.Seq2[github.Issue, error] {
+	return c.all(c.remote.ListAllIssues(ctx, req), func() []github.Issue {
+		return fromPage(listIssues(c.store.issues(), req), req.PerPage, req.Page)
+	})
+}
+
+// all iterates over remote, keeping what it yields, or over the issues
+// local returns when GitHub cannot be reached before anything is yielded.
+func (c *Client) all(remote iter.Seq2[github.Issue, error], local func() []github.Issue) iter.Seq2[github.Issue, error] {
+	return func(yield func(github.Issue, error) bool) {
+		if c.online() {
+			var fetched []github.Issue
+			defer func() { c.keep(fetched...) }()
+
+			lost := false
+			for issue, err := range remote {
+				if err != nil {
+					if lost = len(fetched) == 0 && c.lost(err); !lost {
+						yield(github.Issue{}, err)
+					}
+					break
+				}
+				fetched = append(fetched, issue)
+				if !yield(issue, nil) {
+					return
+				}
+			}
+			if !lost {
+				return
+			}
+		}
+
+		c.servedFromStore()
+		for _, issue := range local() {
+			if !yield(issue, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) SearchIssues(ctx context.Context, req github.SearchIssuesRequest) (*github.SearchIssuesResponse, error) {
+	if c.online() {
+		resp, err := c.remote.SearchIssues(ctx, req)
+		if !c.lost(err) {
+			if err == nil {
+				c.keep(resp.Items...)
+			}
+			return resp, err
+		}
+	}
+	c.servedFromStore()
+	issues := searchIssues(c.store.issues(), c.repo, req)
+	return &github.SearchIssuesResponse{
+		TotalCount: len(issues),
+		Items:      page(issues, req.PerPage, req.Page),
+		HasNext:    hasNextPage(len(issues), req.PerPage, req.Page),
+	}, nil
+}
+
+func (c *Client) SearchAllIssues(ctx context.Context, req github.SearchIssuesRequest) iter.Seq2[github.Issue, error] {
+	return c.all(c.remote.SearchAllIssues(ctx, req), func() []github.Issue {
+		return searchIssues(c.store.issues(), c.repo, req)
+	})
+}
+
+func (c *Client) ListComments(ctx context.Context, number int) ([]github.IssueComment, error) {
+	if c.online() && !IsDraft(number) {
+		comments, err := c.remote.ListComments(ctx, number)
+		if !c.lost(err) {
+			if err == nil {
+				_ = c.store.putComments(map[int][]github.IssueComment{number: comments})
+			}
+			return comments, err
+		}
+	}
+	c.servedFromStore()
+	return c.store.comments(number)
+}
+
+func (c *Client) ListLabels(ctx context.Context) ([]github.Label, error) {
+	if c.online() {
+		labels, err := c.remote.ListLabels(ctx)
+		if !c.lost(err) {
+			if err == nil {
+				_ = c.store.putLabels(labels)
+			}
+			return labels, err
+		}
+	}
+	c.servedFromStore()
+	return c.store.labels(), nil
+}
+
+func (c *Client) ListMilestones(ctx context.Context, state string) ([]github.Milestone, error) {
+	if c.online() {
+		milestones, err := c.remote.ListMilestones(ctx, state)
+		if !c.lost(err) {
+			if err == nil {
+				_ = c.store.putMilestones(milestones)
+			}
+			return milestones, err
+		}
+	}
+	c.servedFromStore()
+	if state == "" {
+		state = "open"
+	}
+	return c.store.milestones(state), nil
+}
+
+// sendOrQueue sends a change to an issue, or queues it when GitHub cannot
+// be reached. Changes to an issue that already has some queued are queued
+// behind them, so they reach GitHub in order.
+func (c *Client) sendOrQueue(ctx context.Context, ch Change, send func() (*github.Issue, error)) (*github.Issue, error) {
+	if c.online() && !IsDraft(ch.Number) && !c.store.hasPending(ch.Number) {
+		issue, err := send()
+		if !c.lostBeforeSending(err) {
+			if err == nil {
+				c.keep(*issue)
+			}
+			return issue, err
+		}
+	}
+	queued, err := c.queue(ch)
+	if err != nil {
+		return nil, err
+	}
+	return c.store.issue(queued.Number)
+}
+
+func (c *Client) queue(ch Change) (Change, error) {
+	queued, err := c.store.queue(ch)
+	if err != nil {
+		return Change{}, err
+	}
+	c.mu.Lock()
+	c.queued++
+	c.mu.Unlock()
+	return queued, nil
+}
+
+func (c *Client) CreateIssue(ctx context.Context, req github.CreateIssueRequest) (*github.Issue, error) {
+	if c.online() {
+		issue, err := c.remote.CreateIssue(ctx, req)
+		if !c.lostBeforeSending(err) {
+			if err == nil {
+				c.keep(*issue)
+			}
+			return issue, err
+		}
+	}
+	queued, err := c.queue(Change{Kind: KindCreate, Create: &req})
+	if err != nil {
+		return nil, err
+	}
+	return c.store.issue(queued.Number)
+}
+
+func (c *Client) UpdateIssue(ctx context.Context, number int, req github.UpdateIssueRequest) (*github.Issue, error) {
+	ch := Change{Kind: KindEdit, Number: number, Edit: newEdit(req)}
+	return c.sendOrQueue(ctx, ch, func() (*github.Issue, error) {
+		return c.remote.UpdateIssue(ctx, number, req)
+	})
+}
+
+func (c *Client) AssignIssue(ctx context.Context, number int, assignees []string) (*github.Issue, error) {
+	ch := Change{Kind: KindAssign, Number: number, Edit: &Edit{Assignees: &assignees}}
+	return c.sendOrQueue(ctx, ch, func() (*github.Issue, error) {
+		return c.remote.AssignIssue(ctx, number, assignees)
+	})
+}
+
+// CloseIssue posts the closing comment and closes the issue separately, so
+// that either can be queued without repeating the other.
+func (c *Client) CloseIssue(ctx context.Context, number int, req github.CloseIssueRequest) (*github.Issue, error) {
+	if req.Comment != "" {
+		if _, err := c.AddComment(ctx, number, req.Comment); err != nil {
+			return nil, fmt.Errorf("adding closing comment: %w", err)
+		}
+		req.Comment = ""
+	}
+	ch := Change{Kind: KindClose, Number: number, Reason: req.Reason}
+	return c.sendOrQueue(ctx, ch, func() (*github.Issue, error) {
+		return c.remote.CloseIssue(ctx, number, req)
+	})
+}
+
+// ReopenIssue posts the reopening comment and reopens the issue separately,
+// as CloseIssue does.
+func (c *Client) ReopenIssue(ctx context.Context, number int, comment string) (*github.Issue, error) {
+	if comment != "" {
+		if _, err := c.AddComment(ctx, number, comment); err != nil {
+			return nil, fmt.Errorf("adding reopening comment: %w", err)
+		}
+	}
+	ch := Change{Kind: KindReopen, Number: number}
+	return c.sendOrQueue(ctx, ch, func() (*github.Issue, error) {
+		return c.remote.ReopenIssue(ctx, number, "")
+	})
+}
+
+func (c *Client) AddComment(ctx context.Context, number int, body string) (*github.IssueComment, error) {
+	if c.online() && !IsDraft(number) && !c.store.hasPending(number) {
+		comment, err := c.remote.AddComment(ctx, number, body)
+		if !c.lostBeforeSending(err) {
+			return comment, err
+		}
+	}
+	queued, err := c.queue(Change{Kind: KindComment, Number: number, Comment: body})
+	if err != nil {
+		return nil, err
+	}
+	comment := queued.localComment(c.store.login())
+	return &comment, nil
+}
+
+func (c *Client) UpdateComment(ctx context.Context, id int64, body string) (*github.IssueComment, error) {
+	return mustBeOnline(c, func() (*github.IssueComment, error) { return c.remote.UpdateComment(ctx, id, body) })
+}
+
+func (c *Client) DeleteComment(ctx context.Context, id int64) error {
+	_, err := mustBeOnline(c, func() (struct{}, error) { return struct{}{}, c.remote.DeleteComment(ctx, id) })
+	return err
+}
+
+func (c *Client) AddSubIssue(ctx context.Context, parent, child github.IssueRef) (*github.Issue, error) {
+	return mustBeOnline(c, func() (*github.Issue, error) { return c.remote.AddSubIssue(ctx, parent, child) })
+}
+
+func (c *Client) RemoveSubIssue(ctx context.Context, parent, child github.IssueRef) (*github.Issue, error) {
+	return mustBeOnline(c, func() (*github.Issue, error) { return c.remote.RemoveSubIssue(ctx, parent, child) })
+}
+
+func (c *Client) ReprioritizeSubIssue(ctx context.Context, parent int, child github.IssueRef, pos github.SubIssuePosition) (*github.Issue, error) {
+	return mustBeOnline(c, func() (*github.Issue, error) { return c.remote.ReprioritizeSubIssue(ctx, parent, child, pos) })
+}
+
+func (c *Client) ListSubIssues(ctx context.Context, parent int) ([]github.Issue, error) {
+	return mustBeOnline(c, func() ([]github.Issue, error) { return c.remote.ListSubIssues(ctx, parent) })
+}
+
+func (c *Client) GetParentIssue(ctx context.Context, number int) (*github.Issue, error) {
+	return mustBeOnline(c, func() (*github.Issue, error) { return c.remote.GetParentIssue(ctx, number) })
+}
+
+func (c *Client) AddBlockedBy(ctx context.Context, number, blocker github.IssueRef) (*github.Issue, error) {
+	return mustBeOnline(c, func() (*github.Issue, error) { return c.remote.AddBlockedBy(ctx, number, blocker) })
+}
+
+func (c *Client) RemoveBlockedBy(ctx context.Context, number, blocker github.IssueRef) (*github.Issue, error) {
+	return mustBeOnline(c, func() (*github.Issue, error) { return c.remote.RemoveBlockedBy(ctx, number, blocker) })
+}
+
+func (c *Client) ListBlockedBy(ctx context.Context, number int) ([]github.Issue, error) {
+	return mustBeOnline(c, func() ([]github.Issue, error) { return c.remote.ListBlockedBy(ctx, number) })
+}
+
+func (c *Client) ListBlocking(ctx context.Context, number int) ([]github.Issue, error) {
+	return mustBeOnline(c, func() ([]github.Issue, error) { return c.remote.ListBlocking(ctx, number) })
+}
+
+func (c *Client) ListLinkedPullRequests(ctx context.Context, number int) ([]github.LinkedPullRequest, error) {
+	return mustBeOnline(c, func() ([]github.LinkedPullRequest, error) { return c.remote.ListLinkedPullRequests(ctx, number) })
+}
+
+func (c *Client) ListTimeline(ctx context.Context, number int) ([]github.TimelineEvent, error) {
+	return mustBeOnline(c, func() ([]github.TimelineEvent, error) { return c.remote.ListTimeline(ctx, number) })
+}
+
+func (c *Client) ListAssignees(ctx context.Context) ([]github.User, error) {
+	return mustBeOnline(c, func() ([]github.User, error) { return c.remote.ListAssignees(ctx) })
+}
+
+func (c *Client) CreateLabel(ctx context.Context, req github.LabelRequest) (*github.Label, error) {
+	return mustBeOnline(c, func() (*github.Label, error) { return c.remote.CreateLabel(ctx, req) })
+}
+
+func (c *Client) UpdateLabel(ctx context.Context, name string, req github.LabelRequest) (*github.Label, error) {
+	return mustBeOnline(c, func() (*github.Label, error) { return c.remote.UpdateLabel(ctx, name, req) })
+}
+
+func (c *Client) DeleteLabel(ctx context.Context, name string) error {
+	_, err := mustBeOnline(c, func() (struct{}, error) { return struct{}{}, c.remote.DeleteLabel(ctx, name) })
+	return err
+}
+
+func (c *Client) CreateMilestone(ctx context.Context, req github.CreateMilestoneRequest) (*github.Milestone, error) {
+	return mustBeOnline(c, func() (*github.Milestone, error) { return c.remote.CreateMilestone(ctx, req) })
+}
+
+func (c *Client) UpdateMilestone(ctx context.Context, number int, req github.UpdateMilestoneRequest) (*github.Milestone, error) {
+	return mustBeOnline(c, func() (*github.Milestone, error) { return c.remote.UpdateMilestone(ctx, number, req) })
+}
+
+func (c *Client) GetProject(ctx context.Context, owner string, number int) (*github.Project, error) {
+	return mustBeOnline(c, func() (*github.Project, error) { return c.remote.GetProject(ctx, owner, number) })
+}
+
+func (c *Client) GetProjectByID(ctx context.Context, id string) (*github.Project, error) {
+	return mustBeOnline(c, func() (*github.Project, error) { return c.remote.GetProjectByID(ctx, id) })
+}
+
+func (c *Client) AddProjectItem(ctx context.Context, projectID, contentID string) (string, error) {
+	return mustBeOnline(c, func() (string, error) { return c.remote.AddProjectItem(ctx, projectID, contentID) })
+}
+
+func (c *Client) UpdateProjectItemField(ctx context.Context, projectID, itemID, fieldID string, value github.ProjectFieldValue) error {
+	_, err := mustBeOnline(c, func() (struct{}, error) {
+		return struct{}{}, c.remote.UpdateProjectItemField(ctx, projectID, itemID, fieldID, value)
+	})
+	return err
+}
+
+func (c *Client) ListProjectItems(ctx context.Context, number int) ([]github.ProjectItem, error) {
+	return mustBeOnline(c, func() ([]github.ProjectItem, error) { return c.remote.ListProjectItems(ctx, number) })
+}
+
+func (c *Client) ListDirectory(ctx context.Context, dir string) ([]github.ContentEntry, error) {
+	return mustBeOnline(c, func() ([]github.ContentEntry, error) { return c.remote.ListDirectory(ctx, dir) })
+}
+
+func (c *Client) GetFileContent(ctx context.Context, file string) ([]byte, error) {
+	return mustBeOnline(c, func() ([]byte, error) { return c.remote.GetFileContent(ctx, file) })
+}
+
+func (c *Client) RateLimit() github.RateLimit {
+	return c.remote.RateLimit()
+}
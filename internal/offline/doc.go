@@ -0,0 +1,12 @@
+// Package offline keeps a local copy of a repository's issues so grit keeps
+// working when GitHub cannot be reached.
+//
+// Client wraps a github.Client. While GitHub answers, it passes calls
+// through and mirrors the issues, comments, labels and milestones it sees
+// into a Store. When GitHub is unreachable, or offline mode is forced,
+// issues are listed, viewed and searched from the Store instead, and
+// creating, editing, assigning, closing, reopening and commenting on issues
+// are queued in an outbox. Sync later sends the queued changes, refusing
+// those whose issue has changed on GitHub since they were queued, and
+// refreshes the Store.
+package offline
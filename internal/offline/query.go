@@ -0,0 +1,278 @@
+package offline
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// defaultPerPage is the page size GitHub uses when none is requested.
+const defaultPerPage = 30
+
+// listIssues filters and orders issues as the issues endpoint does: newest
+// first, open issues unless req asks otherwise.
+func listIssues(issues []github.Issue, req github.ListIssuesRequest) []github.Issue {
+	state := cmp.Or(req.State, "open")
+	labels := splitLabels(req.Labels)
+
+	var matched []github.Issue
+	for _, issue := range issues {
+		switch {
+		case state != "all" && issue.State != state:
+		case issue.UpdatedAt.Before(req.Since):
+		case !hasLabels(issue, labels):
+		case !matchAssignee(issue, req.Assignee):
+		case !matchMilestone(issue, req.Milestone):
+		default:
+			matched = append(matched, issue)
+		}
+	}
+
+	sortIssues(matched, "created")
+	return matched
+}
+
+// searchIssues answers an issue search from the issues of repo, given as
+// owner/name. The query's terms are matched against titles and bodies, and
+// the qualifiers grit uses are understood; other qualifiers are matched as
+// text, as GitHub does.
+func searchIssues(issues []github.Issue, repo string, req github.SearchIssuesRequest) []github.Issue {
+	q := parseQuery(req.Query)
+	repos := append(slices.Clone(req.Repos), q.repos...)
+	if len(repos) > 0 && !slices.ContainsFunc(repos, func(r string) bool { return strings.EqualFold(r, repo) }) {
+		return nil
+	}
+	if !req.IncludePullRequests && q.kind == "" {
+		q.kind = "issue"
+	}
+	if req.State != "" && req.State != "all" {
+		q.state = req.State
+	}
+	q.labels = append(q.labels, splitLabels(req.Labels)...)
+	q.milestone = cmp.Or(req.Milestone, q.milestone)
+	q.assignee = cmp.Or(req.Assignee, q.assignee)
+
+	var matched []github.Issue
+	for _, issue := range issues {
+		if q.matches(issue) {
+			matched = append(matched, issue)
+		}
+	}
+
+	// Without relevance to rank by, results are most recently updated
+	// first.
+	sortIssues(matched, cmp.Or(req.Sort, "updated"))
+	return matched
+}
+
+// sortIssues orders issues newest first by "created", "updated" or
+// "comments", breaking ties by number.
+func sortIssues(issues []github.Issue, by string) {
+	slices.SortStableFunc(issues, func(a, b github.Issue) int {
+		c := 0
+		switch by {
+		case "updated":
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		case "comments":
+			c = cmp.Compare(a.Comments, b.Comments)
+		default:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return -cmp.Or(c, cmp.Compare(a.Number, b.Number))
+	})
+}
+
+// page returns the items on a 1-based page of perPage items.
+func page[T any](items []T, perPage, number int) []T {
+	if perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	start := (max(number, 1) - 1) * perPage
+	if start >= len(items) {
+		return nil
+	}
+	return items[start:min(start+perPage, len(items))]
+}
+
+// hasNextPage reports whether total items run past a 1-based page of
+// perPage items.
+func hasNextPage(total, perPage, number int) bool {
+	if perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	return max(number, 1)*perPage < total
+}
+
+// fromPage returns the items from a 1-based page of perPage items onwards,
+// where ListAllIssues starts. Its pages hold 100 items unless set.
+func fromPage[T any](items []T, perPage, number int) []T {
+	if perPage <= 0 {
+		perPage = 100
+	}
+	return items[min((max(number, 1)-1)*perPage, len(items)):]
+}
+
+func splitLabels(csv string) []string {
+	var labels []string
+	for _, l := range strings.Split(csv, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+func hasLabels(issue github.Issue, labels []string) bool {
+	for _, want := range labels {
+		if !slices.ContainsFunc(issue.Labels, func(l github.Label) bool { return strings.EqualFold(l.Name, want) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAssignee filters by a login, "*" for any assignee or "none".
+func matchAssignee(issue github.Issue, assignee string) bool {
+	switch assignee {
+	case "":
+		return true
+	case "*":
+		return len(issue.Assignees) > 0
+	case "none":
+		return len(issue.Assignees) == 0
+	}
+	return slices.ContainsFunc(issue.Assignees, func(u github.User) bool { return strings.EqualFold(u.Login, assignee) })
+}
+
+// matchMilestone filters by a milestone number, "*" for any milestone or
+// "none".
+func matchMilestone(issue github.Issue, milestone string) bool {
+	switch milestone {
+	case "":
+		return true
+	case "*":
+		return issue.Milestone != nil
+	case "none":
+		return issue.Milestone == nil
+	}
+	n, err := strconv.Atoi(milestone)
+	return err == nil && issue.Milestone != nil && issue.Milestone.Number == n
+}
+
+// query is a parsed issue search query.
+type query struct {
+	repos     []string
+	kind      string // "issue", "pr" or empty for both
+	state     string
+	labels    []string
+	noLabel   bool
+	assignee  string
+	milestone string // a title or "none"
+	terms     []string
+}
+
+func parseQuery(s string) query {
+	var q query
+	for _, token := range splitQuery(s) {
+		key, value, ok := strings.Cut(token, ":")
+		value = strings.Trim(value, `"`)
+		if !ok || value == "" {
+			q.terms = append(q.terms, strings.ToLower(strings.Trim(token, `"`)))
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "repo":
+			q.repos = append(q.repos, value)
+		case "is", "type":
+			switch value {
+			case "issue", "pr":
+				q.kind = value
+			case "open", "closed":
+				q.state = value
+			}
+		case "state":
+			q.state = value
+		case "label":
+			q.labels = append(q.labels, value)
+		case "assignee":
+			q.assignee = value
+		case "milestone":
+			q.milestone = value
+		case "no":
+			switch value {
+			case "assignee":
+				q.assignee = "none"
+			case "milestone":
+				q.milestone = "none"
+			case "label":
+				q.noLabel = true
+			}
+		default:
+			q.terms = append(q.terms, strings.ToLower(token))
+		}
+	}
+	return q
+}
+
+// splitQuery splits a search query on spaces outside double quotes.
+func splitQuery(s string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ' ' && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+func (q query) matches(issue github.Issue) bool {
+	switch {
+	case q.kind == "issue" && issue.IsPullRequest(), q.kind == "pr" && !issue.IsPullRequest():
+		return false
+	case q.state != "" && issue.State != q.state:
+		return false
+	case q.noLabel && len(issue.Labels) > 0:
+		return false
+	case !hasLabels(issue, q.labels):
+		return false
+	case q.assignee != "" && !matchAssignee(issue, q.assignee):
+		return false
+	}
+
+	switch q.milestone {
+	case "":
+	case "none":
+		if issue.Milestone != nil {
+			return false
+		}
+	default:
+		if issue.Milestone == nil || !strings.EqualFold(issue.Milestone.Title, q.milestone) {
+			return false
+		}
+	}
+
+	text := strings.ToLower(issue.Title + "\n" + issue.Body)
+	for _, term := range q.terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,578 @@
+package offline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+const (
+	issuesFile  = "issues.json"
+	journalFile = "issues.journal"
+	outboxFile  = "outbox.json"
+
+	// journalLimit is the size past which the journal is folded into the
+	// issues file.
+	journalLimit = 1 << 20
+)
+
+// Store is the local copy of a repository's issues and the outbox of
+// changes made to them while offline, kept as JSON files in a directory.
+// It is safe for concurrent use.
+//
+// Issues, comments, labels and milestones fetched by ordinary commands are
+// appended to a journal, one entry per line, rather than rewriting the
+// whole issues file on every read. The journal is folded into the issues
+// file by Sync, or once it grows past journalLimit.
+type Store struct {
+	dir string
+
+	mu          sync.Mutex
+	data        storeData
+	outbox      outbox
+	journalSize int64
+}
+
+type storeData struct {
+	// SyncedAt is when Sync last completed.
+	SyncedAt time.Time `json:"synced_at,omitzero"`
+	// FetchedUpTo is the latest UpdatedAt of the issues Sync has fetched,
+	// by GitHub's clock. The next sync fetches the issues updated since.
+	FetchedUpTo time.Time `json:"fetched_up_to,omitzero"`
+	// Login is the authenticated user, credited with comments made offline.
+	Login      string             `json:"login,omitempty"`
+	Issues     map[int]*record    `json:"issues"`
+	Labels     []github.Label     `json:"labels,omitempty"`
+	Milestones []github.Milestone `json:"milestones,omitempty"`
+}
+
+// record is an issue as last seen on GitHub, without the changes queued
+// for it.
+type record struct {
+	Issue    github.Issue          `json:"issue"`
+	Comments []github.IssueComment `json:"comments,omitempty"`
+	// CommentsAt is the issue's UpdatedAt when Comments was fetched, or zero
+	// when they never were.
+	CommentsAt time.Time `json:"comments_updated_at,omitzero"`
+}
+
+// commentsCurrent reports whether Comments holds the comments as of the
+// issue's last update.
+func (r *record) commentsCurrent() bool {
+	return r.Issue.Comments == 0 || r.CommentsAt.Equal(r.Issue.UpdatedAt)
+}
+
+// entry is a line of the journal. It holds one of an issue, the comments
+// on an issue, the labels or some milestones.
+type entry struct {
+	Issue      *github.Issue      `json:"issue,omitempty"`
+	Comments   *issueComments     `json:"comments,omitempty"`
+	Labels     *[]github.Label    `json:"labels,omitempty"`
+	Milestones []github.Milestone `json:"milestones,omitempty"`
+}
+
+// issueComments are the comments on an issue as of its update at
+// UpdatedAt.
+type issueComments struct {
+	Number    int                   `json:"number"`
+	List      []github.IssueComment `json:"list"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+type outbox struct {
+	NextID  int      `json:"next_id"`
+	Changes []Change `json:"changes"`
+}
+
+// Open loads the store kept in dir. A directory that does not exist yet
+// holds an empty store.
+func Open(dir string) (*Store, error) {
+	s := &Store{
+		dir:    dir,
+		data:   storeData{Issues: make(map[int]*record)},
+		outbox: outbox{NextID: 1},
+	}
+	if err := s.read(issuesFile, &s.data); err != nil {
+		return nil, err
+	}
+	if err := s.read(outboxFile, &s.outbox); err != nil {
+		return nil, err
+	}
+	if s.data.Issues == nil {
+		s.data.Issues = make(map[int]*record)
+	}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay applies the entries in the journal. A line cut short by a failed
+// write is skipped.
+func (s *Store) replay() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, journalFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading offline store: %w", err)
+	}
+	s.journalSize = int64(len(data))
+
+	for line := range bytes.Lines(data) {
+		var e entry
+		if json.Unmarshal(line, &e) == nil {
+			s.apply(e)
+		}
+	}
+	return nil
+}
+
+func (s *Store) read(name string, v any) error {
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading offline store: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parsing offline store %s: %w", name, err)
+	}
+	return nil
+}
+
+// write saves v as the named file. The caller must hold s.mu.
+func (s *Store) write(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding offline store: %w", err)
+	}
+	if err := s.create(); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename, so a failed write never
+	// leaves a truncated file behind.
+	tmp, err := os.CreateTemp(s.dir, name+".*")
+	if err != nil {
+		return fmt.Errorf("writing offline store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing offline store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing offline store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return fmt.Errorf("writing offline store: %w", err)
+	}
+	return nil
+}
+
+// create makes the store's directory. The store sits inside .grit, which is
+// usually committed, so it ignores itself.
+func (s *Store) create() error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("creating offline store: %w", err)
+	}
+	ignore := filepath.Join(s.dir, ".gitignore")
+	if _, err := os.Stat(ignore); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(ignore, []byte("*\n"), 0644); err != nil {
+			return fmt.Errorf("creating offline store: %w", err)
+		}
+	}
+	return nil
+}
+
+// save writes the issues file, which then holds everything in the
+// journal. The caller must hold s.mu.
+func (s *Store) save() error {
+	if err := s.write(issuesFile, s.data); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.dir, journalFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("writing offline store: %w", err)
+	}
+	s.journalSize = 0
+	return nil
+}
+
+// journal appends entries to the journal. The caller must hold s.mu.
+func (s *Store) journal(entries ...entry) error {
+	var buf bytes.Buffer
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("encoding offline store: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if s.journalSize+int64(buf.Len()) > journalLimit {
+		return s.save()
+	}
+
+	if err := s.create(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(s.dir, journalFile), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("writing offline store: %w", err)
+	}
+	n, err := f.Write(buf.Bytes())
+	s.journalSize += int64(n)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("writing offline store: %w", err)
+	}
+	return nil
+}
+
+// SyncedAt returns when the store was last synced, or zero if it never was.
+func (s *Store) SyncedAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.SyncedAt
+}
+
+// login returns the authenticated user as of the last sync.
+func (s *Store) login() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.Login
+}
+
+// Pending returns the changes waiting in the outbox, oldest first.
+func (s *Store) Pending() []Change {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.outbox.Changes)
+}
+
+// putIssues records issues as they are on GitHub. Only those that changed
+// are written, to the journal.
+func (s *Store) putIssues(issues []github.Issue) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changed []entry
+	for _, issue := range issues {
+		e := entry{Issue: &issue}
+		if s.apply(e) {
+			changed = append(changed, e)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return s.journal(changed...)
+}
+
+// putComments records the comments on issues, by issue number, as they
+// are on GitHub. They are kept only for issues the store holds.
+func (s *Store) putComments(comments map[int][]github.IssueComment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changed []entry
+	for number, list := range comments {
+		rec, ok := s.data.Issues[number]
+		if !ok {
+			continue
+		}
+		e := entry{Comments: &issueComments{Number: number, List: list, UpdatedAt: rec.Issue.UpdatedAt}}
+		if s.apply(e) {
+			changed = append(changed, e)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return s.journal(changed...)
+}
+
+func (s *Store) putLabels(labels []github.Label) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := entry{Labels: &labels}
+	if !s.apply(e) {
+		return nil
+	}
+	return s.journal(e)
+}
+
+// putMilestones records milestones, replacing those with the same number.
+func (s *Store) putMilestones(milestones []github.Milestone) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := entry{Milestones: milestones}
+	if !s.apply(e) {
+		return nil
+	}
+	return s.journal(e)
+}
+
+// apply records an entry, reporting whether it changed the store. Issues
+// are recorded unless the store already holds them as of the same or a
+// later update. The caller must hold s.mu.
+func (s *Store) apply(e entry) bool {
+	switch {
+	case e.Issue != nil:
+		rec, ok := s.data.Issues[e.Issue.Number]
+		switch {
+		case !ok:
+			rec = &record{}
+			s.data.Issues[e.Issue.Number] = rec
+		case !e.Issue.UpdatedAt.After(rec.Issue.UpdatedAt):
+			return false
+		}
+		rec.Issue = *e.Issue
+		return true
+
+	case e.Comments != nil:
+		rec, ok := s.data.Issues[e.Comments.Number]
+		if !ok {
+			return false
+		}
+		rec.Comments = e.Comments.List
+		rec.CommentsAt = e.Comments.UpdatedAt
+		return true
+
+	case e.Labels != nil:
+		if slices.Equal(s.data.Labels, *e.Labels) {
+			return false
+		}
+		s.data.Labels = *e.Labels
+		return true
+
+	case len(e.Milestones) > 0:
+		for _, m := range e.Milestones {
+			i := slices.IndexFunc(s.data.Milestones, func(x github.Milestone) bool { return x.Number == m.Number })
+			if i < 0 {
+				s.data.Milestones = append(s.data.Milestones, m)
+			} else {
+				s.data.Milestones[i] = m
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// synced records a completed sync that fetched issues.
+func (s *Store) synced(at time.Time, login string, issues []github.Issue) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.SyncedAt = at
+	if login != "" {
+		s.data.Login = login
+	}
+	for _, issue := range issues {
+		if issue.UpdatedAt.After(s.data.FetchedUpTo) {
+			s.data.FetchedUpTo = issue.UpdatedAt
+		}
+	}
+	return s.save()
+}
+
+// fetchedUpTo returns the time the next sync fetches updated issues from,
+// or zero before the first sync.
+func (s *Store) fetchedUpTo() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.FetchedUpTo
+}
+
+// staleComments returns the numbers of the issues whose comments have
+// changed since they were fetched.
+func (s *Store) staleComments() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var numbers []int
+	for number, rec := range s.data.Issues {
+		if !rec.commentsCurrent() {
+			numbers = append(numbers, number)
+		}
+	}
+	slices.Sort(numbers)
+	return numbers
+}
+
+// issue returns an issue with its queued changes applied.
+func (s *Store) issue(number int) (*github.Issue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issue, ok := s.issueLocked(number)
+	if !ok {
+		return nil, fmt.Errorf("%w: issue %s has not been synced", ErrOffline, FormatNumber(number))
+	}
+	return issue, nil
+}
+
+func (s *Store) issueLocked(number int) (*github.Issue, bool) {
+	var issue github.Issue
+	if rec, ok := s.data.Issues[number]; ok {
+		issue = rec.Issue
+	} else if i := slices.IndexFunc(s.outbox.Changes, func(ch Change) bool { return ch.creates(number) }); i >= 0 {
+		issue = s.outbox.Changes[i].localIssue(s.data.Labels, s.data.Milestones)
+	} else {
+		return nil, false
+	}
+
+	for _, ch := range s.outbox.Changes {
+		if ch.Number == number {
+			ch.apply(&issue, s.data.Labels, s.data.Milestones)
+		}
+	}
+	issue.Labels = slices.Clone(issue.Labels)
+	issue.Assignees = slices.Clone(issue.Assignees)
+	return &issue, true
+}
+
+// issues returns every issue with its queued changes applied, including
+// those created offline.
+func (s *Store) issues() []github.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issues := make([]github.Issue, 0, len(s.data.Issues))
+	for number := range s.data.Issues {
+		issue, _ := s.issueLocked(number)
+		issues = append(issues, *issue)
+	}
+	for _, ch := range s.outbox.Changes {
+		if ch.Kind == KindCreate {
+			issue, _ := s.issueLocked(ch.Number)
+			issues = append(issues, *issue)
+		}
+	}
+	return issues
+}
+
+// comments returns the comments on an issue, followed by those queued.
+func (s *Store) comments(number int) ([]github.IssueComment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var comments []github.IssueComment
+	if rec, ok := s.data.Issues[number]; ok {
+		if !rec.commentsCurrent() && rec.CommentsAt.IsZero() {
+			return nil, fmt.Errorf("%w: the comments on #%d have not been synced", ErrOffline, number)
+		}
+		comments = slices.Clone(rec.Comments)
+	} else if _, ok := s.issueLocked(number); !ok {
+		return nil, fmt.Errorf("%w: issue %s has not been synced", ErrOffline, FormatNumber(number))
+	}
+
+	for _, ch := range s.outbox.Changes {
+		if ch.Number == number && ch.Kind == KindComment {
+			comments = append(comments, ch.localComment(s.data.Login))
+		}
+	}
+	return comments, nil
+}
+
+func (s *Store) labels() []github.Label {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.data.Labels)
+}
+
+// milestones returns the milestones in state, which is "open", "closed" or
+// "all".
+func (s *Store) milestones(state string) []github.Milestone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var milestones []github.Milestone
+	for _, m := range s.data.Milestones {
+		if state == "all" || m.State == state {
+			milestones = append(milestones, m)
+		}
+	}
+	return milestones
+}
+
+// hasPending reports whether changes to the issue are waiting in the
+// outbox. Later changes to it must be queued behind them.
+func (s *Store) hasPending(number int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.ContainsFunc(s.outbox.Changes, func(ch Change) bool { return ch.Number == number })
+}
+
+// queue adds a change to the outbox, recording the issue's UpdatedAt on
+// GitHub as its base. Issues created offline are drafts, given the next
+// draft number after those queued.
+func (s *Store) queue(ch Change) (Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ch.Kind == KindCreate {
+		for _, queued := range s.outbox.Changes {
+			ch.Number = min(ch.Number, queued.Number)
+		}
+		ch.Number--
+	} else {
+		rec, ok := s.data.Issues[ch.Number]
+		switch {
+		case ok:
+			ch.Base = rec.Issue.UpdatedAt
+		case !slices.ContainsFunc(s.outbox.Changes, func(x Change) bool { return x.creates(ch.Number) }):
+			return Change{}, fmt.Errorf("%w: issue %s has not been synced", ErrOffline, FormatNumber(ch.Number))
+		}
+	}
+
+	ch.ID = s.outbox.NextID
+	ch.QueuedAt = time.Now().UTC()
+	s.outbox.NextID++
+	s.outbox.Changes = append(s.outbox.Changes, ch)
+	if err := s.write(outboxFile, s.outbox); err != nil {
+		s.outbox.Changes = s.outbox.Changes[:len(s.outbox.Changes)-1]
+		return Change{}, err
+	}
+	return ch, nil
+}
+
+// setPending replaces the outbox after a sync.
+func (s *Store) setPending(changes []Change) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.outbox.Changes = changes
+	return s.write(outboxFile, s.outbox)
+}
+
+// labelsNamed returns the labels called names, with the colors of the
+// repository's labels where they are known.
+func labelsNamed(names []string, known []github.Label) []github.Label {
+	labels := make([]github.Label, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(known, func(l github.Label) bool { return strings.EqualFold(l.Name, name) })
+		if i >= 0 {
+			labels = append(labels, known[i])
+		} else {
+			labels = append(labels, github.Label{Name: name})
+		}
+	}
+	return labels
+}
@@ -0,0 +1,258 @@
+package offline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// SyncOptions controls what Sync does with changes whose issue has been
+// updated on GitHub since they were queued.
+type SyncOptions struct {
+	// Force sends conflicting changes anyway, overwriting the update.
+	Force bool
+	// Discard drops conflicting changes, and those GitHub rejects, from
+	// the outbox.
+	Discard bool
+}
+
+// SyncResult reports what Sync did.
+type SyncResult struct {
+	// Sent lists the changes sent to GitHub.
+	Sent []Change
+	// Created maps the numbers of drafts to the numbers GitHub gave them.
+	Created map[int]int
+	// Conflicts lists the changes kept in the outbox because their issue
+	// was updated on GitHub after they were queued.
+	Conflicts []Change
+	// Discarded lists the changes dropped with Discard.
+	Discarded []Change
+	// Failed lists the changes GitHub rejected, or that could not be sent.
+	// They stay in the outbox.
+	Failed []Change
+	// Fetched is the number of issues fetched from GitHub.
+	Fetched int
+}
+
+// Sync sends the changes waiting in the outbox to GitHub, in the order
+// they were made, then refreshes the store with the issues updated since
+// the last sync. A change is refused as a conflict when its issue was
+// updated on GitHub after the change was queued; once one change to an
+// issue is refused or rejected, the later ones wait behind it.
+func (c *Client) Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	if c.forced {
+		return nil, fmt.Errorf("%w: sync needs a connection to GitHub", ErrOffline)
+	}
+
+	res := &SyncResult{Created: make(map[int]int)}
+	if err := c.push(ctx, opts, res); err != nil {
+		return res, err
+	}
+	if err := c.pull(ctx, res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (c *Client) push(ctx context.Context, opts SyncOptions, res *SyncResult) error {
+	changes := c.store.Pending()
+
+	// updated holds the UpdatedAt of issues changed by this sync, which
+	// later changes to them are checked against instead of their base.
+	updated := make(map[int]time.Time)
+	// held holds the issues with a change that was not sent, and dropped
+	// those with a change that was discarded. Later changes to them go the
+	// same way.
+	held := make(map[int]bool)
+	dropped := make(map[int]bool)
+	var keep []Change
+
+	refuse := func(ch Change, list *[]Change) {
+		if opts.Discard {
+			dropped[ch.Number] = true
+			res.Discarded = append(res.Discarded, ch)
+			return
+		}
+		held[ch.Number] = true
+		*list = append(*list, ch)
+		keep = append(keep, ch)
+	}
+
+	for i, ch := range changes {
+		if number, ok := res.Created[ch.Number]; ok && IsDraft(ch.Number) {
+			ch.Number = number
+		}
+		ch.Conflict, ch.Error = "", ""
+
+		switch {
+		case dropped[ch.Number]:
+			res.Discarded = append(res.Discarded, ch)
+			continue
+		case held[ch.Number]:
+			keep = append(keep, ch)
+			continue
+		}
+
+		if ch.Kind != KindCreate && !opts.Force {
+			conflict, err := c.conflict(ctx, ch, updated)
+			if unreachable(err) {
+				return c.abort(keep, changes[i:], res, err)
+			}
+			if err != nil {
+				ch.Error = err.Error()
+				refuse(ch, &res.Failed)
+				continue
+			}
+			if conflict != "" {
+				ch.Conflict = conflict
+				refuse(ch, &res.Conflicts)
+				continue
+			}
+		}
+
+		issue, err := c.send(ctx, ch)
+		if notSent(err) {
+			return c.abort(keep, changes[i:], res, err)
+		}
+		if err != nil {
+			ch.Error = err.Error()
+			refuse(ch, &res.Failed)
+			continue
+		}
+
+		if ch.Kind == KindCreate {
+			res.Created[ch.Number] = issue.Number
+		}
+		updated[issue.Number] = issue.UpdatedAt
+		res.Sent = append(res.Sent, ch)
+	}
+
+	return c.store.setPending(keep)
+}
+
+// abort keeps the changes not yet sent when GitHub is lost mid-sync.
+func (c *Client) abort(keep, rest []Change, res *SyncResult, err error) error {
+	for _, ch := range rest {
+		if number, ok := res.Created[ch.Number]; ok && IsDraft(ch.Number) {
+			ch.Number = number
+		}
+		keep = append(keep, ch)
+	}
+	if serr := c.store.setPending(keep); serr != nil {
+		return serr
+	}
+	return fmt.Errorf("%w: sending changes: %w", ErrOffline, err)
+}
+
+// conflict describes how the change's issue was updated on GitHub after
+// the change was queued, or returns an empty string if it was not.
+func (c *Client) conflict(ctx context.Context, ch Change, updated map[int]time.Time) (string, error) {
+	issue, err := c.remote.GetIssue(ctx, ch.Number)
+	if err != nil {
+		return "", err
+	}
+	base, ok := updated[ch.Number]
+	if !ok {
+		base = ch.Base
+	}
+	if !issue.UpdatedAt.After(base) {
+		return "", nil
+	}
+	return fmt.Sprintf("#%d was updated on GitHub at %s, after this change was queued",
+		ch.Number, issue.UpdatedAt.Local().Format("2006-01-02 15:04")), nil
+}
+
+// send makes a change on GitHub and returns the issue as it then is.
+func (c *Client) send(ctx context.Context, ch Change) (*github.Issue, error) {
+	switch ch.Kind {
+	case KindCreate:
+		return c.remote.CreateIssue(ctx, *ch.Create)
+	case KindEdit:
+		return c.remote.UpdateIssue(ctx, ch.Number, ch.Edit.request())
+	case KindAssign:
+		return c.remote.AssignIssue(ctx, ch.Number, ch.Edit.request().Assignees)
+	case KindClose:
+		return c.remote.CloseIssue(ctx, ch.Number, github.CloseIssueRequest{Reason: ch.Reason})
+	case KindReopen:
+		return c.remote.ReopenIssue(ctx, ch.Number, "")
+	case KindComment:
+		comment, err := c.remote.AddComment(ctx, ch.Number, ch.Comment)
+		if err != nil {
+			return nil, err
+		}
+		// Commenting updates the issue. Once the comment is posted the
+		// change must not be sent again, so if the issue cannot be fetched
+		// the comment's time stands in for its update.
+		issue, err := c.remote.GetIssue(ctx, ch.Number)
+		if err != nil {
+			return &github.Issue{Number: ch.Number, UpdatedAt: comment.CreatedAt}, nil
+		}
+		return issue, nil
+	}
+	return nil, fmt.Errorf("unknown change kind %q", ch.Kind)
+}
+
+// pull fetches the issues updated since the last sync, or every issue on
+// the first, and the comments on those whose comments changed, along with
+// the labels and milestones.
+func (c *Client) pull(ctx context.Context, res *SyncResult) error {
+	req := github.ListIssuesRequest{State: "all", IncludePullRequests: true, Since: c.store.fetchedUpTo()}
+
+	var issues []github.Issue
+	for issue, err := range c.remote.ListAllIssues(ctx, req) {
+		if err != nil {
+			return fmt.Errorf("fetching issues: %w", err)
+		}
+		issues = append(issues, issue)
+	}
+	if err := c.store.putIssues(issues); err != nil {
+		return err
+	}
+	res.Fetched = len(issues)
+
+	// The comments fetched are kept even if fetching the rest fails, so
+	// the next sync carries on from there.
+	comments := make(map[int][]github.IssueComment)
+	var err error
+	for _, number := range c.store.staleComments() {
+		var list []github.IssueComment
+		if list, err = c.remote.ListComments(ctx, number); err != nil {
+			err = fmt.Errorf("fetching comments on #%d: %w", number, err)
+			break
+		}
+		comments[number] = list
+	}
+	if serr := c.store.putComments(comments); serr != nil {
+		return serr
+	}
+	if err != nil {
+		return err
+	}
+
+	labels, err := c.remote.ListLabels(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching labels: %w", err)
+	}
+	if err := c.store.putLabels(labels); err != nil {
+		return err
+	}
+	milestones, err := c.remote.ListMilestones(ctx, "all")
+	if err != nil {
+		return fmt.Errorf("fetching milestones: %w", err)
+	}
+	if err := c.store.putMilestones(milestones); err != nil {
+		return err
+	}
+
+	// Comments made offline are credited to the token's user. Tokens that
+	// cannot look themselves up, such as a GitHub App's, leave it blank.
+	var login string
+	if auth, ok := c.remote.(github.AuthClient); ok {
+		if info, err := auth.GetTokenInfo(ctx); err == nil {
+			login = info.Login
+		}
+	}
+	return c.store.synced(time.Now(), login, issues)
+}
@@ -0,0 +1,302 @@
+package offline_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/github/githubtest"
+	"github.com/dulait/grit/internal/offline"
+)
+
+// setup serves n issues and returns a store synced with them.
+func setup(t *testing.T, n int) (*githubtest.Server, *offline.Store) {
+	t.Helper()
+	srv := githubtest.NewServer()
+	t.Cleanup(srv.Close)
+	repo := srv.Repo("acme", "widgets")
+	for range n {
+		repo.AddIssue(github.Issue{Title: "issue"})
+	}
+
+	store, err := offline.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := online(srv, store).Sync(t.Context(), offline.SyncOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return srv, store
+}
+
+func online(srv *githubtest.Server, store *offline.Store) *offline.Client {
+	return offline.NewClient(srv.Client("acme", "widgets"), store, "acme/widgets", false)
+}
+
+func forced(srv *githubtest.Server, store *offline.Store) *offline.Client {
+	return offline.NewClient(srv.Client("acme", "widgets"), store, "acme/widgets", true)
+}
+
+func TestSyncSendsQueuedChanges(t *testing.T) {
+	srv, store := setup(t, 2)
+	client := forced(srv, store)
+
+	if _, err := client.CloseIssue(t.Context(), 1, github.CloseIssueRequest{Comment: "done"}); err != nil {
+		t.Fatal(err)
+	}
+	issue, err := client.GetIssue(t.Context(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.State != "closed" {
+		t.Errorf("stored state = %s, want the queued close applied", issue.State)
+	}
+	if got := len(store.Pending()); got != 2 {
+		t.Fatalf("%d changes queued, want the comment and the close", got)
+	}
+	for _, r := range srv.Requests() {
+		if r.Method != "GET" {
+			t.Fatalf("forced offline client sent %s %s", r.Method, r.Path)
+		}
+	}
+
+	if _, err := client.Sync(t.Context(), offline.SyncOptions{}); !errors.Is(err, offline.ErrOffline) {
+		t.Errorf("Sync while forced offline: err = %v, want ErrOffline", err)
+	}
+
+	res, err := online(srv, store).Sync(t.Context(), offline.SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Sent) != 2 || len(store.Pending()) != 0 {
+		t.Errorf("sent %d, %d left, want both sent", len(res.Sent), len(store.Pending()))
+	}
+	remote, _ := srv.Repo("acme", "widgets").Issue(1)
+	if remote.State != "closed" {
+		t.Errorf("state on GitHub = %s, want closed", remote.State)
+	}
+	if comments := srv.Repo("acme", "widgets").Comments(1); len(comments) != 1 || comments[0].Body != "done" {
+		t.Errorf("comments on GitHub = %+v, want the closing comment", comments)
+	}
+}
+
+func TestSyncRenumbersCreatedIssues(t *testing.T) {
+	srv, store := setup(t, 2)
+	client := forced(srv, store)
+
+	created, err := client.CreateIssue(t.Context(), github.CreateIssueRequest{Title: "offline"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !offline.IsDraft(created.Number) {
+		t.Fatalf("local number = %d, want a draft", created.Number)
+	}
+	if _, err := client.AddComment(t.Context(), created.Number, "follow-up"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Someone else takes #3 on GitHub before the sync.
+	srv.Repo("acme", "widgets").AddIssue(github.Issue{Title: "elsewhere"})
+
+	res, err := online(srv, store).Sync(t.Context(), offline.SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Created[created.Number] != 4 {
+		t.Fatalf("Created = %v, want %s to become #4", res.Created, offline.FormatNumber(created.Number))
+	}
+	repo := srv.Repo("acme", "widgets")
+	if issue, _ := repo.Issue(4); issue.Title != "offline" {
+		t.Errorf("#4 on GitHub is %q, want the issue created offline", issue.Title)
+	}
+	if got := len(repo.Comments(4)); got != 1 {
+		t.Errorf("#4 has %d comments, want the follow-up moved to it", got)
+	}
+	if got := len(repo.Comments(3)); got != 0 {
+		t.Errorf("#3 has %d comments, want none", got)
+	}
+}
+
+func TestDraftsKeepApartFromGitHubIssues(t *testing.T) {
+	srv, store := setup(t, 2)
+	draft, err := forced(srv, store).CreateIssue(t.Context(), github.CreateIssueRequest{Title: "offline"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// #3 is created on GitHub and GitHub rejects the draft, so the sync
+	// fetches #3 while the draft stays queued.
+	srv.Repo("acme", "widgets").AddIssue(github.Issue{Title: "elsewhere"})
+	srv.Fail(githubtest.Failure{Method: "POST", Path: "/repos/*/*/issues", Times: 1, Status: http.StatusUnprocessableEntity})
+	res, err := online(srv, store).Sync(t.Context(), offline.SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Failed) != 1 {
+		t.Fatalf("%d changes failed, want the draft", len(res.Failed))
+	}
+
+	client := forced(srv, store)
+	if issue, err := client.GetIssue(t.Context(), 3); err != nil || issue.Title != "elsewhere" {
+		t.Errorf("#3 = %+v, %v, want the issue from GitHub", issue, err)
+	}
+	if issue, err := client.GetIssue(t.Context(), draft.Number); err != nil || issue.Title != "offline" {
+		t.Errorf("%s = %+v, %v, want the draft", offline.FormatNumber(draft.Number), issue, err)
+	}
+	if _, err := client.AddComment(t.Context(), 3, "on #3"); err != nil {
+		t.Fatal(err)
+	}
+	pending := store.Pending()
+	if last := pending[len(pending)-1]; last.Number != 3 {
+		t.Errorf("comment queued for %s, want #3", offline.FormatNumber(last.Number))
+	}
+}
+
+func TestSyncConflicts(t *testing.T) {
+	title := "edited on GitHub"
+	tests := []struct {
+		name          string
+		opts          offline.SyncOptions
+		wantState     string
+		wantConflicts int
+		wantPending   int
+	}{
+		{"kept", offline.SyncOptions{}, "open", 1, 1},
+		{"force", offline.SyncOptions{Force: true}, "closed", 0, 0},
+		{"discard", offline.SyncOptions{Discard: true}, "open", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, store := setup(t, 1)
+			if _, err := forced(srv, store).CloseIssue(t.Context(), 1, github.CloseIssueRequest{}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := srv.Client("acme", "widgets").UpdateIssue(t.Context(), 1, github.UpdateIssueRequest{Title: &title}); err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := online(srv, store).Sync(t.Context(), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Conflicts) != tt.wantConflicts || len(store.Pending()) != tt.wantPending {
+				t.Errorf("%d conflicts, %d pending, want %d and %d",
+					len(res.Conflicts), len(store.Pending()), tt.wantConflicts, tt.wantPending)
+			}
+			if remote, _ := srv.Repo("acme", "widgets").Issue(1); remote.State != tt.wantState {
+				t.Errorf("state on GitHub = %s, want %s", remote.State, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestQueueWhenUnreachable(t *testing.T) {
+	srv, store := setup(t, 1)
+
+	down := githubtest.NewServer()
+	down.Close()
+	remote := github.NewHTTPClient("acme", "widgets", "", github.WithBaseURL(down.URL), github.WithMaxRetries(0))
+	client := offline.NewClient(remote, store, "acme/widgets", false)
+
+	issue, err := client.GetIssue(t.Context(), 1)
+	if err != nil || !client.ServedFromStore() {
+		t.Fatalf("GetIssue = %v, %v, want it served from the store", issue, err)
+	}
+	if _, err := client.AddComment(t.Context(), 1, "queued"); err != nil {
+		t.Fatal(err)
+	}
+	if client.Queued() != 1 {
+		t.Fatalf("queued %d changes, want 1", client.Queued())
+	}
+
+	if _, err := client.Sync(t.Context(), offline.SyncOptions{}); !errors.Is(err, offline.ErrOffline) {
+		t.Errorf("Sync with GitHub down: err = %v, want ErrOffline", err)
+	}
+	if got := len(store.Pending()); got != 1 {
+		t.Errorf("%d changes pending after a failed sync, want 1", got)
+	}
+
+	if _, err := online(srv, store).Sync(t.Context(), offline.SyncOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(srv.Repo("acme", "widgets").Comments(1)); got != 1 {
+		t.Errorf("#1 has %d comments, want the queued one", got)
+	}
+}
+
+func TestSyncFetchesUpdates(t *testing.T) {
+	srv, store := setup(t, 2)
+	title := "renamed"
+	if _, err := srv.Client("acme", "widgets").UpdateIssue(t.Context(), 2, github.UpdateIssueRequest{Title: &title}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := online(srv, store).Sync(t.Context(), offline.SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// since has a resolution of a second, so issues updated in the same
+	// second as the last sync are fetched again.
+	if res.Fetched == 0 {
+		t.Error("fetched no issues, want the one updated")
+	}
+	issue, err := forced(srv, store).GetIssue(t.Context(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Title != title {
+		t.Errorf("stored title = %q, want %q", issue.Title, title)
+	}
+}
+
+func TestReadsAreJournaled(t *testing.T) {
+	srv := githubtest.NewServer()
+	t.Cleanup(srv.Close)
+	repo := srv.Repo("acme", "widgets")
+	repo.AddIssue(github.Issue{Title: "issue"})
+
+	dir := t.TempDir()
+	store, err := offline.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := online(srv, store).Sync(t.Context(), offline.SyncOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(filepath.Join(dir, "issues.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.AddComment(1, "hubot", "new comment")
+	repo.AddLabel(github.Label{Name: "bug"})
+	client := online(srv, store)
+	if _, err := client.GetIssue(t.Context(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListComments(t.Context(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListLabels(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "issues.json")); !bytes.Equal(data, saved) {
+		t.Error("reads rewrote the issues file, want them journaled")
+	}
+
+	reopened, err := offline.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = forced(srv, reopened)
+	if comments, err := client.ListComments(t.Context(), 1); err != nil || len(comments) != 1 {
+		t.Errorf("comments after reopening = %+v, %v, want the one read", comments, err)
+	}
+	if labels, _ := client.ListLabels(t.Context()); len(labels) != 1 {
+		t.Errorf("labels after reopening = %+v, want the one read", labels)
+	}
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+)
+
+const (
+	// assigneeCacheFile is the name of the cached assignable users file.
+	assigneeCacheFile = "assignees.json"
+	// assigneeCacheTTL is how long the cached list is used before it is
+	// fetched again. Logins missing from it are always checked against a
+	// fresh list, so new collaborators are found straight away.
+	assigneeCacheTTL = time.Hour
+	// maxAssigneeSuggestions bounds the "did you mean" suggestions given
+	// for an unknown login.
+	maxAssigneeSuggestions = 3
+)
+
+// AssigneeService looks up the users who can be assigned to issues in the
+// repository, caching them on disk between runs.
+type AssigneeService struct {
+	github github.Client
+	cfg    *config.Config
+}
+
+func NewAssigneeService(ghClient github.Client, cfg *config.Config) *AssigneeService {
+	return &AssigneeService{
+		github: ghClient,
+		cfg:    cfg,
+	}
+}
+
+type assigneeCache struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Logins    []string  `json:"logins"`
+}
+
+// List returns the logins of the repository's assignable users, sorted
+// case-insensitively. A cached list is used while it is fresh.
+func (s *AssigneeService) List(ctx context.Context) ([]string, error) {
+	logins, _, err := s.list(ctx)
+	return logins, err
+}
+
+// list returns the assignable logins and whether they came from the cache.
+func (s *AssigneeService) list(ctx context.Context) ([]string, bool, error) {
+	if cache, ok := s.readCache(); ok && time.Since(cache.FetchedAt) < assigneeCacheTTL {
+		return cache.Logins, true, nil
+	}
+	logins, err := s.Refresh(ctx)
+	return logins, false, err
+}
+
+// Refresh fetches the assignable users from GitHub and updates the cache.
+func (s *AssigneeService) Refresh(ctx context.Context) ([]string, error) {
+	users, err := s.github.ListAssignees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing assignable users: %w", err)
+	}
+
+	logins := make([]string, len(users))
+	for i, u := range users {
+		logins[i] = u.Login
+	}
+	sort.Slice(logins, func(i, j int) bool {
+		return strings.ToLower(logins[i]) < strings.ToLower(logins[j])
+	})
+
+	s.writeCache(assigneeCache{FetchedAt: time.Now(), Logins: logins})
+	return logins, nil
+}
+
+// readCache returns the cached list, if there is a readable one.
+func (s *AssigneeService) readCache() (assigneeCache, bool) {
+	var cache assigneeCache
+	path, err := s.cfg.Project.RepoCachePath(assigneeCacheFile)
+	if err != nil {
+		return cache, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache, false
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cache, false
+	}
+	return cache, true
+}
+
+// writeCache saves the list. The cache only saves requests, so failing to
+// write it is not an error.
+func (s *AssigneeService) writeCache(cache assigneeCache) {
+	path, err := s.cfg.Project.RepoCachePath(assigneeCacheFile)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
+
+// UnknownAssignee is a login that cannot be assigned, with the assignable
+// logins closest to it.
+type UnknownAssignee struct {
+	Login       string
+	Suggestions []string
+}
+
+// UnknownAssigneesError is returned by Validate when some logins cannot be
+// assigned in the repository.
+type UnknownAssigneesError struct {
+	Repo    string
+	Unknown []UnknownAssignee
+}
+
+func (e *UnknownAssigneesError) Error() string {
+	parts := make([]string, len(e.Unknown))
+	for i, u := range e.Unknown {
+		parts[i] = fmt.Sprintf("%q cannot be assigned in %s", u.Login, e.Repo)
+		if len(u.Suggestions) > 0 {
+			parts[i] += fmt.Sprintf("; did you mean %s?", quoteJoin(u.Suggestions, " or "))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func quoteJoin(items []string, sep string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	return strings.Join(quoted, sep)
+}
+
+// Validate checks logins against the repository's assignable users and
+// returns them as GitHub spells them, without any leading @. Logins missing
+// from a cached list are checked again against a fresh one. When some are
+// still unknown, it returns an *UnknownAssigneesError with suggestions.
+func (s *AssigneeService) Validate(ctx context.Context, logins []string) ([]string, error) {
+	if len(logins) == 0 {
+		return logins, nil
+	}
+
+	assignable, cached, err := s.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, unknown := resolveLogins(logins, assignable)
+	if len(unknown) > 0 && cached {
+		if assignable, err = s.Refresh(ctx); err != nil {
+			return nil, err
+		}
+		resolved, unknown = resolveLogins(logins, assignable)
+	}
+	if len(unknown) == 0 {
+		return resolved, nil
+	}
+
+	verr := &UnknownAssigneesError{Repo: s.cfg.Project.FullName()}
+	for _, login := range unknown {
+		verr.Unknown = append(verr.Unknown, UnknownAssignee{
+			Login:       login,
+			Suggestions: SuggestAssignees(login, assignable, maxAssigneeSuggestions),
+		})
+	}
+	return nil, verr
+}
+
+// resolveLogins matches logins case-insensitively against assignable and
+// returns the matched spellings and the logins that did not match.
+func resolveLogins(logins, assignable []string) (resolved, unknown []string) {
+	byLower := make(map[string]string, len(assignable))
+	for _, a := range assignable {
+		byLower[strings.ToLower(a)] = a
+	}
+	for _, login := range logins {
+		login = strings.TrimPrefix(strings.TrimSpace(login), "@")
+		if match, ok := byLower[strings.ToLower(login)]; ok {
+			resolved = append(resolved, match)
+		} else {
+			unknown = append(unknown, login)
+		}
+	}
+	return resolved, unknown
+}
+
+// Match ranks, from best to worst.
+const (
+	matchPrefix = iota
+	matchSubstring
+	matchFuzzy
+	noMatch
+)
+
+// SuggestAssignees returns up to limit logins from candidates that match
+// query, best first: logins starting with it, then containing it, then
+// those containing its characters in order or within a couple of typos of
+// it, closest first. Matching ignores case and a leading @.
+func SuggestAssignees(query string, candidates []string, limit int) []string {
+	query = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+	if query == "" {
+		return nil
+	}
+
+	type scored struct {
+		login string
+		rank  int
+		dist  int
+	}
+	var matches []scored
+	for _, c := range candidates {
+		rank, dist := matchLogin(query, strings.ToLower(c))
+		if rank != noMatch {
+			matches = append(matches, scored{c, rank, dist})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.dist != b.dist {
+			return a.dist < b.dist
+		}
+		return strings.ToLower(a.login) < strings.ToLower(b.login)
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	logins := make([]string, len(matches))
+	for i, m := range matches {
+		logins[i] = m.login
+	}
+	return logins
+}
+
+// matchLogin returns how well query matches login, both lowercase, and a
+// distance used to order matches of the same rank.
+func matchLogin(query, login string) (rank, dist int) {
+	switch {
+	case strings.HasPrefix(login, query):
+		return matchPrefix, len(login) - len(query)
+	case strings.Contains(login, query):
+		return matchSubstring, len(login) - len(query)
+	}
+
+	d := editDistance(query, login)
+	if isSubsequence(query, login) || d <= max(1, len(query)/3) {
+		return matchFuzzy, d
+	}
+	return noMatch, 0
+}
+
+// isSubsequence reports whether the characters of query appear in s in
+// order.
+func isSubsequence(query, s string) bool {
+	i := 0
+	for j := 0; i < len(query) && j < len(s); j++ {
+		if query[i] == s[j] {
+			i++
+		}
+	}
+	return i == len(query)
+}
+
+// editDistance returns the number of insertions, deletions, substitutions
+// and swaps of adjacent characters needed to turn a into b.
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
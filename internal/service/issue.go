@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"slices"
+	"strings"
 
 	"github.com/dulait/grit/internal/config"
 	"github.com/dulait/grit/internal/github"
@@ -16,6 +20,15 @@ type IssueInput struct {
 	Description string
 	Labels      []string
 	Assignees   []string
+	// Template, when set, shapes the generated body. See ApplyTemplate.
+	Template *IssueTemplate
+}
+
+// CreateOptions holds the issue fields that are not generated by the LLM.
+type CreateOptions struct {
+	Assignees []string
+	// Milestone is a milestone number or title.
+	Milestone string
 }
 
 type EditIssueInput struct {
@@ -26,6 +39,9 @@ type EditIssueInput struct {
 	Assignees    []string
 	SetLabels    bool
 	SetAssignees bool
+	// Milestone is a milestone number or title. An empty string or "none"
+	// removes the milestone.
+	Milestone *string
 }
 
 // IssueService provides operations for managing GitHub issues.
@@ -46,6 +62,21 @@ func NewIssueService(ghClient github.Client, llmClient llm.Client, cfg *config.C
 
 // GenerateIssue creates issue content, optionally using LLM enhancement.
 func (s *IssueService) GenerateIssue(ctx context.Context, input IssueInput, enhance bool) (*llm.GeneratedIssue, error) {
+	issue, err := s.generateIssue(ctx, input, enhance)
+	if err != nil {
+		return nil, err
+	}
+	if input.Template != nil {
+		issue.Title = input.Template.applyTitle(issue.Title)
+	}
+	return issue, nil
+}
+
+func (s *IssueService) generateIssue(ctx context.Context, input IssueInput, enhance bool) (*llm.GeneratedIssue, error) {
+	if (!enhance || s.llm == nil) && input.Description == "" && input.Template != nil {
+		input.Description = input.Template.Body
+	}
+
 	if !enhance && input.Title != "" && input.Description != "" {
 		return &llm.GeneratedIssue{
 			Title:  input.Title,
@@ -68,10 +99,13 @@ func (s *IssueService) GenerateIssue(ctx context.Context, input IssueInput, enha
 		DescriptionHint: input.Description,
 		RepoContext:     fmt.Sprintf("%s/%s", s.cfg.Project.Owner, s.cfg.Project.Repo),
 		IssuePrefix:     s.cfg.Project.IssuePrefix,
-		AllowedLabels:   s.cfg.Project.Labels,
+		AllowedLabels:   s.cfg.Project.LabelNames(),
 		GenerateTitle:   input.Title == "",
 		GenerateBody:    true,
-		SuggestLabels:   len(input.Labels) == 0 && len(s.cfg.Project.Labels) > 0,
+		SuggestLabels:   len(input.Labels) == 0 && len(s.cfg.Project.LabelNames()) > 0,
+	}
+	if input.Template != nil {
+		req.Template = input.Template.Outline()
 	}
 
 	issue, err := s.llm.GenerateIssue(ctx, req)
@@ -102,6 +136,16 @@ func (s *IssueService) EditIssue(ctx context.Context, number int, input EditIssu
 	if input.SetAssignees {
 		req.Assignees = input.Assignees
 	}
+	if input.Milestone != nil {
+		req.Milestone = new(int)
+		if ref := *input.Milestone; ref != "" && !strings.EqualFold(ref, "none") {
+			m, err := s.ResolveMilestone(ctx, ref)
+			if err != nil {
+				return nil, err
+			}
+			*req.Milestone = m.Number
+		}
+	}
 
 	issue, err := s.github.UpdateIssue(ctx, number, req)
 	if err != nil {
@@ -111,12 +155,10 @@ func (s *IssueService) EditIssue(ctx context.Context, number int, input EditIssu
 }
 
 // CreateIssue posts a new issue to GitHub.
-func (s *IssueService) CreateIssue(ctx context.Context, issue *llm.GeneratedIssue, assignees []string) (*github.Issue, error) {
-	req := github.CreateIssueRequest{
-		Title:     issue.Title,
-		Body:      issue.Body,
-		Labels:    issue.Labels,
-		Assignees: assignees,
+func (s *IssueService) CreateIssue(ctx context.Context, issue *llm.GeneratedIssue, opts CreateOptions) (*github.Issue, error) {
+	req, err := s.createRequest(ctx, issue, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	created, err := s.github.CreateIssue(ctx, req)
@@ -127,14 +169,52 @@ func (s *IssueService) CreateIssue(ctx context.Context, issue *llm.GeneratedIssu
 	return created, nil
 }
 
-func (s *IssueService) ListIssues(ctx context.Context, req github.ListIssuesRequest) ([]github.Issue, error) {
-	issues, err := s.github.ListIssues(ctx, req)
+func (s *IssueService) createRequest(ctx context.Context, issue *llm.GeneratedIssue, opts CreateOptions) (github.CreateIssueRequest, error) {
+	req := github.CreateIssueRequest{
+		Title:     issue.Title,
+		Body:      issue.Body,
+		Labels:    issue.Labels,
+		Assignees: opts.Assignees,
+	}
+	if opts.Milestone != "" {
+		m, err := s.ResolveMilestone(ctx, opts.Milestone)
+		if err != nil {
+			return req, err
+		}
+		req.Milestone = m.Number
+	}
+	return req, nil
+}
+
+// ResolveMilestone finds a milestone by number or, failing that, by title.
+// Titles are matched without regard to case.
+func (s *IssueService) ResolveMilestone(ctx context.Context, ref string) (*github.Milestone, error) {
+	return findMilestone(ctx, s.github, ref)
+}
+
+// IssuePage is one page of an issue listing.
+type IssuePage struct {
+	Issues []github.Issue
+	// HasNext reports whether GitHub has a next page. It may hold only
+	// pull requests.
+	HasNext bool
+}
+
+// ListIssues returns one page of issues. Pull requests are left out unless
+// req.IncludePullRequests is set, so a page may hold fewer than
+// req.PerPage issues.
+func (s *IssueService) ListIssues(ctx context.Context, req github.ListIssuesRequest) (*IssuePage, error) {
+	resp, err := s.github.ListIssues(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("listing issues: %w", err)
 	}
-	return issues, nil
-}
 
+	page := &IssuePage{Issues: resp.Issues, HasNext: resp.HasNext}
+	if !req.IncludePullRequests {
+		page.Issues = slices.DeleteFunc(resp.Issues, github.Issue.IsPullRequest)
+	}
+	return page, nil
+}
 
 func (s *IssueService) SearchIssues(ctx context.Context, req github.SearchIssuesRequest) (*github.SearchIssuesResponse, error) {
 	resp, err := s.github.SearchIssues(ctx, req)
@@ -144,14 +224,91 @@ func (s *IssueService) SearchIssues(ctx context.Context, req github.SearchIssues
 	return resp, nil
 }
 
-func (s *IssueService) CloseIssue(ctx context.Context, number int, comment string) (*github.Issue, error) {
-	closed, err := s.github.CloseIssue(ctx, number, comment)
+// IterateIssues streams every issue matching req across all pages.
+func (s *IssueService) IterateIssues(ctx context.Context, req github.ListIssuesRequest) iter.Seq2[github.Issue, error] {
+	issues := wrapSeqErr(s.github.ListAllIssues(ctx, req), "listing issues")
+	if req.IncludePullRequests {
+		return issues
+	}
+	return func(yield func(github.Issue, error) bool) {
+		for issue, err := range issues {
+			if err == nil && issue.IsPullRequest() {
+				continue
+			}
+			if !yield(issue, err) {
+				return
+			}
+		}
+	}
+}
+
+// IterateSearch streams every search result across all pages.
+func (s *IssueService) IterateSearch(ctx context.Context, req github.SearchIssuesRequest) iter.Seq2[github.Issue, error] {
+	return wrapSeqErr(s.github.SearchAllIssues(ctx, req), "searching issues")
+}
+
+func wrapSeqErr[T any](seq iter.Seq2[T, error], op string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for v, err := range seq {
+			if err != nil {
+				err = fmt.Errorf("%s: %w", op, err)
+			}
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+// CloseOptions controls how an issue is closed.
+type CloseOptions struct {
+	Comment string
+	// Reason is completed, not_planned or duplicate. Empty means completed.
+	Reason string
+	// DuplicateOf closes the issue as a duplicate of this issue, which may
+	// be in another repository.
+	DuplicateOf github.IssueRef
+	// CopyLabels adds the duplicate's labels to the canonical issue.
+	CopyLabels bool
+}
+
+// CloseIssue closes an issue with the given reason. When opts.DuplicateOf is
+// set the issue is marked as a duplicate of that issue. If the issue was
+// closed but its labels could not be copied, the closed issue is returned
+// along with the error.
+func (s *IssueService) CloseIssue(ctx context.Context, number int, opts CloseOptions) (*github.Issue, error) {
+	switch opts.Reason {
+	case "", github.StateReasonCompleted, github.StateReasonNotPlanned, github.StateReasonDuplicate:
+	default:
+		return nil, fmt.Errorf("invalid close reason %q: use completed, not_planned or duplicate", opts.Reason)
+	}
+
+	if opts.DuplicateOf.Number != 0 {
+		return s.closeAsDuplicate(ctx, number, opts)
+	}
+	if opts.CopyLabels {
+		return nil, fmt.Errorf("copying labels requires a canonical issue")
+	}
+
+	closed, err := s.github.CloseIssue(ctx, number, github.CloseIssueRequest{
+		Comment: opts.Comment,
+		Reason:  opts.Reason,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("closing issue: %w", err)
 	}
 	return closed, nil
 }
 
+// ReopenIssue reopens a closed issue, optionally posting a comment first.
+func (s *IssueService) ReopenIssue(ctx context.Context, number int, comment string) (*github.Issue, error) {
+	reopened, err := s.github.ReopenIssue(ctx, number, comment)
+	if err != nil {
+		return nil, fmt.Errorf("reopening issue: %w", err)
+	}
+	return reopened, nil
+}
+
 // AddComment generates and posts a comment using the LLM.
 func (s *IssueService) AddComment(ctx context.Context, number int, userPrompt string) (*github.IssueComment, error) {
 	if s.llm == nil {
@@ -178,6 +335,32 @@ func (s *IssueService) AddComment(ctx context.Context, number int, userPrompt st
 	return comment, nil
 }
 
+// ListComments returns the comment thread of an issue.
+func (s *IssueService) ListComments(ctx context.Context, number int) ([]github.IssueComment, error) {
+	comments, err := s.github.ListComments(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("listing comments: %w", err)
+	}
+	return comments, nil
+}
+
+// UpdateComment replaces the body of an existing comment.
+func (s *IssueService) UpdateComment(ctx context.Context, id int64, body string) (*github.IssueComment, error) {
+	comment, err := s.github.UpdateComment(ctx, id, body)
+	if err != nil {
+		return nil, fmt.Errorf("updating comment: %w", err)
+	}
+	return comment, nil
+}
+
+// DeleteComment removes a comment.
+func (s *IssueService) DeleteComment(ctx context.Context, id int64) error {
+	if err := s.github.DeleteComment(ctx, id); err != nil {
+		return fmt.Errorf("deleting comment: %w", err)
+	}
+	return nil
+}
+
 // AssignIssue assigns users to an issue.
 func (s *IssueService) AssignIssue(ctx context.Context, number int, assignees []string) (*github.Issue, error) {
 	issue, err := s.github.AssignIssue(ctx, number, assignees)
@@ -187,20 +370,76 @@ func (s *IssueService) AssignIssue(ctx context.Context, number int, assignees []
 	return issue, nil
 }
 
-// LinkIssue creates a relationship between two issues via comment.
-func (s *IssueService) LinkIssue(ctx context.Context, number, targetNumber int, linkType string) error {
-	linkText := fmt.Sprintf("Related to #%d", targetNumber)
+// Link types accepted by LinkIssue and UnlinkIssue.
+const (
+	LinkRelated    = "related"
+	LinkBlocks     = "blocks"
+	LinkBlockedBy  = "blocked-by"
+	LinkDuplicates = "duplicates"
+	LinkParent     = "parent"
+	LinkChild      = "child"
+)
+
+// IssueRelationships describes the native relationships of an issue.
+type IssueRelationships struct {
+	Parent    *github.Issue
+	SubIssues []github.Issue
+	BlockedBy []github.Issue
+	Blocking  []github.Issue
+}
+
+// LinkIssue creates a relationship between two issues using GitHub's native
+// APIs: issue dependencies for blocks/blocked-by, sub-issues for
+// parent/child, and close-as-duplicate for duplicates. Related issues have
+// no native representation and are always linked by comment. The target
+// may be in another repository.
+func (s *IssueService) LinkIssue(ctx context.Context, number int, target github.IssueRef, linkType string) error {
+	issue := github.IssueRef{Number: number}
+	var err error
+	switch linkType {
+	case LinkRelated:
+		return s.LinkIssueByComment(ctx, number, target, linkType)
+	case LinkBlocks:
+		_, err = s.github.AddBlockedBy(ctx, target, issue)
+	case LinkBlockedBy:
+		_, err = s.github.AddBlockedBy(ctx, issue, target)
+	case LinkDuplicates:
+		return s.CloseAsDuplicate(ctx, number, target)
+	case LinkParent:
+		_, err = s.AttachSubIssue(ctx, issue, target)
+		return err
+	case LinkChild:
+		_, err = s.AttachSubIssue(ctx, target, issue)
+		return err
+	default:
+		return fmt.Errorf("unknown link type: %s", linkType)
+	}
+
+	if errors.Is(err, github.ErrNotSupported) {
+		return fmt.Errorf("linking issues: %w; use --comment to link by comment instead", err)
+	}
+	if err != nil {
+		return fmt.Errorf("linking issues: %w", err)
+	}
+	return nil
+}
+
+// LinkIssueByComment records a relationship as a plain comment. GitHub
+// cannot query these links, so this is only a fallback for servers without
+// the native APIs.
+func (s *IssueService) LinkIssueByComment(ctx context.Context, number int, target github.IssueRef, linkType string) error {
+	linkText := fmt.Sprintf("Related to %s", target)
 	switch linkType {
-	case "blocks":
-		linkText = fmt.Sprintf("Blocks #%d", targetNumber)
-	case "blocked-by":
-		linkText = fmt.Sprintf("Blocked by #%d", targetNumber)
-	case "duplicates":
-		linkText = fmt.Sprintf("Duplicates #%d", targetNumber)
-	case "parent":
-		linkText = fmt.Sprintf("Parent of #%d", targetNumber)
-	case "child":
-		linkText = fmt.Sprintf("Child of #%d", targetNumber)
+	case LinkBlocks:
+		linkText = fmt.Sprintf("Blocks %s", target)
+	case LinkBlockedBy:
+		linkText = fmt.Sprintf("Blocked by %s", target)
+	case LinkDuplicates:
+		linkText = fmt.Sprintf("Duplicates %s", target)
+	case LinkParent:
+		linkText = fmt.Sprintf("Parent of %s", target)
+	case LinkChild:
+		linkText = fmt.Sprintf("Child of %s", target)
 	}
 
 	_, err := s.github.AddComment(ctx, number, linkText)
@@ -211,15 +450,147 @@ func (s *IssueService) LinkIssue(ctx context.Context, number, targetNumber int,
 	return nil
 }
 
-// CreateSubIssue creates an issue linked to a parent issue.
-func (s *IssueService) CreateSubIssue(ctx context.Context, parentNumber int, generated *llm.GeneratedIssue, assignees []string) (*github.Issue, error) {
-	body := fmt.Sprintf("Part of #%d\n\n---\n\n%s", parentNumber, generated.Body)
+// UnlinkIssue removes a native relationship created by LinkIssue.
+func (s *IssueService) UnlinkIssue(ctx context.Context, number int, target github.IssueRef, linkType string) error {
+	issue := github.IssueRef{Number: number}
+	var err error
+	switch linkType {
+	case LinkBlocks:
+		_, err = s.github.RemoveBlockedBy(ctx, target, issue)
+	case LinkBlockedBy:
+		_, err = s.github.RemoveBlockedBy(ctx, issue, target)
+	case LinkParent:
+		_, err = s.DetachSubIssue(ctx, issue, target)
+		return err
+	case LinkChild:
+		_, err = s.DetachSubIssue(ctx, target, issue)
+		return err
+	default:
+		return fmt.Errorf("cannot unlink %s relationships", linkType)
+	}
 
-	req := github.CreateIssueRequest{
-		Title:     generated.Title,
-		Body:      body,
-		Labels:    generated.Labels,
-		Assignees: assignees,
+	if err != nil {
+		return fmt.Errorf("unlinking issues: %w", err)
+	}
+	return nil
+}
+
+// CloseAsDuplicate closes number as a duplicate of canonical.
+func (s *IssueService) CloseAsDuplicate(ctx context.Context, number int, canonical github.IssueRef) error {
+	_, err := s.CloseIssue(ctx, number, CloseOptions{DuplicateOf: canonical})
+	return err
+}
+
+// closeAsDuplicate posts a "Duplicate of #N" comment, which is what GitHub
+// uses to mark the issue as a duplicate and link it to the canonical issue,
+// then closes it with the duplicate reason.
+func (s *IssueService) closeAsDuplicate(ctx context.Context, number int, opts CloseOptions) (*github.Issue, error) {
+	canonical := opts.DuplicateOf
+	if canonical.Repo == "" && number == canonical.Number {
+		return nil, fmt.Errorf("an issue cannot duplicate itself")
+	}
+	if opts.Reason != "" && opts.Reason != github.StateReasonDuplicate {
+		return nil, fmt.Errorf("an issue closed as a duplicate must use the duplicate reason")
+	}
+	if opts.CopyLabels && canonical.Repo != "" {
+		return nil, fmt.Errorf("labels can only be copied to a canonical issue in the same repository")
+	}
+
+	target, err := s.github.GetIssueByRef(ctx, canonical)
+	if err != nil {
+		return nil, fmt.Errorf("fetching canonical issue: %w", err)
+	}
+
+	comment := fmt.Sprintf("Duplicate of %s", canonical)
+	if opts.Comment != "" {
+		comment += "\n\n" + opts.Comment
+	}
+
+	closed, err := s.github.CloseIssue(ctx, number, github.CloseIssueRequest{
+		Comment: comment,
+		Reason:  github.StateReasonDuplicate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("closing as duplicate: %w", err)
+	}
+
+	if opts.CopyLabels {
+		if err := s.copyLabels(ctx, closed, target); err != nil {
+			return closed, err
+		}
+	}
+	return closed, nil
+}
+
+// copyLabels adds the labels of from that to is missing.
+func (s *IssueService) copyLabels(ctx context.Context, from, to *github.Issue) error {
+	have := make(map[string]bool, len(to.Labels))
+	labels := make([]string, 0, len(to.Labels)+len(from.Labels))
+	for _, l := range to.Labels {
+		have[strings.ToLower(l.Name)] = true
+		labels = append(labels, l.Name)
+	}
+
+	added := false
+	for _, l := range from.Labels {
+		if !have[strings.ToLower(l.Name)] {
+			labels = append(labels, l.Name)
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+
+	if _, err := s.github.UpdateIssue(ctx, to.Number, github.UpdateIssueRequest{Labels: labels}); err != nil {
+		return fmt.Errorf("copying labels to #%d: %w", to.Number, err)
+	}
+	return nil
+}
+
+// LinkedPullRequests returns the pull requests that close or mention an
+// issue, with those that close it first.
+func (s *IssueService) LinkedPullRequests(ctx context.Context, number int) ([]github.LinkedPullRequest, error) {
+	prs, err := s.github.ListLinkedPullRequests(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("listing linked pull requests: %w", err)
+	}
+	return prs, nil
+}
+
+// Relationships returns the parent, sub-issues and dependencies of an issue.
+// Relationships the server cannot report are left empty.
+func (s *IssueService) Relationships(ctx context.Context, number int) (*IssueRelationships, error) {
+	var rel IssueRelationships
+	var nf *github.NotFoundError
+
+	parent, err := s.github.GetParentIssue(ctx, number)
+	switch {
+	case err == nil:
+		rel.Parent = parent
+	case !errors.As(err, &nf):
+		return nil, fmt.Errorf("fetching parent issue: %w", err)
+	}
+
+	if rel.SubIssues, err = s.github.ListSubIssues(ctx, number); err != nil && !errors.Is(err, github.ErrNotSupported) {
+		return nil, fmt.Errorf("listing sub-issues: %w", err)
+	}
+	if rel.BlockedBy, err = s.github.ListBlockedBy(ctx, number); err != nil && !errors.Is(err, github.ErrNotSupported) {
+		return nil, fmt.Errorf("listing blocking issues: %w", err)
+	}
+	if rel.Blocking, err = s.github.ListBlocking(ctx, number); err != nil && !errors.Is(err, github.ErrNotSupported) {
+		return nil, fmt.Errorf("listing blocked issues: %w", err)
+	}
+
+	return &rel, nil
+}
+
+// CreateSubIssue creates an issue and attaches it to a parent issue. Servers
+// without the sub-issues API get a "Part of #N" reference in the body instead.
+func (s *IssueService) CreateSubIssue(ctx context.Context, parentNumber int, generated *llm.GeneratedIssue, opts CreateOptions) (*github.Issue, error) {
+	req, err := s.createRequest(ctx, generated, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	created, err := s.github.CreateIssue(ctx, req)
@@ -227,5 +598,87 @@ func (s *IssueService) CreateSubIssue(ctx context.Context, parentNumber int, gen
 		return nil, fmt.Errorf("creating sub-issue: %w", err)
 	}
 
+	parent, child := github.IssueRef{Number: parentNumber}, github.IssueRef{Number: created.Number}
+	if _, err := s.AttachSubIssue(ctx, parent, child); err != nil {
+		return created, err
+	}
+
 	return created, nil
 }
+
+// AttachSubIssue makes child a sub-issue of parent. It reports whether the
+// native sub-issues API was used; when the server lacks it, the child's body
+// is prefixed with a "Part of #N" reference instead, which needs the child
+// to be in the client's repository.
+func (s *IssueService) AttachSubIssue(ctx context.Context, parent, child github.IssueRef) (bool, error) {
+	_, err := s.github.AddSubIssue(ctx, parent, child)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, github.ErrNotSupported) || child.Repo != "" {
+		return false, fmt.Errorf("adding sub-issue: %w", err)
+	}
+
+	issue, err := s.github.GetIssue(ctx, child.Number)
+	if err != nil {
+		return false, fmt.Errorf("fetching sub-issue: %w", err)
+	}
+
+	ref := parentRef(parent)
+	if strings.HasPrefix(issue.Body, ref) {
+		return false, nil
+	}
+
+	body := ref + issue.Body
+	if _, err := s.github.UpdateIssue(ctx, child.Number, github.UpdateIssueRequest{Body: &body}); err != nil {
+		return false, fmt.Errorf("adding parent reference: %w", err)
+	}
+	return false, nil
+}
+
+// DetachSubIssue removes child from parent, falling back to stripping the
+// "Part of #N" reference on servers without the sub-issues API.
+func (s *IssueService) DetachSubIssue(ctx context.Context, parent, child github.IssueRef) (bool, error) {
+	_, err := s.github.RemoveSubIssue(ctx, parent, child)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, github.ErrNotSupported) || child.Repo != "" {
+		return false, fmt.Errorf("removing sub-issue: %w", err)
+	}
+
+	issue, err := s.github.GetIssue(ctx, child.Number)
+	if err != nil {
+		return false, fmt.Errorf("fetching sub-issue: %w", err)
+	}
+
+	body, found := strings.CutPrefix(issue.Body, parentRef(parent))
+	if !found {
+		return false, fmt.Errorf("%s is not a sub-issue of %s", child, parent)
+	}
+	if _, err := s.github.UpdateIssue(ctx, child.Number, github.UpdateIssueRequest{Body: &body}); err != nil {
+		return false, fmt.Errorf("removing parent reference: %w", err)
+	}
+	return false, nil
+}
+
+// ReorderSubIssue moves child within its parent's sub-issue list.
+func (s *IssueService) ReorderSubIssue(ctx context.Context, parent int, child github.IssueRef, pos github.SubIssuePosition) error {
+	if _, err := s.github.ReprioritizeSubIssue(ctx, parent, child, pos); err != nil {
+		return fmt.Errorf("reordering sub-issue: %w", err)
+	}
+	return nil
+}
+
+// ListSubIssues returns the sub-issues of parent in priority order.
+func (s *IssueService) ListSubIssues(ctx context.Context, parent int) ([]github.Issue, error) {
+	issues, err := s.github.ListSubIssues(ctx, parent)
+	if err != nil {
+		return nil, fmt.Errorf("listing sub-issues: %w", err)
+	}
+	return issues, nil
+}
+
+func parentRef(parent github.IssueRef) string {
+	return fmt.Sprintf("Part of %s\n\n---\n\n", parent)
+}
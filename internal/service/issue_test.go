@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/github/githubtest"
+	"github.com/dulait/grit/internal/service"
+)
+
+// newIssueService serves issues numbered 1 to n, of which those in prs are
+// pull requests.
+func newIssueService(t *testing.T, n int, prs ...int) (*service.IssueService, *githubtest.Repo) {
+	t.Helper()
+	srv := githubtest.NewServer()
+	t.Cleanup(srv.Close)
+	repo := srv.Repo("acme", "widgets")
+	for i := 1; i <= n; i++ {
+		issue := github.Issue{Title: "issue"}
+		for _, pr := range prs {
+			if pr == i {
+				issue.PullRequest = &github.PullRequestRef{}
+			}
+		}
+		repo.AddIssue(issue)
+	}
+	return service.NewIssueService(srv.Client("acme", "widgets"), nil, &config.Config{}), repo
+}
+
+func TestListIssuesDropsPullRequests(t *testing.T) {
+	// Issues are listed newest first, so the first page of three holds #6,
+	// #5 and #4, of which #5 is a pull request.
+	svc, _ := newIssueService(t, 6, 5)
+
+	page, err := svc.ListIssues(t.Context(), github.ListIssuesRequest{PerPage: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Issues) != 2 || page.Issues[0].Number != 6 || page.Issues[1].Number != 4 {
+		t.Errorf("issues = %v, want #6 and #4", numbers(page.Issues))
+	}
+	if !page.HasNext {
+		t.Error("HasNext = false for a page short only of a pull request")
+	}
+
+	page, err = svc.ListIssues(t.Context(), github.ListIssuesRequest{PerPage: 3, Page: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Issues) != 3 || page.HasNext {
+		t.Errorf("last page: issues %v, HasNext %v", numbers(page.Issues), page.HasNext)
+	}
+
+	page, err = svc.ListIssues(t.Context(), github.ListIssuesRequest{PerPage: 3, IncludePullRequests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Issues) != 3 {
+		t.Errorf("with pull requests: issues %v, want 3", numbers(page.Issues))
+	}
+}
+
+func TestIterateIssues(t *testing.T) {
+	svc, _ := newIssueService(t, 7, 2, 3)
+
+	var got []github.Issue
+	for issue, err := range svc.IterateIssues(t.Context(), github.ListIssuesRequest{PerPage: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, issue)
+	}
+	if len(got) != 5 {
+		t.Errorf("issues = %v, want the 5 that are not pull requests", numbers(got))
+	}
+}
+
+func TestIssueNotFound(t *testing.T) {
+	svc, _ := newIssueService(t, 1)
+
+	_, err := svc.CloseIssue(t.Context(), 9, service.CloseOptions{})
+	var notFound *github.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("err = %v, want a NotFoundError", err)
+	}
+}
+
+func TestEditIssueResolvesMilestoneTitle(t *testing.T) {
+	svc, repo := newIssueService(t, 1)
+	m := repo.AddMilestone(github.Milestone{Title: "v1.0"})
+
+	title := "V1.0"
+	if _, err := svc.EditIssue(t.Context(), 1, service.EditIssueInput{Milestone: &title}); err != nil {
+		t.Fatal(err)
+	}
+	issue, _ := repo.Issue(1)
+	if issue.Milestone == nil || issue.Milestone.Number != m.Number {
+		t.Errorf("milestone = %+v, want %s", issue.Milestone, m.Title)
+	}
+
+	none := "none"
+	if _, err := svc.EditIssue(t.Context(), 1, service.EditIssueInput{Milestone: &none}); err != nil {
+		t.Fatal(err)
+	}
+	if issue, _ := repo.Issue(1); issue.Milestone != nil {
+		t.Errorf("milestone = %+v, want it removed", issue.Milestone)
+	}
+}
+
+func numbers(issues []github.Issue) []int {
+	n := make([]int, len(issues))
+	for i, issue := range issues {
+		n[i] = issue.Number
+	}
+	return n
+}
+
+// dependencyClient answers dependency changes with err.
+type dependencyClient struct {
+	github.Client
+	err error
+}
+
+func (c dependencyClient) AddBlockedBy(ctx context.Context, number, blocker github.IssueRef) (*github.Issue, error) {
+	return nil, c.err
+}
+
+func (c dependencyClient) RemoveBlockedBy(ctx context.Context, number, blocker github.IssueRef) (*github.Issue, error) {
+	return nil, c.err
+}
+
+func TestLinkErrors(t *testing.T) {
+	missing := fmt.Errorf("#9 is not blocked by #3: %w", &github.NotFoundError{})
+	unsupported := fmt.Errorf("issue dependencies: %w", github.ErrNotSupported)
+
+	tests := []struct {
+		name     string
+		err      error
+		unlink   bool
+		wantHint bool
+	}{
+		{"unlink missing dependency", missing, true, false},
+		{"link unsupported", unsupported, false, true},
+		{"link missing issue", &github.NotFoundError{}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewIssueService(dependencyClient{err: tt.err}, nil, &config.Config{})
+			target := github.IssueRef{Number: 9}
+			var err error
+			if tt.unlink {
+				err = svc.UnlinkIssue(t.Context(), 3, target, service.LinkBlocks)
+			} else {
+				err = svc.LinkIssue(t.Context(), 3, target, service.LinkBlocks)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want it to wrap %v", err, tt.err)
+			}
+			if got := strings.Contains(err.Error(), "--comment"); got != tt.wantHint {
+				t.Errorf("err = %q, want the --comment hint only when the API is missing", err)
+			}
+		})
+	}
+}
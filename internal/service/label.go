@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+)
+
+// defaultLabelColor is used when a configured label has no color. It matches
+// the grey GitHub uses for new labels.
+const defaultLabelColor = "ededed"
+
+// LabelAction describes what a sync will do to a single label.
+type LabelAction string
+
+const (
+	LabelCreate LabelAction = "create"
+	LabelUpdate LabelAction = "update"
+	LabelDelete LabelAction = "delete"
+)
+
+// LabelChange is a single step in a label sync plan.
+type LabelChange struct {
+	Action LabelAction
+	// Current is the label as it exists in the repository. It is nil for
+	// creations.
+	Current *github.Label
+	// Desired is the label as configured. It is empty for deletions.
+	Desired config.LabelConfig
+}
+
+// Name returns the name of the label the change applies to.
+func (c LabelChange) Name() string {
+	if c.Action == LabelDelete {
+		return c.Current.Name
+	}
+	return c.Desired.Name
+}
+
+// LabelService manages repository labels and keeps them in sync with the
+// labels defined in the project config.
+type LabelService struct {
+	github github.Client
+	cfg    *config.Config
+}
+
+func NewLabelService(ghClient github.Client, cfg *config.Config) *LabelService {
+	return &LabelService{
+		github: ghClient,
+		cfg:    cfg,
+	}
+}
+
+func (s *LabelService) List(ctx context.Context) ([]github.Label, error) {
+	labels, err := s.github.ListLabels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing labels: %w", err)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return strings.ToLower(labels[i].Name) < strings.ToLower(labels[j].Name)
+	})
+	return labels, nil
+}
+
+func (s *LabelService) Create(ctx context.Context, label config.LabelConfig) (*github.Label, error) {
+	req := github.LabelRequest{
+		Name:  label.Name,
+		Color: config.NormalizeColor(label.Color),
+	}
+	if req.Color == "" {
+		req.Color = defaultLabelColor
+	}
+	if label.Description != "" {
+		req.Description = &label.Description
+	}
+
+	created, err := s.github.CreateLabel(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("creating label: %w", err)
+	}
+	return created, nil
+}
+
+// Update changes an existing label. Empty fields in req are left unchanged.
+func (s *LabelService) Update(ctx context.Context, name string, req github.LabelRequest) (*github.Label, error) {
+	req.Color = config.NormalizeColor(req.Color)
+	updated, err := s.github.UpdateLabel(ctx, name, req)
+	if err != nil {
+		return nil, fmt.Errorf("updating label: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *LabelService) Delete(ctx context.Context, name string) error {
+	if err := s.github.DeleteLabel(ctx, name); err != nil {
+		return fmt.Errorf("deleting label: %w", err)
+	}
+	return nil
+}
+
+// Import returns the repository's labels in config form, ready to be saved
+// under project.labels.
+func (s *LabelService) Import(ctx context.Context) ([]config.LabelConfig, error) {
+	labels, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]config.LabelConfig, len(labels))
+	for i, l := range labels {
+		out[i] = config.LabelConfig{
+			Name:        l.Name,
+			Color:       l.Color,
+			Description: l.Description,
+		}
+	}
+	return out, nil
+}
+
+// PlanSync compares the configured labels with the repository and returns
+// the changes needed to reconcile them. Labels are matched by name without
+// regard to case. A color or description left empty in the config is not
+// managed. Labels missing from the config are only deleted when prune is set.
+func (s *LabelService) PlanSync(ctx context.Context, prune bool) ([]LabelChange, error) {
+	current, err := s.github.ListLabels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing labels: %w", err)
+	}
+
+	byName := make(map[string]*github.Label, len(current))
+	for i := range current {
+		byName[strings.ToLower(current[i].Name)] = &current[i]
+	}
+
+	var changes []LabelChange
+	wanted := make(map[string]bool, len(s.cfg.Project.Labels))
+	for _, desired := range s.cfg.Project.Labels {
+		key := strings.ToLower(desired.Name)
+		if wanted[key] {
+			return nil, fmt.Errorf("label %q is defined more than once in config", desired.Name)
+		}
+		wanted[key] = true
+
+		existing, ok := byName[key]
+		if !ok {
+			changes = append(changes, LabelChange{Action: LabelCreate, Desired: desired})
+			continue
+		}
+		if labelDiffers(existing, desired) {
+			changes = append(changes, LabelChange{Action: LabelUpdate, Current: existing, Desired: desired})
+		}
+	}
+
+	if prune {
+		for i := range current {
+			if !wanted[strings.ToLower(current[i].Name)] {
+				changes = append(changes, LabelChange{Action: LabelDelete, Current: &current[i]})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// ApplySync performs the changes returned by PlanSync, stopping at the
+// first failure.
+func (s *LabelService) ApplySync(ctx context.Context, changes []LabelChange) error {
+	for _, c := range changes {
+		var err error
+		switch c.Action {
+		case LabelCreate:
+			_, err = s.Create(ctx, c.Desired)
+		case LabelUpdate:
+			req := github.LabelRequest{Name: c.Desired.Name, Color: c.Desired.Color}
+			if c.Desired.Description != "" {
+				req.Description = &c.Desired.Description
+			}
+			_, err = s.Update(ctx, c.Current.Name, req)
+		case LabelDelete:
+			err = s.Delete(ctx, c.Current.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("%s %q: %w", c.Action, c.Name(), err)
+		}
+	}
+	return nil
+}
+
+func labelDiffers(current *github.Label, desired config.LabelConfig) bool {
+	if current.Name != desired.Name {
+		return true
+	}
+	if desired.Color != "" && !strings.EqualFold(current.Color, config.NormalizeColor(desired.Color)) {
+		return true
+	}
+	return desired.Description != "" && current.Description != desired.Description
+}
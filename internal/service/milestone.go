@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// MilestoneService manages repository milestones.
+type MilestoneService struct {
+	github github.Client
+}
+
+func NewMilestoneService(ghClient github.Client) *MilestoneService {
+	return &MilestoneService{github: ghClient}
+}
+
+func (s *MilestoneService) List(ctx context.Context, state string) ([]github.Milestone, error) {
+	milestones, err := s.github.ListMilestones(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("listing milestones: %w", err)
+	}
+	return milestones, nil
+}
+
+// Create adds a milestone. A zero due date leaves the milestone undated.
+func (s *MilestoneService) Create(ctx context.Context, title, description string, due time.Time) (*github.Milestone, error) {
+	req := github.CreateMilestoneRequest{
+		Title:       title,
+		Description: description,
+	}
+	if !due.IsZero() {
+		req.DueOn = &due
+	}
+
+	m, err := s.github.CreateMilestone(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("creating milestone: %w", err)
+	}
+	return m, nil
+}
+
+// Close closes the milestone identified by number or title.
+func (s *MilestoneService) Close(ctx context.Context, ref string) (*github.Milestone, error) {
+	m, err := findMilestone(ctx, s.github, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	state := "closed"
+	closed, err := s.github.UpdateMilestone(ctx, m.Number, github.UpdateMilestoneRequest{State: &state})
+	if err != nil {
+		return nil, fmt.Errorf("closing milestone: %w", err)
+	}
+	return closed, nil
+}
+
+func findMilestone(ctx context.Context, gh github.Client, ref string) (*github.Milestone, error) {
+	milestones, err := gh.ListMilestones(ctx, "all")
+	if err != nil {
+		return nil, fmt.Errorf("listing milestones: %w", err)
+	}
+
+	if number, err := strconv.Atoi(ref); err == nil {
+		for i := range milestones {
+			if milestones[i].Number == number {
+				return &milestones[i], nil
+			}
+		}
+	}
+	for i := range milestones {
+		if strings.EqualFold(milestones[i].Title, ref) {
+			return &milestones[i], nil
+		}
+	}
+	return nil, fmt.Errorf("milestone %q not found", ref)
+}
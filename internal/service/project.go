@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dulait/grit/internal/config"
+	"github.com/dulait/grit/internal/github"
+)
+
+// FieldAssignment sets a project field. Field is a field name or an alias
+// from the project's config.
+type FieldAssignment struct {
+	Field string
+	Value string
+}
+
+// ProjectService adds issues to GitHub Projects (v2) boards and manages
+// their field values.
+type ProjectService struct {
+	github github.Client
+	cfg    *config.Config
+}
+
+func NewProjectService(ghClient github.Client, cfg *config.Config) *ProjectService {
+	return &ProjectService{
+		github: ghClient,
+		cfg:    cfg,
+	}
+}
+
+// Board fetches the configured board with the given name, or the default
+// board when name is empty.
+func (s *ProjectService) Board(ctx context.Context, name string) (*github.Project, *config.BoardConfig, error) {
+	board, err := s.cfg.Board(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var project *github.Project
+	switch {
+	case board.ID != "":
+		project, err = s.github.GetProjectByID(ctx, board.ID)
+	case board.Owner != "" && board.Number > 0:
+		project, err = s.github.GetProject(ctx, board.Owner, board.Number)
+	default:
+		return nil, nil, fmt.Errorf("project %q needs either an id or an owner and number", board.Name)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching project %q: %w", board.Name, err)
+	}
+	return project, board, nil
+}
+
+// AddIssue adds an issue to a board and sets the given fields. Adding an
+// issue that is already on the board only updates its fields.
+func (s *ProjectService) AddIssue(ctx context.Context, number int, name string, fields []FieldAssignment) (*github.Project, error) {
+	project, board, err := s.Board(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]github.ProjectFieldValue, len(fields))
+	targets := make([]*github.ProjectField, len(fields))
+	for i, a := range fields {
+		field := findProjectField(project, board.FieldName(a.Field))
+		if field == nil {
+			return nil, fmt.Errorf("project %q has no field %q", board.Name, board.FieldName(a.Field))
+		}
+		values[i], err = projectFieldValue(field, a.Value, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		targets[i] = field
+	}
+
+	issue, err := s.github.GetIssue(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("fetching issue: %w", err)
+	}
+
+	itemID, err := s.github.AddProjectItem(ctx, project.ID, issue.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("adding issue to project %q: %w", board.Name, err)
+	}
+
+	for i, field := range targets {
+		if err := s.github.UpdateProjectItemField(ctx, project.ID, itemID, field.ID, values[i]); err != nil {
+			return nil, fmt.Errorf("setting %s: %w", field.Name, err)
+		}
+	}
+	return project, nil
+}
+
+// Items returns the boards an issue is on and its field values on each.
+func (s *ProjectService) Items(ctx context.Context, number int) ([]github.ProjectItem, error) {
+	items, err := s.github.ListProjectItems(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("listing project items: %w", err)
+	}
+	return items, nil
+}
+
+func findProjectField(project *github.Project, name string) *github.ProjectField {
+	for i := range project.Fields {
+		if strings.EqualFold(project.Fields[i].Name, name) {
+			return &project.Fields[i]
+		}
+	}
+	return nil
+}
+
+// projectFieldValue converts raw into a value for field. Single-select
+// options and iterations are matched by name; iterations also accept
+// @current and @next.
+func projectFieldValue(field *github.ProjectField, raw string, now time.Time) (github.ProjectFieldValue, error) {
+	var v github.ProjectFieldValue
+
+	switch field.DataType {
+	case github.ProjectFieldSingleSelect:
+		for _, o := range field.Options {
+			if strings.EqualFold(o.Name, raw) {
+				v.SingleSelectOptionID = o.ID
+				return v, nil
+			}
+		}
+		names := make([]string, len(field.Options))
+		for i, o := range field.Options {
+			names[i] = o.Name
+		}
+		return v, fmt.Errorf("%s has no option %q; choose from: %s", field.Name, raw, strings.Join(names, ", "))
+
+	case github.ProjectFieldIteration:
+		it, err := findIteration(field, raw, now)
+		if err != nil {
+			return v, err
+		}
+		v.IterationID = it.ID
+		return v, nil
+
+	case github.ProjectFieldNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return v, fmt.Errorf("%s must be a number, got %q", field.Name, raw)
+		}
+		v.Number = &n
+		return v, nil
+
+	case github.ProjectFieldDate:
+		if _, err := time.Parse("2006-01-02", raw); err != nil {
+			return v, fmt.Errorf("%s must be a date in YYYY-MM-DD form, got %q", field.Name, raw)
+		}
+		v.Date = raw
+		return v, nil
+
+	case github.ProjectFieldText:
+		v.Text = raw
+		return v, nil
+	}
+
+	return v, fmt.Errorf("%s fields of type %s cannot be set by grit", field.Name, strings.ToLower(field.DataType))
+}
+
+func findIteration(field *github.ProjectField, raw string, now time.Time) (*github.ProjectIteration, error) {
+	today := now.Format("2006-01-02")
+
+	for i := range field.Iterations {
+		it := &field.Iterations[i]
+		start, err := time.Parse("2006-01-02", it.StartDate)
+		if err != nil {
+			continue
+		}
+		end := start.AddDate(0, 0, it.Duration).Format("2006-01-02")
+
+		switch strings.ToLower(raw) {
+		case "@current":
+			if it.StartDate <= today && today < end {
+				return it, nil
+			}
+		case "@next":
+			if it.StartDate > today {
+				return it, nil
+			}
+		default:
+			if strings.EqualFold(it.Title, raw) {
+				return it, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s has no iteration %q", field.Name, raw)
+}
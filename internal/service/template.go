@@ -0,0 +1,488 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dulait/grit/internal/github"
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateDir is where GitHub looks for issue templates and issue forms,
+// relative to the repository root.
+const TemplateDir = ".github/ISSUE_TEMPLATE"
+
+// Issue form element types.
+const (
+	FormMarkdown   = "markdown"
+	FormInput      = "input"
+	FormTextarea   = "textarea"
+	FormDropdown   = "dropdown"
+	FormCheckboxes = "checkboxes"
+)
+
+// IssueTemplate is a Markdown issue template or a YAML issue form.
+type IssueTemplate struct {
+	// Slug is the file name without its extension, e.g. "bug_report".
+	Slug      string
+	Name      string
+	About     string
+	Title     string
+	Labels    []string
+	Assignees []string
+	// Body is the Markdown body of a template. It is empty for forms.
+	Body string
+	// Form is set for YAML issue forms, whose body is described by Fields.
+	Form   bool
+	Fields []FormField
+}
+
+// FormField is one element of an issue form.
+type FormField struct {
+	Type        string
+	ID          string
+	Label       string
+	Description string
+	Placeholder string
+	// Value is the default value, or the text of a markdown element.
+	Value    string
+	Render   string
+	Options  []FormOption
+	Multiple bool
+	Required bool
+}
+
+// FormOption is a dropdown choice or a checkbox.
+type FormOption struct {
+	Label    string `yaml:"label"`
+	Required bool   `yaml:"required"`
+}
+
+// UnmarshalYAML accepts dropdown options, which are plain strings, as well
+// as checkbox options, which are mappings.
+func (o *FormOption) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		o.Label = node.Value
+		return nil
+	}
+	type formOption FormOption
+	return node.Decode((*formOption)(o))
+}
+
+// stringList is a YAML value given either as a sequence or as a
+// comma-separated string, as labels and assignees are in templates.
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = parseList(node.Value)
+		return nil
+	}
+	var items []string
+	if err := node.Decode(&items); err != nil {
+		return err
+	}
+	*l = nil
+	for _, item := range items {
+		*l = append(*l, parseList(item)...)
+	}
+	return nil
+}
+
+func parseList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+type templateHeader struct {
+	Name        string     `yaml:"name"`
+	About       string     `yaml:"about"`
+	Description string     `yaml:"description"`
+	Title       string     `yaml:"title"`
+	Labels      stringList `yaml:"labels"`
+	Assignees   stringList `yaml:"assignees"`
+}
+
+type issueForm struct {
+	templateHeader `yaml:",inline"`
+	Body           []struct {
+		Type       string `yaml:"type"`
+		ID         string `yaml:"id"`
+		Attributes struct {
+			Label       string       `yaml:"label"`
+			Description string       `yaml:"description"`
+			Placeholder string       `yaml:"placeholder"`
+			Value       string       `yaml:"value"`
+			Render      string       `yaml:"render"`
+			Options     []FormOption `yaml:"options"`
+			Multiple    bool         `yaml:"multiple"`
+		} `yaml:"attributes"`
+		Validations struct {
+			Required bool `yaml:"required"`
+		} `yaml:"validations"`
+	} `yaml:"body"`
+}
+
+// ParseIssueTemplate parses a template file. Files ending in .md are
+// Markdown templates with optional front matter; .yml and .yaml files are
+// issue forms.
+func ParseIssueTemplate(name string, data []byte) (*IssueTemplate, error) {
+	ext := strings.ToLower(path.Ext(name))
+	t := &IssueTemplate{Slug: strings.TrimSuffix(name, path.Ext(name))}
+
+	switch ext {
+	case ".md":
+		header, body := splitFrontMatter(data)
+		var h templateHeader
+		if err := yaml.Unmarshal(header, &h); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+		t.setHeader(h)
+		t.Body = strings.TrimSpace(string(body))
+
+	case ".yml", ".yaml":
+		var f issueForm
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+		t.setHeader(f.templateHeader)
+		t.Form = true
+		for _, el := range f.Body {
+			t.Fields = append(t.Fields, FormField{
+				Type:        el.Type,
+				ID:          el.ID,
+				Label:       el.Attributes.Label,
+				Description: el.Attributes.Description,
+				Placeholder: el.Attributes.Placeholder,
+				Value:       el.Attributes.Value,
+				Render:      el.Attributes.Render,
+				Options:     el.Attributes.Options,
+				Multiple:    el.Attributes.Multiple,
+				Required:    el.Validations.Required,
+			})
+		}
+
+	default:
+		return nil, fmt.Errorf("%s is not an issue template", name)
+	}
+
+	if t.Name == "" {
+		t.Name = t.Slug
+	}
+	return t, nil
+}
+
+func (t *IssueTemplate) setHeader(h templateHeader) {
+	t.Name = h.Name
+	t.About = h.About
+	if t.About == "" {
+		t.About = h.Description
+	}
+	t.Title = h.Title
+	t.Labels = h.Labels
+	t.Assignees = h.Assignees
+}
+
+// splitFrontMatter separates the YAML front matter of a Markdown template
+// from its body. A file without front matter has an empty header.
+func splitFrontMatter(data []byte) (header, body []byte) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(data, []byte("---\n")) {
+		return nil, data
+	}
+	rest := data[len("---\n"):]
+	end := bytes.Index(rest, []byte("\n---"))
+	if end == -1 {
+		return nil, data
+	}
+	body = rest[end+len("\n---"):]
+	if i := bytes.IndexByte(body, '\n'); i != -1 {
+		body = body[i+1:]
+	} else {
+		body = nil
+	}
+	return rest[:end], body
+}
+
+// Inputs returns the form fields that take a response, skipping markdown
+// elements.
+func (t *IssueTemplate) Inputs() []FormField {
+	var fields []FormField
+	for _, f := range t.Fields {
+		if f.Type != FormMarkdown {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Outline returns the body skeleton the LLM is asked to fill in: the
+// Markdown body of a template, or one section per field of a form.
+func (t *IssueTemplate) Outline() string {
+	if !t.Form {
+		return t.Body
+	}
+	var b strings.Builder
+	for _, f := range t.Inputs() {
+		fmt.Fprintf(&b, "### %s\n\n", f.Label)
+		if f.Description != "" {
+			fmt.Fprintf(&b, "<!-- %s -->\n\n", f.Description)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Key identifies the field in a map of form responses.
+func (f FormField) Key() string {
+	if f.ID != "" {
+		return f.ID
+	}
+	return f.Label
+}
+
+// OptionLabels returns the labels of the field's options.
+func (f FormField) OptionLabels() []string {
+	labels := make([]string, len(f.Options))
+	for i, o := range f.Options {
+		labels[i] = o.Label
+	}
+	return labels
+}
+
+// Validate checks a response to the field. Dropdown and checkbox responses
+// are option labels, matched without regard to case.
+func (f FormField) Validate(values []string) error {
+	if f.Required && len(values) == 0 {
+		return fmt.Errorf("%s is required", f.Label)
+	}
+
+	switch f.Type {
+	case FormDropdown:
+		if len(values) > 1 && !f.Multiple {
+			return fmt.Errorf("%s takes a single option", f.Label)
+		}
+		for _, v := range values {
+			if f.option(v) == nil {
+				return fmt.Errorf("%q is not an option for %s (options: %s)", v, f.Label, strings.Join(f.OptionLabels(), ", "))
+			}
+		}
+	case FormCheckboxes:
+		for _, v := range values {
+			if f.option(v) == nil {
+				return fmt.Errorf("%q is not an option for %s", v, f.Label)
+			}
+		}
+		for _, o := range f.Options {
+			if o.Required && !slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, o.Label) }) {
+				return fmt.Errorf("%q must be checked", o.Label)
+			}
+		}
+	}
+	return nil
+}
+
+func (f FormField) option(label string) *FormOption {
+	for i, o := range f.Options {
+		if strings.EqualFold(o.Label, label) {
+			return &f.Options[i]
+		}
+	}
+	return nil
+}
+
+// RenderForm builds an issue body from form responses keyed by FormField.Key,
+// in the layout GitHub uses for issues created from a form.
+func (t *IssueTemplate) RenderForm(answers map[string][]string) string {
+	var b strings.Builder
+	for _, f := range t.Inputs() {
+		values := answers[f.Key()]
+		fmt.Fprintf(&b, "### %s\n\n", f.Label)
+
+		switch {
+		case f.Type == FormCheckboxes:
+			for _, o := range f.Options {
+				mark := " "
+				if slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, o.Label) }) {
+					mark = "X"
+				}
+				fmt.Fprintf(&b, "- [%s] %s\n", mark, o.Label)
+			}
+		case len(values) == 0:
+			b.WriteString("_No response_\n")
+		case f.Render != "":
+			fmt.Fprintf(&b, "```%s\n%s\n```\n", f.Render, strings.Join(values, "\n"))
+		default:
+			b.WriteString(strings.Join(values, ", ") + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// ApplyTemplate records the template on the input and adds the template's
+// default labels and assignees to those the user gave.
+func ApplyTemplate(input IssueInput, t *IssueTemplate) IssueInput {
+	if t == nil {
+		return input
+	}
+	input.Template = t
+	input.Labels = mergeNames(t.Labels, input.Labels)
+	input.Assignees = mergeNames(t.Assignees, input.Assignees)
+	return input
+}
+
+func mergeNames(base, extra []string) []string {
+	merged := slices.Clone(base)
+	for _, name := range extra {
+		if !slices.ContainsFunc(merged, func(m string) bool { return strings.EqualFold(m, name) }) {
+			merged = append(merged, name)
+		}
+	}
+	return merged
+}
+
+// applyTitle prefixes title with the template's default title, unless the
+// title already starts with it.
+func (t *IssueTemplate) applyTitle(title string) string {
+	prefix := t.Title
+	if strings.TrimSpace(prefix) == "" || title == "" {
+		return title
+	}
+	if strings.HasPrefix(strings.ToLower(title), strings.ToLower(strings.TrimSpace(prefix))) {
+		return title
+	}
+	if !strings.HasSuffix(prefix, " ") {
+		prefix += " "
+	}
+	return prefix + title
+}
+
+// TemplateService discovers the issue templates of the configured repository.
+type TemplateService struct {
+	github github.Client
+	root   string
+}
+
+// NewTemplateService creates a template service. Templates are read from
+// the checkout at root when it has a template directory, and from the
+// repository's default branch through the API otherwise.
+func NewTemplateService(gh github.Client, root string) *TemplateService {
+	return &TemplateService{github: gh, root: root}
+}
+
+// List returns the repository's issue templates, ordered by file name.
+func (s *TemplateService) List(ctx context.Context) ([]IssueTemplate, error) {
+	if s.root != "" {
+		dir := filepath.Join(s.root, filepath.FromSlash(TemplateDir))
+		if entries, err := os.ReadDir(dir); err == nil {
+			return s.listLocal(dir, entries)
+		}
+	}
+	return s.listRemote(ctx)
+}
+
+func (s *TemplateService) listLocal(dir string, entries []os.DirEntry) ([]IssueTemplate, error) {
+	var templates []IssueTemplate
+	for _, e := range entries {
+		if e.IsDir() || !isTemplateFile(e.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading issue template: %w", err)
+		}
+		t, err := ParseIssueTemplate(e.Name(), data)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	return templates, nil
+}
+
+func (s *TemplateService) listRemote(ctx context.Context) ([]IssueTemplate, error) {
+	entries, err := s.github.ListDirectory(ctx, TemplateDir)
+	if err != nil {
+		var notFound *github.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing issue templates: %w", err)
+	}
+
+	var templates []IssueTemplate
+	for _, e := range entries {
+		if e.Type != "file" || !isTemplateFile(e.Name) {
+			continue
+		}
+		data, err := s.github.GetFileContent(ctx, e.Path)
+		if err != nil {
+			return nil, fmt.Errorf("fetching issue template %s: %w", e.Name, err)
+		}
+		t, err := ParseIssueTemplate(e.Name, data)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	return templates, nil
+}
+
+// isTemplateFile reports whether name is a template or form, as opposed to
+// the template chooser's config.yml.
+func isTemplateFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md":
+		return true
+	case ".yml", ".yaml":
+		return strings.ToLower(strings.TrimSuffix(name, path.Ext(name))) != "config"
+	}
+	return false
+}
+
+// Find returns the template whose file name or display name matches name,
+// without regard to case.
+func (s *TemplateService) Find(ctx context.Context, name string) (*IssueTemplate, error) {
+	templates, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("repository has no issue templates in %s", TemplateDir)
+	}
+
+	for i, t := range templates {
+		if strings.EqualFold(t.Slug, name) || strings.EqualFold(t.Name, name) {
+			return &templates[i], nil
+		}
+	}
+
+	// Allow "bug" for "bug_report" or "bug-report.yml".
+	var matches []*IssueTemplate
+	for i, t := range templates {
+		if strings.HasPrefix(strings.ToLower(t.Slug), strings.ToLower(name)) {
+			matches = append(matches, &templates[i])
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+
+	slugs := make([]string, len(templates))
+	for i, t := range templates {
+		slugs[i] = t.Slug
+	}
+	return nil, fmt.Errorf("no issue template named %q (available: %s)", name, strings.Join(slugs, ", "))
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dulait/grit/internal/github"
+)
+
+// HistoryEntry is a timeline event described for display.
+type HistoryEntry struct {
+	Time  time.Time
+	Actor string
+	Text  string
+}
+
+// History returns the issue's timeline as a chronological log of label,
+// assignment, state, title and milestone changes, comments and
+// cross-references. Events grit does not know how to describe are skipped.
+func (s *IssueService) History(ctx context.Context, number int) ([]HistoryEntry, error) {
+	events, err := s.github.ListTimeline(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("fetching timeline: %w", err)
+	}
+
+	var entries []HistoryEntry
+	for _, e := range events {
+		text, ok := s.describeEvent(e)
+		if !ok {
+			continue
+		}
+		entries = append(entries, HistoryEntry{
+			Time:  e.CreatedAt,
+			Actor: eventActor(e),
+			Text:  text,
+		})
+	}
+	return entries, nil
+}
+
+func (s *IssueService) describeEvent(e github.TimelineEvent) (string, bool) {
+	switch e.Event {
+	case "labeled":
+		if e.Label == nil {
+			return "", false
+		}
+		return fmt.Sprintf("added label %s", e.Label.Name), true
+	case "unlabeled":
+		if e.Label == nil {
+			return "", false
+		}
+		return fmt.Sprintf("removed label %s", e.Label.Name), true
+	case "assigned":
+		if e.Assignee == nil {
+			return "", false
+		}
+		if e.Actor != nil && e.Actor.Login == e.Assignee.Login {
+			return "self-assigned this", true
+		}
+		return fmt.Sprintf("assigned @%s", e.Assignee.Login), true
+	case "unassigned":
+		if e.Assignee == nil {
+			return "", false
+		}
+		return fmt.Sprintf("unassigned @%s", e.Assignee.Login), true
+	case "closed":
+		if e.StateReason != "" {
+			return fmt.Sprintf("closed this as %s", strings.ReplaceAll(e.StateReason, "_", " ")), true
+		}
+		return "closed this", true
+	case "reopened":
+		return "reopened this", true
+	case "renamed":
+		if e.Rename == nil {
+			return "", false
+		}
+		return fmt.Sprintf("changed the title from %q to %q", e.Rename.From, e.Rename.To), true
+	case "milestoned":
+		if e.Milestone == nil {
+			return "", false
+		}
+		return fmt.Sprintf("added this to the %s milestone", e.Milestone.Title), true
+	case "demilestoned":
+		if e.Milestone == nil {
+			return "", false
+		}
+		return fmt.Sprintf("removed this from the %s milestone", e.Milestone.Title), true
+	case "commented":
+		return "commented", true
+	case "referenced":
+		if e.CommitID == "" {
+			return "", false
+		}
+		return fmt.Sprintf("referenced this in commit %s", shortSHA(e.CommitID)), true
+	case "cross-referenced":
+		if e.Source == nil || e.Source.Issue == nil {
+			return "", false
+		}
+		return "referenced this in " + s.describeSource(e.Source.Issue), true
+	case "marked_as_duplicate":
+		return "marked this as a duplicate", true
+	case "connected":
+		return "linked a pull request that will close this", true
+	case "disconnected":
+		return "removed a linked pull request", true
+	case "locked":
+		return "locked this conversation", true
+	case "unlocked":
+		return "unlocked this conversation", true
+	case "pinned":
+		return "pinned this issue", true
+	case "unpinned":
+		return "unpinned this issue", true
+	case "transferred":
+		return "transferred this issue", true
+	}
+	return "", false
+}
+
+// describeSource formats a cross-referencing issue or pull request,
+// qualifying it with the repository name when it lives elsewhere.
+func (s *IssueService) describeSource(issue *github.Issue) string {
+	kind := "issue"
+	if issue.PullRequest != nil {
+		kind = "pull request"
+	}
+
+	ref := fmt.Sprintf("#%d", issue.Number)
+	own := s.cfg.Project.Owner + "/" + s.cfg.Project.Repo
+	if issue.Repository != nil && !strings.EqualFold(issue.Repository.FullName, own) {
+		ref = issue.Repository.FullName + ref
+	}
+	return fmt.Sprintf("%s %s: %s", kind, ref, issue.Title)
+}
+
+func eventActor(e github.TimelineEvent) string {
+	switch {
+	case e.Actor != nil:
+		return e.Actor.Login
+	case e.User != nil:
+		return e.User.Login
+	}
+	return ""
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
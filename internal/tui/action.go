@@ -3,12 +3,16 @@ package tui
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/offline"
+	"github.com/dulait/grit/internal/service"
 )
 
 type actionKind int
@@ -19,11 +23,26 @@ const (
 	actionComment
 )
 
+// closeReason is an option offered by the close modal.
+type closeReason struct {
+	value string
+	title string
+}
+
+var closeReasons = []closeReason{
+	{github.StateReasonCompleted, "completed"},
+	{github.StateReasonNotPlanned, "not planned"},
+	{github.StateReasonDuplicate, "duplicate"},
+}
+
 type actionModel struct {
 	kind        actionKind
 	issueNumber int
 	deps        Dependencies
 	input       textinput.Model
+	completer   assigneeCompleter
+	reason      int
+	comment     string
 	loading     bool
 	spinner     spinner.Model
 	err         error
@@ -64,6 +83,9 @@ func newActionModel(deps Dependencies, kind actionKind, issueNumber, width, heig
 }
 
 func (m actionModel) Init() tea.Cmd {
+	if m.kind == actionAssign {
+		return tea.Batch(textinput.Blink, loadAssignees(m.deps))
+	}
 	return textinput.Blink
 }
 
@@ -85,7 +107,26 @@ func (m actionModel) Update(msg tea.Msg) (actionModel, tea.Cmd) {
 		switch msg.String() {
 		case "esc":
 			return m, func() tea.Msg { return actionCancelledMsg{} }
+		case "tab", "shift+tab":
+			if m.kind == actionClose {
+				m.cycleReason(msg.String() == "tab")
+				return m, nil
+			}
+			if m.kind == actionAssign && msg.String() == "tab" {
+				m.completer.complete(&m.input)
+				return m, nil
+			}
+		case "ctrl+n", "ctrl+p":
+			if m.kind == actionAssign {
+				m.completer.cycle(msg.String() == "ctrl+n")
+				return m, nil
+			}
 		case "enter":
+			if m.kind == actionClose && m.isDuplicate() {
+				if _, err := strconv.Atoi(strings.TrimSpace(m.input.Value())); err != nil {
+					return m, nil
+				}
+			}
 			if m.kind == actionAssign && strings.TrimSpace(m.input.Value()) == "" {
 				return m, nil
 			}
@@ -103,6 +144,10 @@ func (m actionModel) Update(msg tea.Msg) (actionModel, tea.Cmd) {
 			return m, cmd
 		}
 
+	case assigneesLoadedMsg:
+		m.completer.users = msg.users
+		return m, nil
+
 	case actionSuccessMsg:
 		m.done = true
 		m.loading = false
@@ -116,22 +161,60 @@ func (m actionModel) Update(msg tea.Msg) (actionModel, tea.Cmd) {
 	if !m.loading && !m.done && m.err == nil {
 		var cmd tea.Cmd
 		m.input, cmd = m.input.Update(msg)
+		if m.kind == actionAssign {
+			m.completer.update(m.input.Value())
+		}
 		return m, cmd
 	}
 
 	return m, nil
 }
 
+func (m actionModel) isDuplicate() bool {
+	return closeReasons[m.reason].value == github.StateReasonDuplicate
+}
+
+// cycleReason moves to the next or previous close reason. The duplicate
+// reason reuses the text input for the canonical issue number, so the
+// comment typed so far is set aside and restored when leaving it.
+func (m *actionModel) cycleReason(forward bool) {
+	wasDuplicate := m.isDuplicate()
+	if forward {
+		m.reason = (m.reason + 1) % len(closeReasons)
+	} else {
+		m.reason = (m.reason + len(closeReasons) - 1) % len(closeReasons)
+	}
+
+	switch {
+	case m.isDuplicate() && !wasDuplicate:
+		m.comment = m.input.Value()
+		m.input.SetValue("")
+		m.input.Placeholder = "duplicate of issue number"
+	case !m.isDuplicate() && wasDuplicate:
+		m.input.SetValue(m.comment)
+		m.input.Placeholder = "closing comment (optional)"
+	}
+	m.input.CursorEnd()
+}
+
 func (m actionModel) execute() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 		switch m.kind {
 		case actionClose:
-			_, err := m.deps.GitHubClient.CloseIssue(ctx, m.issueNumber, m.input.Value())
+			opts := service.CloseOptions{Comment: m.input.Value(), Reason: closeReasons[m.reason].value}
+			if m.isDuplicate() {
+				opts.DuplicateOf.Number, _ = strconv.Atoi(strings.TrimSpace(m.input.Value()))
+				opts.Comment = m.comment
+			}
+			_, err := m.deps.IssueServiceWithoutLLM().CloseIssue(ctx, m.issueNumber, opts)
 			if err != nil {
 				return errMsg{err: err}
 			}
-			return actionSuccessMsg{text: fmt.Sprintf("Issue #%d closed", m.issueNumber)}
+			if m.isDuplicate() {
+				return actionSuccessMsg{text: fmt.Sprintf("Issue %s closed as a duplicate of %s", offline.FormatNumber(m.issueNumber), opts.DuplicateOf)}
+			}
+			return actionSuccessMsg{text: fmt.Sprintf("Issue %s closed as %s", offline.FormatNumber(m.issueNumber), closeReasons[m.reason].title)}
 
 		case actionAssign:
 			raw := strings.Split(m.input.Value(), ",")
@@ -141,18 +224,22 @@ func (m actionModel) execute() tea.Cmd {
 					assignees = append(assignees, trimmed)
 				}
 			}
-			_, err := m.deps.GitHubClient.AssignIssue(ctx, m.issueNumber, assignees)
+			assignees, err := resolveAssignees(ctx, m.deps, assignees)
+			if err != nil {
+				return errMsg{err: err}
+			}
+			_, err = m.deps.GitHubClient.AssignIssue(ctx, m.issueNumber, assignees)
 			if err != nil {
 				return errMsg{err: err}
 			}
-			return actionSuccessMsg{text: fmt.Sprintf("Issue #%d assigned to %s", m.issueNumber, strings.Join(assignees, ", "))}
+			return actionSuccessMsg{text: fmt.Sprintf("Issue %s assigned to %s", offline.FormatNumber(m.issueNumber), strings.Join(assignees, ", "))}
 
 		case actionComment:
 			_, err := m.deps.GitHubClient.AddComment(ctx, m.issueNumber, m.input.Value())
 			if err != nil {
 				return errMsg{err: err}
 			}
-			return actionSuccessMsg{text: fmt.Sprintf("Comment added to issue #%d", m.issueNumber)}
+			return actionSuccessMsg{text: fmt.Sprintf("Comment added to issue %s", offline.FormatNumber(m.issueNumber))}
 		}
 		return nil
 	}
@@ -175,6 +262,16 @@ func (m actionModel) View() string {
 		lines = append(lines, errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
 		lines = append(lines, "")
 		lines = append(lines, dimStyle.Render("Press any key to continue"))
+	} else if m.kind == actionClose {
+		lines = append(lines, m.renderReasons())
+		lines = append(lines, "")
+		lines = append(lines, m.input.View())
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("tab reason · enter submit · esc cancel"))
+	} else if m.kind == actionAssign {
+		lines = append(lines, m.input.View())
+		lines = append(lines, m.completer.View())
+		lines = append(lines, dimStyle.Render("tab complete · ctrl+n/ctrl+p choose · enter submit · esc cancel"))
 	} else {
 		lines = append(lines, m.input.View())
 		lines = append(lines, "")
@@ -187,14 +284,26 @@ func (m actionModel) View() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
 }
 
+func (m actionModel) renderReasons() string {
+	parts := make([]string, len(closeReasons))
+	for i, r := range closeReasons {
+		if i == m.reason {
+			parts[i] = selectedStyle.Render(" " + r.title + " ")
+		} else {
+			parts[i] = dimStyle.Render(" " + r.title + " ")
+		}
+	}
+	return "Reason: " + strings.Join(parts, " ")
+}
+
 func (m actionModel) actionTitle() string {
 	switch m.kind {
 	case actionClose:
-		return fmt.Sprintf("Close Issue #%d", m.issueNumber)
+		return fmt.Sprintf("Close Issue %s", offline.FormatNumber(m.issueNumber))
 	case actionAssign:
-		return fmt.Sprintf("Assign Issue #%d", m.issueNumber)
+		return fmt.Sprintf("Assign Issue %s", offline.FormatNumber(m.issueNumber))
 	case actionComment:
-		return fmt.Sprintf("Comment on Issue #%d", m.issueNumber)
+		return fmt.Sprintf("Comment on Issue %s", offline.FormatNumber(m.issueNumber))
 	}
 	return ""
 }
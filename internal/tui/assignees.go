@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dulait/grit/internal/service"
+)
+
+// maxAssigneeCompletions bounds the suggestions shown under an assignee
+// input.
+const maxAssigneeCompletions = 5
+
+type assigneesLoadedMsg struct {
+	users []string
+}
+
+// loadAssignees fetches the repository's assignable users for completion.
+// Completion is simply unavailable when they cannot be listed.
+func loadAssignees(deps Dependencies) tea.Cmd {
+	return func() tea.Msg {
+		users, err := deps.AssigneeService().List(context.Background())
+		if err != nil {
+			return assigneesLoadedMsg{}
+		}
+		return assigneesLoadedMsg{users: users}
+	}
+}
+
+// resolveAssignees checks logins against the repository's assignable users.
+// Only unknown logins are an error; when the users cannot be listed, the
+// logins are used as typed.
+func resolveAssignees(ctx context.Context, deps Dependencies, logins []string) ([]string, error) {
+	resolved, err := deps.AssigneeService().Validate(ctx, logins)
+	var unknown *service.UnknownAssigneesError
+	if errors.As(err, &unknown) {
+		return nil, err
+	}
+	if err != nil {
+		return logins, nil
+	}
+	return resolved, nil
+}
+
+// assigneeCompleter suggests assignable users for the login being typed at
+// the end of a comma-separated assignee input.
+type assigneeCompleter struct {
+	users    []string
+	value    string
+	matches  []string
+	selected int
+}
+
+// update recomputes the suggestions when the input's value has changed.
+func (c *assigneeCompleter) update(value string) {
+	if value == c.value {
+		return
+	}
+	c.value = value
+	c.matches = nil
+	c.selected = 0
+
+	parts := strings.Split(value, ",")
+	current := strings.TrimSpace(parts[len(parts)-1])
+	if current == "" {
+		return
+	}
+	entered := make(map[string]bool, len(parts)-1)
+	for _, p := range parts[:len(parts)-1] {
+		entered[strings.ToLower(strings.TrimSpace(p))] = true
+	}
+
+	for _, login := range service.SuggestAssignees(current, c.users, len(c.users)) {
+		if strings.EqualFold(login, strings.TrimPrefix(current, "@")) {
+			c.matches = nil
+			return
+		}
+		if entered[strings.ToLower(login)] {
+			continue
+		}
+		c.matches = append(c.matches, login)
+		if len(c.matches) == maxAssigneeCompletions {
+			break
+		}
+	}
+}
+
+// cycle moves the highlighted suggestion forward or back.
+func (c *assigneeCompleter) cycle(forward bool) {
+	if len(c.matches) == 0 {
+		return
+	}
+	if forward {
+		c.selected = (c.selected + 1) % len(c.matches)
+	} else {
+		c.selected = (c.selected + len(c.matches) - 1) % len(c.matches)
+	}
+}
+
+// complete replaces the login being typed with the highlighted suggestion
+// and reports whether there was one to use.
+func (c *assigneeCompleter) complete(input *textinput.Model) bool {
+	if len(c.matches) == 0 {
+		return false
+	}
+
+	parts := strings.Split(input.Value(), ",")
+	login := c.matches[c.selected]
+	if len(parts) > 1 {
+		login = " " + login
+	}
+	parts[len(parts)-1] = login
+
+	input.SetValue(strings.Join(parts, ",") + ", ")
+	input.CursorEnd()
+	c.update(input.Value())
+	return true
+}
+
+func (c assigneeCompleter) View() string {
+	if len(c.matches) == 0 {
+		return ""
+	}
+	parts := make([]string, len(c.matches))
+	for i, login := range c.matches {
+		if i == c.selected {
+			parts[i] = selectedStyle.Render(" " + login + " ")
+		} else {
+			parts[i] = assigneeStyle.Render(" " + login + " ")
+		}
+	}
+	return strings.Join(parts, " ")
+}
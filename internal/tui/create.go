@@ -11,6 +11,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dulait/grit/internal/github"
 	"github.com/dulait/grit/internal/llm"
+	"github.com/dulait/grit/internal/offline"
 	"github.com/dulait/grit/internal/service"
 )
 
@@ -38,7 +39,11 @@ type createModel struct {
 	inputs     []textinput.Model
 	focusIndex int
 	generated  *llm.GeneratedIssue
+	templates  []service.IssueTemplate
+	template   int
+	formFields []service.FormField
 	assignees  []string
+	completer  assigneeCompleter
 	created    *github.Issue
 	spinner    spinner.Model
 	err        error
@@ -61,12 +66,13 @@ func newCreateModel(deps Dependencies, width, height int) createModel {
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
 
 	return createModel{
-		deps:   deps,
-		step:   stepInput,
-		inputs: inputs,
-		spinner: s,
-		width:  width,
-		height: height,
+		deps:     deps,
+		step:     stepInput,
+		template: -1,
+		inputs:   inputs,
+		spinner:  s,
+		width:    width,
+		height:   height,
 	}
 }
 
@@ -79,7 +85,15 @@ func newInput(placeholder string, charLimit int) textinput.Model {
 }
 
 func (m createModel) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, m.loadTemplates(), loadAssignees(m.deps))
+}
+
+func (m createModel) loadTemplates() tea.Cmd {
+	deps := m.deps
+	return func() tea.Msg {
+		templates, err := deps.TemplateService().List(context.Background())
+		return templatesLoadedMsg{templates: templates, err: err}
+	}
 }
 
 func (m createModel) Update(msg tea.Msg) (createModel, tea.Cmd) {
@@ -114,6 +128,16 @@ func (m createModel) Update(msg tea.Msg) (createModel, tea.Cmd) {
 			return m, cmd
 		}
 
+	case templatesLoadedMsg:
+		if msg.err == nil {
+			m.templates = msg.templates
+		}
+		return m, nil
+
+	case assigneesLoadedMsg:
+		m.completer.users = msg.users
+		return m, nil
+
 	case issueGeneratedMsg:
 		m.generated = msg.issue
 		m.step = stepReview
@@ -139,13 +163,29 @@ func (m createModel) updateInput(msg tea.KeyMsg) (createModel, tea.Cmd) {
 	case "esc":
 		return m, func() tea.Msg { return navigateToListMsg{} }
 	case "tab", "down":
-		m.focusIndex = (m.focusIndex + 1) % fieldCount
+		if msg.String() == "tab" && m.focusIndex == fieldAssignees && m.completer.complete(&m.inputs[fieldAssignees]) {
+			return m, nil
+		}
+		m.focusIndex = (m.focusIndex + 1) % len(m.inputs)
 		return m.syncFocus(), nil
 	case "shift+tab", "up":
-		m.focusIndex = (m.focusIndex - 1 + fieldCount) % fieldCount
+		m.focusIndex = (m.focusIndex - 1 + len(m.inputs)) % len(m.inputs)
 		return m.syncFocus(), nil
+	case "ctrl+n", "ctrl+p":
+		if m.focusIndex == fieldAssignees {
+			m.completer.cycle(msg.String() == "ctrl+n")
+		}
+		return m, nil
+	case "ctrl+t":
+		if len(m.templates) == 0 {
+			return m, nil
+		}
+		return m.cycleTemplate().syncFocus(), nil
 	case "ctrl+g":
-		if m.inputTitle() == "" && m.inputPrompt() == "" {
+		if m.inputTitle() == "" && m.inputPrompt() == "" && m.selectedTemplate() == nil {
+			return m, nil
+		}
+		if m.err = m.validateForm(); m.err != nil {
 			return m, nil
 		}
 		m.step = stepGenerating
@@ -154,6 +194,9 @@ func (m createModel) updateInput(msg tea.KeyMsg) (createModel, tea.Cmd) {
 		if m.inputTitle() == "" {
 			return m, nil
 		}
+		if m.err = m.validateForm(); m.err != nil {
+			return m, nil
+		}
 		m.step = stepCreating
 		m.assignees = parseCSVInput(m.inputs[fieldAssignees].Value())
 		return m, tea.Batch(m.createDirect(), m.spinner.Tick)
@@ -191,6 +234,7 @@ func (m createModel) updateInputFields(msg tea.Msg) (createModel, tea.Cmd) {
 		m.inputs[i], cmd = m.inputs[i].Update(msg)
 		cmds = append(cmds, cmd)
 	}
+	m.completer.update(m.inputs[fieldAssignees].Value())
 	return m, tea.Batch(cmds...)
 }
 
@@ -203,13 +247,77 @@ func (m createModel) inputPrompt() string {
 }
 
 func (m createModel) buildInput() service.IssueInput {
-	return service.IssueInput{
+	input := service.IssueInput{
 		Title:       m.inputTitle(),
 		Prompt:      m.inputPrompt(),
 		Description: m.inputPrompt(),
 		Labels:      parseCSVInput(m.inputs[fieldLabels].Value()),
 		Assignees:   parseCSVInput(m.inputs[fieldAssignees].Value()),
 	}
+
+	t := m.selectedTemplate()
+	if t != nil && t.Form {
+		input.Description = t.RenderForm(m.formAnswers())
+	}
+	return service.ApplyTemplate(input, t)
+}
+
+func (m createModel) selectedTemplate() *service.IssueTemplate {
+	if m.template < 0 || m.template >= len(m.templates) {
+		return nil
+	}
+	return &m.templates[m.template]
+}
+
+// cycleTemplate selects the next template, wrapping around to none. The
+// inputs of a form's fields follow the standard inputs.
+func (m createModel) cycleTemplate() createModel {
+	m.template++
+	if m.template >= len(m.templates) {
+		m.template = -1
+	}
+
+	m.inputs = m.inputs[:fieldCount]
+	m.formFields = nil
+	if t := m.selectedTemplate(); t != nil && t.Form {
+		for _, f := range t.Inputs() {
+			placeholder := f.Placeholder
+			if len(f.Options) > 0 {
+				placeholder = strings.Join(f.OptionLabels(), ", ")
+			}
+			ti := newInput(placeholder, 1024)
+			ti.SetValue(f.Value)
+			m.inputs = append(m.inputs, ti)
+			m.formFields = append(m.formFields, f)
+		}
+	}
+	if m.focusIndex >= len(m.inputs) {
+		m.focusIndex = fieldTitle
+	}
+	return m
+}
+
+func (m createModel) formAnswers() map[string][]string {
+	answers := make(map[string][]string, len(m.formFields))
+	for i, f := range m.formFields {
+		value := m.inputs[fieldCount+i].Value()
+		if f.Type == service.FormDropdown || f.Type == service.FormCheckboxes {
+			answers[f.Key()] = parseCSVInput(value)
+		} else if value = strings.TrimSpace(value); value != "" {
+			answers[f.Key()] = []string{value}
+		}
+	}
+	return answers
+}
+
+func (m createModel) validateForm() error {
+	answers := m.formAnswers()
+	for _, f := range m.formFields {
+		if err := f.Validate(answers[f.Key()]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m createModel) generate(enhance bool) tea.Cmd {
@@ -235,7 +343,11 @@ func (m createModel) createDirect() tea.Cmd {
 		if err != nil {
 			return errMsg{err: err}
 		}
-		created, err := svc.CreateIssue(context.Background(), generated, assignees)
+		assignees, err := resolveAssignees(context.Background(), deps, assignees)
+		if err != nil {
+			return errMsg{err: err}
+		}
+		created, err := svc.CreateIssue(context.Background(), generated, service.CreateOptions{Assignees: assignees})
 		if err != nil {
 			return errMsg{err: err}
 		}
@@ -248,8 +360,12 @@ func (m createModel) createFromGenerated() tea.Cmd {
 	deps := m.deps
 	assignees := m.assignees
 	return func() tea.Msg {
+		assignees, err := resolveAssignees(context.Background(), deps, assignees)
+		if err != nil {
+			return errMsg{err: err}
+		}
 		svc := deps.IssueService()
-		created, err := svc.CreateIssue(context.Background(), generated, assignees)
+		created, err := svc.CreateIssue(context.Background(), generated, service.CreateOptions{Assignees: assignees})
 		if err != nil {
 			return errMsg{err: err}
 		}
@@ -289,7 +405,25 @@ func (m createModel) View() string {
 func (m createModel) viewInput() string {
 	var b strings.Builder
 
+	b.WriteString(dimStyle.Render("  Template: "))
+	switch t := m.selectedTemplate(); {
+	case t != nil:
+		b.WriteString(labelStyle.Render(t.Name))
+	case len(m.templates) > 0:
+		b.WriteString("none")
+	default:
+		b.WriteString(dimStyle.Render("none available"))
+	}
+	b.WriteString("\n\n")
+
 	labels := []string{"  Title:", "  Prompt:", "  Labels:", "  Assignees:"}
+	for _, f := range m.formFields {
+		label := "  " + f.Label + ":"
+		if f.Required {
+			label += " *"
+		}
+		labels = append(labels, label)
+	}
 	for i, label := range labels {
 		style := dimStyle
 		if i == m.focusIndex {
@@ -298,11 +432,17 @@ func (m createModel) viewInput() string {
 		b.WriteString(style.Render(label))
 		b.WriteString("\n")
 		b.WriteString("  " + m.inputs[i].View())
-		b.WriteString("\n\n")
+		b.WriteString("\n")
+		if i == fieldAssignees && i == m.focusIndex {
+			if completions := m.completer.View(); completions != "" {
+				b.WriteString("  " + completions + dimStyle.Render("  tab complete · ctrl+n/ctrl+p choose") + "\n")
+			}
+		}
+		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("  tab/shift+tab navigate · ctrl+g generate with LLM · ctrl+s submit · esc cancel"))
+	b.WriteString(helpStyle.Render("  tab/shift+tab navigate · ctrl+t template · ctrl+g generate with LLM · ctrl+s submit · esc cancel"))
 
 	return b.String()
 }
@@ -343,7 +483,7 @@ func (m createModel) viewReview() string {
 func (m createModel) viewDone() string {
 	var b strings.Builder
 
-	b.WriteString(successStyle.Render(fmt.Sprintf("  Issue #%d created", m.created.Number)))
+	b.WriteString(successStyle.Render(fmt.Sprintf("  Issue %s created", offline.FormatNumber(m.created.Number))))
 	b.WriteString("\n\n")
 	b.WriteString("  " + m.created.Title)
 	b.WriteString("\n")
@@ -11,6 +11,8 @@ type Dependencies struct {
 	Config       *config.Config
 	GitHubClient github.Client
 	LLMClient    llm.Client
+	// Root is the project root, used to find local issue templates.
+	Root string
 }
 
 func (d Dependencies) IssueService() *service.IssueService {
@@ -20,3 +22,11 @@ func (d Dependencies) IssueService() *service.IssueService {
 func (d Dependencies) IssueServiceWithoutLLM() *service.IssueService {
 	return service.NewIssueService(d.GitHubClient, nil, d.Config)
 }
+
+func (d Dependencies) TemplateService() *service.TemplateService {
+	return service.NewTemplateService(d.GitHubClient, d.Root)
+}
+
+func (d Dependencies) AssigneeService() *service.AssigneeService {
+	return service.NewAssigneeService(d.GitHubClient, d.Config)
+}
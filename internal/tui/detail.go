@@ -13,19 +13,37 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/offline"
+	"github.com/dulait/grit/internal/service"
+)
+
+type detailTab int
+
+const (
+	tabConversation detailTab = iota
+	tabTimeline
 )
 
 type detailModel struct {
-	deps        Dependencies
-	issueNumber int
-	issue       *github.Issue
-	loading     bool
-	spinner     spinner.Model
-	viewport    viewport.Model
-	ready       bool
-	err         error
-	width       int
-	height      int
+	deps           Dependencies
+	issueNumber    int
+	issue          *github.Issue
+	comments       []github.IssueComment
+	commentsErr    error
+	commentsLoaded bool
+	pullRequests   []github.LinkedPullRequest
+	pullsErr       error
+	tab            detailTab
+	history        []service.HistoryEntry
+	historyErr     error
+	historyLoaded  bool
+	loading        bool
+	spinner        spinner.Model
+	viewport       viewport.Model
+	ready          bool
+	err            error
+	width          int
+	height         int
 }
 
 func newDetailModel(deps Dependencies, issueNumber int) detailModel {
@@ -42,7 +60,37 @@ func newDetailModel(deps Dependencies, issueNumber int) detailModel {
 }
 
 func (m detailModel) Init() tea.Cmd {
-	return tea.Batch(m.fetchIssue(), m.spinner.Tick)
+	return tea.Batch(m.fetchIssue(), m.fetchComments(), m.fetchPullRequests(), m.spinner.Tick)
+}
+
+func (m detailModel) fetchPullRequests() tea.Cmd {
+	number := m.issueNumber
+	deps := m.deps
+	return func() tea.Msg {
+		prs, err := deps.IssueServiceWithoutLLM().LinkedPullRequests(context.Background(), number)
+		return pullRequestsLoadedMsg{pullRequests: prs, err: err}
+	}
+}
+
+func (m detailModel) fetchComments() tea.Cmd {
+	number := m.issueNumber
+	deps := m.deps
+	return func() tea.Msg {
+		comments, err := deps.IssueServiceWithoutLLM().ListComments(context.Background(), number)
+		if err != nil {
+			return commentsLoadedMsg{comments: nil, err: err}
+		}
+		return commentsLoadedMsg{comments: comments}
+	}
+}
+
+func (m detailModel) fetchHistory() tea.Cmd {
+	number := m.issueNumber
+	deps := m.deps
+	return func() tea.Msg {
+		entries, err := deps.IssueServiceWithoutLLM().History(context.Background(), number)
+		return historyLoadedMsg{entries: entries, err: err}
+	}
 }
 
 func (m detailModel) fetchIssue() tea.Cmd {
@@ -61,7 +109,7 @@ func (m detailModel) Update(msg tea.Msg) (detailModel, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		headerHeight := 8
+		headerHeight := 9
 		footerHeight := 2
 		if m.ready {
 			m.viewport.Width = msg.Width
@@ -79,10 +127,33 @@ func (m detailModel) Update(msg tea.Msg) (detailModel, tea.Cmd) {
 		m.issue = msg.issue
 		m.loading = false
 		m.err = nil
-		m.viewport = viewport.New(m.width, m.height-10)
-		m.viewport.SetContent(m.renderBody())
+		m.viewport = viewport.New(m.width, m.height-11)
+		m.viewport.SetContent(m.renderContent())
 		m.ready = true
 
+	case commentsLoadedMsg:
+		m.comments = msg.comments
+		m.commentsErr = msg.err
+		m.commentsLoaded = true
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case pullRequestsLoadedMsg:
+		m.pullRequests = msg.pullRequests
+		m.pullsErr = msg.err
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case historyLoadedMsg:
+		m.history = msg.entries
+		m.historyErr = msg.err
+		m.historyLoaded = true
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+		}
+
 	case errMsg:
 		m.err = msg.err
 		m.loading = false
@@ -100,6 +171,21 @@ func (m detailModel) Update(msg tea.Msg) (detailModel, tea.Cmd) {
 				openBrowser(m.issue.HTMLURL)
 			}
 			return m, nil
+		case key.Matches(msg, detailKeys.Timeline):
+			if !m.ready {
+				return m, nil
+			}
+			if m.tab == tabTimeline {
+				m.tab = tabConversation
+			} else {
+				m.tab = tabTimeline
+			}
+			m.viewport.SetContent(m.renderContent())
+			m.viewport.GotoTop()
+			if m.tab == tabTimeline && !m.historyLoaded {
+				return m, m.fetchHistory()
+			}
+			return m, nil
 		case key.Matches(msg, detailKeys.HalfPageUp):
 			m.viewport.HalfViewUp()
 			return m, nil
@@ -149,7 +235,7 @@ func (m detailModel) Update(msg tea.Msg) (detailModel, tea.Cmd) {
 func (m detailModel) View() string {
 	var b strings.Builder
 
-	header := headerStyle.Width(m.width).Render(fmt.Sprintf(" grit · Issue #%d", m.issueNumber))
+	header := headerStyle.Width(m.width).Render(fmt.Sprintf(" grit · Issue %s", offline.FormatNumber(m.issueNumber)))
 	b.WriteString(header)
 	b.WriteString("\n")
 
@@ -174,6 +260,8 @@ func (m detailModel) View() string {
 	b.WriteString("\n\n")
 	b.WriteString(m.renderMetadata())
 	b.WriteString("\n")
+	b.WriteString(m.renderTabs())
+	b.WriteString("\n")
 	b.WriteString(strings.Repeat("─", m.width))
 	b.WriteString("\n")
 
@@ -182,7 +270,7 @@ func (m detailModel) View() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("  j/k scroll · e edit · x close · a assign · m comment · o browser · esc/h back · ? help"))
+	b.WriteString(helpStyle.Render("  j/k scroll · t timeline · e edit · x close · a assign · m comment · o browser · esc/h back · ? help"))
 
 	return b.String()
 }
@@ -215,16 +303,144 @@ func (m detailModel) renderMetadata() string {
 		parts = append(parts, fmt.Sprintf("  Assignees: %s", assigneeStyle.Render(strings.Join(names, ", "))))
 	}
 
+	if issue.Milestone != nil {
+		parts = append(parts, fmt.Sprintf("  Milestone: %s", milestoneStyle.Render(formatMilestone(issue.Milestone))))
+	}
+
 	parts = append(parts, fmt.Sprintf("  URL: %s", dimStyle.Render(issue.HTMLURL)))
 
 	return strings.Join(parts, "\n")
 }
 
+func (m detailModel) renderTabs() string {
+	tabs := []struct {
+		tab   detailTab
+		title string
+	}{
+		{tabConversation, "Conversation"},
+		{tabTimeline, "Timeline"},
+	}
+
+	parts := make([]string, len(tabs))
+	for i, t := range tabs {
+		if t.tab == m.tab {
+			parts[i] = titleStyle.Render(t.title)
+		} else {
+			parts[i] = dimStyle.Render(t.title)
+		}
+	}
+	return "  " + strings.Join(parts, dimStyle.Render(" │ "))
+}
+
+func (m detailModel) renderContent() string {
+	if m.tab == tabTimeline {
+		return m.renderTimeline()
+	}
+	return m.renderBody()
+}
+
+func (m detailModel) renderTimeline() string {
+	if m.historyErr != nil {
+		return errorStyle.Render(fmt.Sprintf("  Could not load timeline: %v", m.historyErr))
+	}
+	if !m.historyLoaded {
+		return dimStyle.Render("  Loading timeline...")
+	}
+	if len(m.history) == 0 {
+		return dimStyle.Render("  No events yet.")
+	}
+
+	var b strings.Builder
+	for _, e := range m.history {
+		b.WriteString("  " + dimStyle.Render(e.Time.Format("2006-01-02 15:04")))
+		if e.Actor != "" {
+			b.WriteString("  " + assigneeStyle.Render("@"+e.Actor))
+		}
+		b.WriteString("  " + e.Text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 func (m detailModel) renderBody() string {
+	var b strings.Builder
+	if prs := m.renderPullRequests(); prs != "" {
+		b.WriteString(prs)
+		b.WriteString("\n")
+	}
 	if m.issue.Body == "" {
-		return dimStyle.Render("  No description provided.")
+		b.WriteString(dimStyle.Render("  No description provided."))
+	} else {
+		b.WriteString("  " + strings.ReplaceAll(m.issue.Body, "\n", "\n  "))
 	}
-	return "  " + strings.ReplaceAll(m.issue.Body, "\n", "\n  ")
+	b.WriteString("\n\n")
+	b.WriteString(m.renderComments())
+	return b.String()
+}
+
+// renderPullRequests lists the pull requests that close or mention the
+// issue. It is empty when there are none, or for a pull request itself.
+func (m detailModel) renderPullRequests() string {
+	if m.issue.IsPullRequest() {
+		return ""
+	}
+	if m.pullsErr != nil {
+		return dimStyle.Render(fmt.Sprintf("  Could not load linked pull requests: %v", m.pullsErr)) + "\n"
+	}
+	if len(m.pullRequests) == 0 {
+		return ""
+	}
+
+	repo := m.deps.Config.Project.Owner + "/" + m.deps.Config.Project.Repo
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("  Pull requests (%d)", len(m.pullRequests))))
+	b.WriteString("\n")
+	for _, pr := range m.pullRequests {
+		ref := fmt.Sprintf("#%d", pr.Number)
+		if !strings.EqualFold(pr.Repository, repo) {
+			ref = pr.Repository + ref
+		}
+		b.WriteString("  " + pullRequestStateStyle(pr.State).Render(fmt.Sprintf("%-7s", strings.ToLower(pr.State))))
+		b.WriteString("  " + ref + " " + pr.Title)
+		if pr.Closes {
+			b.WriteString(dimStyle.Render(" · closes this issue"))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func pullRequestStateStyle(state string) lipgloss.Style {
+	switch state {
+	case github.PullRequestOpen:
+		return stateOpenStyle
+	case github.PullRequestMerged:
+		return mergedStyle
+	default:
+		return stateClosedStyle
+	}
+}
+
+func (m detailModel) renderComments() string {
+	if m.commentsErr != nil {
+		return errorStyle.Render(fmt.Sprintf("  Could not load comments: %v", m.commentsErr))
+	}
+	if !m.commentsLoaded {
+		return dimStyle.Render("  Loading comments...")
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("  Comments (%d)", len(m.comments))))
+	b.WriteString("\n")
+	for _, c := range m.comments {
+		b.WriteString("\n")
+		b.WriteString("  " + assigneeStyle.Render("@"+c.User.Login))
+		b.WriteString(dimStyle.Render(" · " + c.CreatedAt.Format("2006-01-02 15:04")))
+		b.WriteString("\n")
+		b.WriteString("  " + strings.ReplaceAll(c.Body, "\n", "\n  "))
+		b.WriteString("\n")
+	}
+	return b.String()
 }
 
 func openBrowser(url string) {
@@ -10,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/offline"
 	"github.com/dulait/grit/internal/service"
 )
 
@@ -38,6 +39,7 @@ type editModel struct {
 	step        editStep
 	inputs      []textinput.Model
 	focusIndex  int
+	completer   assigneeCompleter
 	spinner     spinner.Model
 	updated     *github.Issue
 	err         error
@@ -78,7 +80,7 @@ func newEditInput(placeholder string, charLimit int) textinput.Model {
 }
 
 func (m editModel) Init() tea.Cmd {
-	return tea.Batch(m.fetchIssue(), m.spinner.Tick)
+	return tea.Batch(m.fetchIssue(), m.spinner.Tick, loadAssignees(m.deps))
 }
 
 func (m editModel) fetchIssue() tea.Cmd {
@@ -125,6 +127,10 @@ func (m editModel) Update(msg tea.Msg) (editModel, tea.Cmd) {
 			return m, cmd
 		}
 
+	case assigneesLoadedMsg:
+		m.completer.users = msg.users
+		return m, nil
+
 	case issueDetailLoadedMsg:
 		m.original = msg.issue
 		m.populateInputs()
@@ -178,11 +184,19 @@ func (m editModel) updateInput(msg tea.KeyMsg) (editModel, tea.Cmd) {
 			return navigateToDetailMsg{issueNumber: m.issueNumber}
 		}
 	case "tab", "down":
+		if msg.String() == "tab" && m.focusIndex == editFieldAssignees && m.completer.complete(&m.inputs[editFieldAssignees]) {
+			return m, nil
+		}
 		m.focusIndex = (m.focusIndex + 1) % editFieldCount
 		return m.syncFocus(), nil
 	case "shift+tab", "up":
 		m.focusIndex = (m.focusIndex - 1 + editFieldCount) % editFieldCount
 		return m.syncFocus(), nil
+	case "ctrl+n", "ctrl+p":
+		if m.focusIndex == editFieldAssignees {
+			m.completer.cycle(msg.String() == "ctrl+n")
+		}
+		return m, nil
 	case "ctrl+s":
 		m.step = editSaving
 		return m, tea.Batch(m.save(), m.spinner.Tick)
@@ -206,6 +220,7 @@ func (m editModel) updateInputFields(msg tea.Msg) (editModel, tea.Cmd) {
 		m.inputs[i], cmd = m.inputs[i].Update(msg)
 		cmds = append(cmds, cmd)
 	}
+	m.completer.update(m.inputs[editFieldAssignees].Value())
 	return m, tea.Batch(cmds...)
 }
 
@@ -252,6 +267,13 @@ func (m editModel) save() tea.Cmd {
 	}
 
 	return func() tea.Msg {
+		if input.SetAssignees {
+			assignees, err := resolveAssignees(context.Background(), deps, input.Assignees)
+			if err != nil {
+				return errMsg{err: err}
+			}
+			input.Assignees = assignees
+		}
 		svc := deps.IssueServiceWithoutLLM()
 		issue, err := svc.EditIssue(context.Background(), number, input)
 		if err != nil {
@@ -276,7 +298,7 @@ func slicesEqual(a, b []string) bool {
 func (m editModel) View() string {
 	var b strings.Builder
 
-	header := headerStyle.Width(m.width).Render(fmt.Sprintf(" grit · Edit Issue #%d", m.issueNumber))
+	header := headerStyle.Width(m.width).Render(fmt.Sprintf(" grit · Edit Issue %s", offline.FormatNumber(m.issueNumber)))
 	b.WriteString(header)
 	b.WriteString("\n\n")
 
@@ -312,7 +334,13 @@ func (m editModel) viewInput() string {
 		b.WriteString(style.Render(label))
 		b.WriteString("\n")
 		b.WriteString("  " + m.inputs[i].View())
-		b.WriteString("\n\n")
+		b.WriteString("\n")
+		if i == editFieldAssignees && i == m.focusIndex {
+			if completions := m.completer.View(); completions != "" {
+				b.WriteString("  " + completions + dimStyle.Render("  tab complete · ctrl+n/ctrl+p choose") + "\n")
+			}
+		}
+		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
@@ -324,7 +352,7 @@ func (m editModel) viewInput() string {
 func (m editModel) viewDone() string {
 	var b strings.Builder
 
-	b.WriteString(successStyle.Render(fmt.Sprintf("  Issue #%d updated", m.updated.Number)))
+	b.WriteString(successStyle.Render(fmt.Sprintf("  Issue %s updated", offline.FormatNumber(m.updated.Number))))
 	b.WriteString("\n\n")
 	b.WriteString("  " + m.updated.Title)
 	b.WriteString("\n")
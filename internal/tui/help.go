@@ -27,6 +27,7 @@ var listHelpBindings = []helpBinding{
 var detailHelpBindings = []helpBinding{
 	{"j/k", "scroll up/down"},
 	{"ctrl+u/d", "half page up/down"},
+	{"t", "toggle timeline"},
 	{"e", "edit issue"},
 	{"x", "close issue"},
 	{"a", "assign users"},
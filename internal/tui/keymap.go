@@ -45,6 +45,7 @@ type detailKeyMap struct {
 	Assign      key.Binding
 	Comment     key.Binding
 	Edit        key.Binding
+	Timeline    key.Binding
 	Help        key.Binding
 	Quit        key.Binding
 }
@@ -60,6 +61,7 @@ var detailKeys = detailKeyMap{
 	Assign:      key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "assign")),
 	Comment:     key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "comment")),
 	Edit:        key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
+	Timeline:    key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "toggle timeline")),
 	Help:        key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
 	Quit:        key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
 }
@@ -12,6 +12,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dulait/grit/internal/github"
+	"github.com/dulait/grit/internal/offline"
 )
 
 type listModel struct {
@@ -20,6 +21,7 @@ type listModel struct {
 	cursor      int
 	offset      int
 	page        int
+	hasNext     bool
 	perPage     int
 	state       string
 	loading     bool
@@ -66,11 +68,11 @@ func (m listModel) fetchIssues() tea.Cmd {
 			PerPage: m.perPage,
 			Page:    m.page,
 		}
-		issues, err := svc.ListIssues(context.Background(), req)
+		page, err := svc.ListIssues(context.Background(), req)
 		if err != nil {
 			return errMsg{err: err}
 		}
-		return issuesLoadedMsg{issues: issues, page: m.page}
+		return issuesLoadedMsg{issues: page.Issues, page: m.page, hasNext: page.HasNext}
 	}
 }
 
@@ -94,7 +96,7 @@ func (m listModel) searchIssues() tea.Cmd {
 		if err != nil {
 			return errMsg{err: err}
 		}
-		return searchResultsMsg{issues: resp.Items, totalCount: resp.TotalCount, page: m.page}
+		return searchResultsMsg{issues: resp.Items, totalCount: resp.TotalCount, page: m.page, hasNext: resp.HasNext}
 	}
 }
 
@@ -138,10 +140,7 @@ func (m listModel) updateSearchInput(msg tea.Msg) (listModel, tea.Cmd) {
 }
 
 func (m listModel) hasNextPage() bool {
-	if m.searchQuery != "" {
-		return m.page*m.perPage < m.totalCount
-	}
-	return len(m.issues) == m.perPage
+	return m.hasNext
 }
 
 func (m listModel) Update(msg tea.Msg) (listModel, tea.Cmd) {
@@ -167,6 +166,7 @@ func (m listModel) Update(msg tea.Msg) (listModel, tea.Cmd) {
 	case issuesLoadedMsg:
 		m.issues = msg.issues
 		m.page = msg.page
+		m.hasNext = msg.hasNext
 		m.loading = false
 		m.cursor = 0
 		m.offset = 0
@@ -176,6 +176,7 @@ func (m listModel) Update(msg tea.Msg) (listModel, tea.Cmd) {
 		m.issues = msg.issues
 		m.page = msg.page
 		m.totalCount = msg.totalCount
+		m.hasNext = msg.hasNext
 		m.loading = false
 		m.cursor = 0
 		m.offset = 0
@@ -338,7 +339,7 @@ func (m listModel) View() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(renderStatusBar(repo, m.state, m.page, len(m.issues), m.width))
+	b.WriteString(renderStatusBar(repo, m.state, m.page, len(m.issues), m.deps.GitHubClient.RateLimit(), m.width))
 	b.WriteString("\n")
 	b.WriteString(helpStyle.Render(m.helpText()))
 
@@ -356,7 +357,7 @@ func (m listModel) helpText() string {
 }
 
 func (m listModel) renderIssueRow(index int, issue github.Issue) string {
-	number := fmt.Sprintf("#%-4d", issue.Number)
+	number := fmt.Sprintf("%-5s", offline.FormatNumber(issue.Number))
 
 	maxTitle := m.width - 30
 	if maxTitle < 20 {
@@ -390,6 +391,9 @@ func (m listModel) renderIssueRow(index int, issue github.Issue) string {
 	}
 
 	parts := []string{number, title, state}
+	if issue.Milestone != nil {
+		parts = append(parts, milestoneStyle.Render(formatMilestone(issue.Milestone)))
+	}
 	if labels != "" {
 		parts = append(parts, labels)
 	}
@@ -425,6 +429,13 @@ func (m *listModel) adjustOffset() {
 	}
 }
 
+func formatMilestone(m *github.Milestone) string {
+	if m.DueOn != nil {
+		return fmt.Sprintf("%s (due %s)", m.Title, m.DueOn.Format("2006-01-02"))
+	}
+	return m.Title
+}
+
 func truncateStr(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
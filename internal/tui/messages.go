@@ -3,17 +3,39 @@ package tui
 import (
 	"github.com/dulait/grit/internal/github"
 	"github.com/dulait/grit/internal/llm"
+	"github.com/dulait/grit/internal/service"
 )
 
 type issuesLoadedMsg struct {
-	issues []github.Issue
-	page   int
+	issues  []github.Issue
+	page    int
+	hasNext bool
 }
 
 type issueDetailLoadedMsg struct {
 	issue *github.Issue
 }
 
+type commentsLoadedMsg struct {
+	comments []github.IssueComment
+	err      error
+}
+
+type pullRequestsLoadedMsg struct {
+	pullRequests []github.LinkedPullRequest
+	err          error
+}
+
+type historyLoadedMsg struct {
+	entries []service.HistoryEntry
+	err     error
+}
+
+type templatesLoadedMsg struct {
+	templates []service.IssueTemplate
+	err       error
+}
+
 type navigateToDetailMsg struct {
 	issueNumber int
 }
@@ -55,6 +77,7 @@ type searchResultsMsg struct {
 	issues     []github.Issue
 	totalCount int
 	page       int
+	hasNext    bool
 }
 
 type searchTickMsg struct {
@@ -4,11 +4,15 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dulait/grit/internal/github"
 )
 
-func renderStatusBar(repo string, state string, page int, count int, width int) string {
+func renderStatusBar(repo string, state string, page int, count int, rl github.RateLimit, width int) string {
 	left := fmt.Sprintf(" %s · %s", repo, state)
 	right := fmt.Sprintf("Page %d · %d issues ", page, count)
+	if rl.Known() {
+		right = fmt.Sprintf("API %d/%d · %s", rl.Remaining, rl.Limit, right)
+	}
 
 	gap := width - lipgloss.Width(left) - lipgloss.Width(right) - statusBarStyle.GetHorizontalPadding()
 	if gap < 0 {
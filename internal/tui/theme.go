@@ -10,6 +10,8 @@ var (
 	stateClosedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
 	labelStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
 	assigneeStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("117"))
+	milestoneStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+	mergedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("135"))
 	errorStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
 	statusBarStyle   = lipgloss.NewStyle().Background(lipgloss.Color("236")).Padding(0, 1)
 	helpStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))